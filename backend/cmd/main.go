@@ -5,13 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"koditon-go/internal/alerts"
+	"koditon-go/internal/buildings"
 	"koditon-go/internal/config"
 	"koditon-go/internal/consumers"
 	"koditon-go/internal/frontdoor"
 	"koditon-go/internal/prices"
 	"koditon-go/internal/server"
 	"koditon-go/internal/shortcut"
+	"koditon-go/internal/sources"
 	"koditon-go/internal/taskqueue"
+	"koditon-go/internal/webhooks"
 	"log/slog"
 	"net"
 	"net/http"
@@ -63,9 +67,11 @@ func run(
 	}
 	appLogger.Debug("database connection established")
 	taskQueueClient := taskqueue.NewClient(pool)
+	limiters := server.NewLimiters(cfg)
 	pricesService, err := prices.NewService(
 		pool,
 		cfg.Prices.BaseURL,
+		limiters.Prices,
 	)
 	if err != nil {
 		return fmt.Errorf("create prices service: %w", err)
@@ -78,26 +84,46 @@ func run(
 		cfg.Shortcut.AdBaseURL,
 		cfg.Shortcut.UserAgent,
 		cfg.Shortcut.SitemapBase,
+		limiters.Shortcut,
+		cfg.Shortcut.TokenPool.ClientConfig(),
 	)
 	frontdoorService := frontdoor.NewService(
 		pool,
+		logger,
 		cfg.Frontdoor.BaseURL,
 		cfg.Frontdoor.UserAgent,
 		cfg.Frontdoor.Cookie,
 		cfg.Frontdoor.SitemapBase,
+		limiters.Frontdoor,
 	)
+	buildingsService := buildings.NewService(pool)
+	webhooksService := webhooks.NewService(pool)
+	alertsService := alerts.NewService(pool, alerts.NewWebhookNotifier(webhooksService))
 	consumer := consumers.New(
 		logger,
 		taskQueueClient,
-		pricesService,
-		shortcutService,
-		frontdoorService,
+		buildingsService,
+		alertsService,
+		webhooksService,
 	)
-	consumerConfig := consumers.DefaultConfig()
+	shortcutSource := shortcut.NewSource(shortcutService, taskQueueClient, consumer)
+	for _, source := range []sources.Source{
+		frontdoor.NewSource(frontdoorService, taskQueueClient, consumer),
+		shortcutSource,
+		prices.NewSource(pricesService, taskQueueClient),
+	} {
+		if err := consumer.Register(source); err != nil {
+			return fmt.Errorf("register %s source: %w", source.Name(), err)
+		}
+	}
+	if err := shortcutSource.RegisterSearchAreas(ctx, cfg.Shortcut.SearchPostcodes); err != nil {
+		return fmt.Errorf("register shortcut search areas: %w", err)
+	}
+	consumerConfig := consumers.NewConfig(cfg.Workers)
 	if err := consumer.Start(ctx, consumerConfig, pool); err != nil {
 		return fmt.Errorf("start consumer: %w", err)
 	}
-	srv := server.New(logger, cfg, pool, taskQueueClient)
+	srv := server.New(logger, cfg, pool, taskQueueClient, consumer, consumer.Registry(), limiters)
 	mux := http.NewServeMux()
 	api := humago.New(mux, huma.DefaultConfig("Koditon API", "0.1.0"))
 	httpServer := &http.Server{
@@ -0,0 +1,48 @@
+CREATE INDEX idx_prices_transactions_neighborhood_price_per_sqm
+    ON public.prices_transactions(prices_neighborhoods_id, prices_transactions_price_per_square_meter, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_price_per_sqm
+    ON public.prices_transactions(prices_transactions_price_per_square_meter, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_price
+    ON public.prices_transactions(prices_transactions_price, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_build_year
+    ON public.prices_transactions(prices_transactions_build_year, prices_transactions_id);
+
+-- Transactions with the names of their neighborhood, postal code and city.
+CREATE VIEW public.vw_prices_transactions AS
+SELECT
+    ht.prices_transactions_id,
+    ht.prices_transactions_description,
+    ht.prices_transactions_type,
+    ht.prices_transactions_area,
+    ht.prices_transactions_price,
+    ht.prices_transactions_price_per_square_meter,
+    ht.prices_transactions_build_year,
+    ht.prices_transactions_floor,
+    ht.prices_transactions_elevator,
+    ht.prices_transactions_condition,
+    ht.prices_transactions_plot,
+    ht.prices_transactions_energy_class,
+    ht.prices_transactions_period_identifier,
+    ht.prices_transactions_category,
+    ht.prices_neighborhoods_id,
+    hn.prices_neighborhoods_name,
+    hp.prices_postal_codes_code,
+    hc.prices_cities_name
+FROM public.prices_transactions AS ht
+LEFT JOIN public.prices_neighborhoods AS hn
+    ON ht.prices_neighborhoods_id = hn.prices_neighborhoods_id
+LEFT JOIN public.prices_postal_codes AS hp
+    ON hn.prices_neighborhoods_postal_code_id = hp.prices_postal_codes_id
+LEFT JOIN public.prices_cities AS hc
+    ON hn.prices_neighborhoods_city_id = hc.prices_cities_id;
+
+---- create above / drop below ----
+
+DROP VIEW IF EXISTS public.vw_prices_transactions;
+DROP INDEX IF EXISTS public.idx_prices_transactions_build_year;
+DROP INDEX IF EXISTS public.idx_prices_transactions_price;
+DROP INDEX IF EXISTS public.idx_prices_transactions_price_per_sqm;
+DROP INDEX IF EXISTS public.idx_prices_transactions_neighborhood_price_per_sqm;
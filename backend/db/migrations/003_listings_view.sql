@@ -0,0 +1,148 @@
+-- Parses a price or area scraped as free text, e.g. '189 000 €' or '54,5'.
+CREATE OR REPLACE FUNCTION public.fnc__parse_number(p_value TEXT) RETURNS FLOAT8 AS $$
+    SELECT CASE
+        WHEN cleaned ~ '^[0-9]+(\.[0-9]+)?$' THEN cleaned::FLOAT8
+    END
+    FROM (
+        SELECT replace(regexp_replace(p_value, '[^0-9,.]', '', 'g'), ',', '.') AS cleaned
+    ) c;
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE public.shortcut_buildings ADD COLUMN shortcut_buildings_postcode text;
+
+COMMENT ON COLUMN public.shortcut_buildings.shortcut_buildings_postcode IS 'Postcode from the building API, or from the ads of the building before the API sync';
+
+UPDATE public.shortcut_buildings sb
+SET shortcut_buildings_postcode = COALESCE(
+    (
+        SELECT s.shortcut_ads_data -> 'address' -> 'zipCode' ->> 'name'
+        FROM public.shortcut_ads s
+        WHERE s.shortcut_ads_building_id = sb.shortcut_buildings_id
+            AND s.shortcut_ads_data -> 'address' -> 'zipCode' ->> 'name' IS NOT NULL
+        ORDER BY s.shortcut_ads_last_seen_at DESC
+        LIMIT 1
+    ),
+    substring(sb.shortcut_buildings_address FROM '\m[0-9]{5}\M')
+);
+
+-- Normalizes frontdoor ads, frontdoor building announcements, shortcut ads and
+-- shortcut building listings into one listing shape.
+CREATE OR REPLACE VIEW public.vw_listings AS
+SELECT
+    'frontdoor:ad:' || a.frontdoor_ads_external_id AS listing_id,
+    'frontdoor'::TEXT AS source,
+    a.frontdoor_ads_external_id AS external_id,
+    a.frontdoor_ads_url AS url,
+    public.fnc__parse_number(a.frontdoor_ads_data ->> 'sellingPrice') AS price,
+    public.fnc__parse_number(a.frontdoor_ads_data ->> 'debfFreePrice') AS debt_free_price,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'livingArea') AS area,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'totalRoomCount')::INT4 AS rooms,
+    a.frontdoor_ads_data -> 'property' -> 'postCode' ->> 'postCode' AS postcode,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'property' -> 'geoCode' ->> 'latitude') AS latitude,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'property' -> 'geoCode' ->> 'longitude') AS longitude,
+    a.frontdoor_ads_first_seen_at AS first_seen_at,
+    a.frontdoor_ads_last_seen_at AS last_seen_at,
+    CASE WHEN a.frontdoor_ads_page_not_found THEN 'removed' ELSE 'active' END AS status
+FROM public.frontdoor_ads a
+WHERE a.frontdoor_ads_data IS NOT NULL OR a.frontdoor_ads_page_not_found
+UNION ALL
+SELECT
+    'frontdoor:announcement:' || ba.frontdoor_building_announcements_id::TEXT AS listing_id,
+    'frontdoor'::TEXT AS source,
+    COALESCE(ba.frontdoor_building_announcements_friendly_id, ba.frontdoor_building_announcements_external_id::TEXT) AS external_id,
+    NULL::TEXT AS url,
+    ba.frontdoor_building_announcements_search_price AS price,
+    ba.frontdoor_building_announcements_search_price AS debt_free_price,
+    ba.frontdoor_building_announcements_area AS area,
+    NULL::INT4 AS rooms,
+    b.frontdoor_buildings_postcode AS postcode,
+    b.frontdoor_buildings_latitude AS latitude,
+    b.frontdoor_buildings_longitude AS longitude,
+    ba.frontdoor_building_announcements_first_seen_at AS first_seen_at,
+    ba.frontdoor_building_announcements_last_seen_at AS last_seen_at,
+    CASE WHEN ba.frontdoor_building_announcements_published THEN 'active' ELSE 'removed' END AS status
+FROM public.frontdoor_building_announcements ba
+JOIN public.frontdoor_buildings b
+    ON b.frontdoor_buildings_id = ba.frontdoor_building_announcements_building_id
+-- announcements that were also synced as ads are listed once, as the ad
+WHERE NOT EXISTS (
+    SELECT 1 FROM public.frontdoor_ads a
+    WHERE a.frontdoor_ads_external_id = ba.frontdoor_building_announcements_friendly_id
+)
+UNION ALL
+SELECT
+    'shortcut:ad:' || s.shortcut_ads_id::TEXT AS listing_id,
+    'shortcut'::TEXT AS source,
+    s.shortcut_ads_id::TEXT AS external_id,
+    s.shortcut_ads_url AS url,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'price') AS price,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'debtFreePrice') AS debt_free_price,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'size') AS area,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'rooms')::INT4 AS rooms,
+    s.shortcut_ads_data -> 'address' -> 'zipCode' ->> 'name' AS postcode,
+    COALESCE(public.fnc__parse_number(s.shortcut_ads_data -> 'coordinates' ->> 'latitude'), sb.shortcut_buildings_latitude) AS latitude,
+    COALESCE(public.fnc__parse_number(s.shortcut_ads_data -> 'coordinates' ->> 'longitude'), sb.shortcut_buildings_longitude) AS longitude,
+    s.shortcut_ads_first_seen_at AS first_seen_at,
+    s.shortcut_ads_last_seen_at AS last_seen_at,
+    'active'::TEXT AS status
+FROM public.shortcut_ads s
+LEFT JOIN public.shortcut_buildings sb
+    ON sb.shortcut_buildings_id = s.shortcut_ads_building_id
+WHERE s.shortcut_ads_data IS NOT NULL
+UNION ALL
+SELECT
+    'shortcut:listing:' || l.shortcut_building_listings_id::TEXT AS listing_id,
+    'shortcut'::TEXT AS source,
+    l.shortcut_building_listings_id::TEXT AS external_id,
+    sb.shortcut_buildings_url AS url,
+    l.shortcut_building_listings_price AS price,
+    l.shortcut_building_listings_price AS debt_free_price,
+    l.shortcut_building_listings_size AS area,
+    NULL::INT4 AS rooms,
+    sb.shortcut_buildings_postcode AS postcode,
+    sb.shortcut_buildings_latitude AS latitude,
+    sb.shortcut_buildings_longitude AS longitude,
+    l.shortcut_building_listings_created_at AS first_seen_at,
+    l.shortcut_building_listings_updated_at AS last_seen_at,
+    CASE WHEN l.shortcut_building_listings_deleted_at IS NULL THEN 'active' ELSE 'removed' END AS status
+FROM public.shortcut_building_listings l
+JOIN public.shortcut_buildings sb
+    ON sb.shortcut_buildings_id = l.shortcut_building_listings_building_id;
+
+COMMENT ON VIEW public.vw_listings IS
+'Frontdoor and shortcut listings normalized into one shape for the listings API. Announcements and building listings only carry the debt-free search price, which is reported as both price and debt_free_price.';
+
+-- The view is filtered on these expressions and paged by first seen time, so
+-- every branch can use an index.
+CREATE INDEX idx_frontdoor_ads_postcode ON public.frontdoor_ads((frontdoor_ads_data -> 'property' -> 'postCode' ->> 'postCode'));
+CREATE INDEX idx_frontdoor_ads_price ON public.frontdoor_ads(public.fnc__parse_number(frontdoor_ads_data ->> 'sellingPrice'));
+CREATE INDEX idx_frontdoor_ads_area ON public.frontdoor_ads(public.fnc__parse_number(frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'livingArea'));
+CREATE INDEX idx_frontdoor_ads_first_seen_at ON public.frontdoor_ads(frontdoor_ads_first_seen_at);
+CREATE INDEX idx_frontdoor_buildings_postcode ON public.frontdoor_buildings(frontdoor_buildings_postcode);
+CREATE INDEX idx_frontdoor_building_announcements_search_price ON public.frontdoor_building_announcements(frontdoor_building_announcements_search_price);
+CREATE INDEX idx_frontdoor_building_announcements_first_seen_at ON public.frontdoor_building_announcements(frontdoor_building_announcements_first_seen_at);
+CREATE INDEX idx_shortcut_ads_price ON public.shortcut_ads(public.fnc__parse_number(shortcut_ads_data ->> 'price'));
+CREATE INDEX idx_shortcut_ads_area ON public.shortcut_ads(public.fnc__parse_number(shortcut_ads_data ->> 'size'));
+CREATE INDEX idx_shortcut_ads_first_seen_at ON public.shortcut_ads(shortcut_ads_first_seen_at);
+CREATE INDEX idx_shortcut_buildings_postcode ON public.shortcut_buildings(shortcut_buildings_postcode);
+CREATE INDEX idx_shortcut_building_listings_price ON public.shortcut_building_listings(shortcut_building_listings_price);
+CREATE INDEX idx_shortcut_building_listings_created_at ON public.shortcut_building_listings(shortcut_building_listings_created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS public.idx_shortcut_building_listings_created_at;
+DROP INDEX IF EXISTS public.idx_shortcut_building_listings_price;
+DROP INDEX IF EXISTS public.idx_shortcut_buildings_postcode;
+DROP INDEX IF EXISTS public.idx_shortcut_ads_first_seen_at;
+DROP INDEX IF EXISTS public.idx_shortcut_ads_area;
+DROP INDEX IF EXISTS public.idx_shortcut_ads_price;
+DROP INDEX IF EXISTS public.idx_frontdoor_building_announcements_first_seen_at;
+DROP INDEX IF EXISTS public.idx_frontdoor_building_announcements_search_price;
+DROP INDEX IF EXISTS public.idx_frontdoor_buildings_postcode;
+DROP INDEX IF EXISTS public.idx_frontdoor_ads_first_seen_at;
+DROP INDEX IF EXISTS public.idx_frontdoor_ads_area;
+DROP INDEX IF EXISTS public.idx_frontdoor_ads_price;
+DROP INDEX IF EXISTS public.idx_frontdoor_ads_postcode;
+DROP VIEW IF EXISTS public.vw_listings;
+DROP FUNCTION IF EXISTS public.fnc__parse_number(TEXT);
+ALTER TABLE public.shortcut_buildings DROP COLUMN IF EXISTS shortcut_buildings_postcode;
@@ -0,0 +1,86 @@
+-- The sync services only write coordinates, keep the geometry columns in sync
+-- with them so buildings can be matched and searched spatially.
+CREATE OR REPLACE FUNCTION public.fnc__frontdoor_buildings_set_geom() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.frontdoor_buildings_geom := CASE
+        WHEN NEW.frontdoor_buildings_latitude IS NOT NULL AND NEW.frontdoor_buildings_longitude IS NOT NULL THEN
+            postgis.ST_SetSRID(postgis.ST_MakePoint(NEW.frontdoor_buildings_longitude, NEW.frontdoor_buildings_latitude), 4326)
+    END;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg__frontdoor_buildings_set_geom
+    BEFORE INSERT OR UPDATE OF frontdoor_buildings_latitude, frontdoor_buildings_longitude
+    ON public.frontdoor_buildings
+    FOR EACH ROW EXECUTE FUNCTION public.fnc__frontdoor_buildings_set_geom();
+
+CREATE OR REPLACE FUNCTION public.fnc__shortcut_buildings_set_geom() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.shortcut_buildings_geom := CASE
+        WHEN NEW.shortcut_buildings_latitude IS NOT NULL AND NEW.shortcut_buildings_longitude IS NOT NULL THEN
+            postgis.ST_SetSRID(postgis.ST_MakePoint(NEW.shortcut_buildings_longitude, NEW.shortcut_buildings_latitude), 4326)
+    END;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg__shortcut_buildings_set_geom
+    BEFORE INSERT OR UPDATE OF shortcut_buildings_latitude, shortcut_buildings_longitude
+    ON public.shortcut_buildings
+    FOR EACH ROW EXECUTE FUNCTION public.fnc__shortcut_buildings_set_geom();
+
+UPDATE public.frontdoor_buildings
+SET frontdoor_buildings_geom = postgis.ST_SetSRID(postgis.ST_MakePoint(frontdoor_buildings_longitude, frontdoor_buildings_latitude), 4326)
+WHERE frontdoor_buildings_latitude IS NOT NULL AND frontdoor_buildings_longitude IS NOT NULL;
+
+UPDATE public.shortcut_buildings
+SET shortcut_buildings_geom = postgis.ST_SetSRID(postgis.ST_MakePoint(shortcut_buildings_longitude, shortcut_buildings_latitude), 4326)
+WHERE shortcut_buildings_latitude IS NOT NULL AND shortcut_buildings_longitude IS NOT NULL;
+
+CREATE INDEX frontdoor_buildings_geom_idx ON public.frontdoor_buildings USING GIST (frontdoor_buildings_geom);
+
+-- Nearest building of the other source within p_max_distance_meters of a
+-- point, or NULL. The two sources have no shared key, so this is how a
+-- frontdoor building and a shortcut building are paired.
+CREATE OR REPLACE FUNCTION public.fnc__nearest_frontdoor_building(
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_max_distance_meters FLOAT8
+) RETURNS UUID AS $$
+    SELECT frontdoor_buildings_id
+    FROM public.frontdoor_buildings
+    WHERE postgis.ST_DWithin(
+        frontdoor_buildings_geom::postgis.geography,
+        postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)::postgis.geography,
+        p_max_distance_meters
+    )
+    ORDER BY frontdoor_buildings_geom OPERATOR(postgis.<->) postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION public.fnc__nearest_shortcut_building(
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_max_distance_meters FLOAT8
+) RETURNS UUID AS $$
+    SELECT shortcut_buildings_id
+    FROM public.shortcut_buildings
+    WHERE postgis.ST_DWithin(
+        shortcut_buildings_geom::postgis.geography,
+        postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)::postgis.geography,
+        p_max_distance_meters
+    )
+    ORDER BY shortcut_buildings_geom OPERATOR(postgis.<->) postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+---- create above / drop below ----
+
+DROP FUNCTION IF EXISTS public.fnc__nearest_shortcut_building(FLOAT8, FLOAT8, FLOAT8);
+DROP FUNCTION IF EXISTS public.fnc__nearest_frontdoor_building(FLOAT8, FLOAT8, FLOAT8);
+DROP INDEX IF EXISTS public.frontdoor_buildings_geom_idx;
+DROP TRIGGER IF EXISTS trg__shortcut_buildings_set_geom ON public.shortcut_buildings;
+DROP FUNCTION IF EXISTS public.fnc__shortcut_buildings_set_geom();
+DROP TRIGGER IF EXISTS trg__frontdoor_buildings_set_geom ON public.frontdoor_buildings;
+DROP FUNCTION IF EXISTS public.fnc__frontdoor_buildings_set_geom();
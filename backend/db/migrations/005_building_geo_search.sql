@@ -0,0 +1,177 @@
+-- One point per physical building. Shortcut buildings that have a frontdoor
+-- building within 25 metres are left out, matching fnc__nearest_*_building.
+CREATE OR REPLACE VIEW public.vw_building_points AS
+SELECT
+    b.frontdoor_buildings_id AS building_id,
+    'frontdoor'::TEXT AS source,
+    NULLIF(concat_ws(' ', b.frontdoor_buildings_street_address, b.frontdoor_buildings_house_number), '') AS address,
+    COALESCE(b.frontdoor_buildings_build_year, b.frontdoor_buildings_construction_end_year) AS build_year,
+    b.frontdoor_buildings_geom AS geom
+FROM public.frontdoor_buildings b
+WHERE b.frontdoor_buildings_geom IS NOT NULL
+UNION ALL
+SELECT
+    s.shortcut_buildings_id AS building_id,
+    'shortcut'::TEXT AS source,
+    s.shortcut_buildings_address AS address,
+    s.shortcut_buildings_construction_year AS build_year,
+    s.shortcut_buildings_geom AS geom
+FROM public.shortcut_buildings s
+WHERE s.shortcut_buildings_geom IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM public.frontdoor_buildings f
+        WHERE f.frontdoor_buildings_geom OPERATOR(postgis.&&) postgis.ST_Expand(s.shortcut_buildings_geom, 0.001)
+            AND postgis.ST_DWithin(f.frontdoor_buildings_geom::postgis.geography, s.shortcut_buildings_geom::postgis.geography, 25)
+    );
+
+-- Building points whose geometry falls in an envelope. The envelope is
+-- matched against the base tables so their GIST indexes can be used, the
+-- shortcut buildings are left out the same way as in vw_building_points.
+CREATE OR REPLACE FUNCTION public.fnc__building_points_in_envelope(
+    p_envelope postgis.geometry
+) RETURNS SETOF public.vw_building_points AS $$
+    SELECT
+        b.frontdoor_buildings_id AS building_id,
+        'frontdoor'::TEXT AS source,
+        NULLIF(concat_ws(' ', b.frontdoor_buildings_street_address, b.frontdoor_buildings_house_number), '') AS address,
+        COALESCE(b.frontdoor_buildings_build_year, b.frontdoor_buildings_construction_end_year) AS build_year,
+        b.frontdoor_buildings_geom AS geom
+    FROM public.frontdoor_buildings b
+    WHERE b.frontdoor_buildings_geom OPERATOR(postgis.&&) p_envelope
+    UNION ALL
+    SELECT
+        s.shortcut_buildings_id AS building_id,
+        'shortcut'::TEXT AS source,
+        s.shortcut_buildings_address AS address,
+        s.shortcut_buildings_construction_year AS build_year,
+        s.shortcut_buildings_geom AS geom
+    FROM public.shortcut_buildings s
+    WHERE s.shortcut_buildings_geom OPERATOR(postgis.&&) p_envelope
+        AND NOT EXISTS (
+            SELECT 1 FROM public.frontdoor_buildings f
+            WHERE f.frontdoor_buildings_geom OPERATOR(postgis.&&) postgis.ST_Expand(s.shortcut_buildings_geom, 0.001)
+                AND postgis.ST_DWithin(f.frontdoor_buildings_geom::postgis.geography, s.shortcut_buildings_geom::postgis.geography, 25)
+        );
+$$ LANGUAGE sql STABLE;
+
+-- Building points inside an area. Exactly one of the bounding box, the
+-- radius around a point or the GeoJSON polygon is expected to be set.
+CREATE OR REPLACE FUNCTION public.fnc__buildings_in_area(
+    p_min_longitude FLOAT8,
+    p_min_latitude FLOAT8,
+    p_max_longitude FLOAT8,
+    p_max_latitude FLOAT8,
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_radius_meters FLOAT8,
+    p_polygon TEXT
+) RETURNS SETOF public.vw_building_points AS $$
+DECLARE
+    v_center postgis.geometry;
+    v_polygon postgis.geometry;
+BEGIN
+    IF p_min_longitude IS NOT NULL THEN
+        RETURN QUERY
+        SELECT p.*
+        FROM public.fnc__building_points_in_envelope(
+            postgis.ST_MakeEnvelope(p_min_longitude, p_min_latitude, p_max_longitude, p_max_latitude, 4326)
+        ) p;
+    ELSIF p_radius_meters IS NOT NULL THEN
+        IF p_latitude IS NULL OR p_longitude IS NULL THEN
+            RAISE EXCEPTION 'radius search needs a latitude and a longitude';
+        END IF;
+        v_center := postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326);
+        RETURN QUERY
+        SELECT p.*
+        FROM public.fnc__building_points_in_envelope(
+            postgis.ST_Expand(v_center, p_radius_meters / 111000.0 / GREATEST(cos(radians(p_latitude)), 0.01))
+        ) p
+        WHERE postgis.ST_DWithin(p.geom::postgis.geography, v_center::postgis.geography, p_radius_meters);
+    ELSIF p_polygon IS NOT NULL THEN
+        v_polygon := postgis.ST_SetSRID(postgis.ST_GeomFromGeoJSON(p_polygon), 4326);
+        RETURN QUERY
+        SELECT p.*
+        FROM public.fnc__building_points_in_envelope(postgis.ST_Envelope(v_polygon)) p
+        WHERE postgis.ST_Intersects(p.geom, v_polygon);
+    END IF;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+CREATE OR REPLACE FUNCTION public.fnc__search_buildings(
+    p_min_longitude FLOAT8,
+    p_min_latitude FLOAT8,
+    p_max_longitude FLOAT8,
+    p_max_latitude FLOAT8,
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_radius_meters FLOAT8,
+    p_polygon TEXT,
+    p_limit INT
+) RETURNS TABLE(
+    building_id UUID,
+    source TEXT,
+    address TEXT,
+    build_year INT4,
+    latitude FLOAT8,
+    longitude FLOAT8
+) AS $$
+    SELECT
+        p.building_id,
+        p.source,
+        p.address,
+        p.build_year,
+        postgis.ST_Y(p.geom) AS latitude,
+        postgis.ST_X(p.geom) AS longitude
+    FROM public.fnc__buildings_in_area(
+        p_min_longitude, p_min_latitude, p_max_longitude, p_max_latitude,
+        p_latitude, p_longitude, p_radius_meters, p_polygon
+    ) p
+    ORDER BY p.building_id
+    LIMIT p_limit;
+$$ LANGUAGE sql STABLE;
+
+-- Groups building points into grid cells of p_grid_size degrees. A cell with a
+-- single building carries that building's ID and source. At most p_max_points
+-- buildings are grouped so a zoomed out map cannot aggregate the whole table.
+CREATE OR REPLACE FUNCTION public.fnc__cluster_buildings(
+    p_min_longitude FLOAT8,
+    p_min_latitude FLOAT8,
+    p_max_longitude FLOAT8,
+    p_max_latitude FLOAT8,
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_radius_meters FLOAT8,
+    p_polygon TEXT,
+    p_grid_size FLOAT8,
+    p_max_points INT
+) RETURNS TABLE(
+    point_count BIGINT,
+    latitude FLOAT8,
+    longitude FLOAT8,
+    building_id UUID,
+    source TEXT
+) AS $$
+    SELECT
+        COUNT(*) AS point_count,
+        postgis.ST_Y(postgis.ST_Centroid(postgis.ST_Collect(p.geom))) AS latitude,
+        postgis.ST_X(postgis.ST_Centroid(postgis.ST_Collect(p.geom))) AS longitude,
+        CASE WHEN COUNT(*) = 1 THEN (array_agg(p.building_id))[1] END AS building_id,
+        CASE WHEN COUNT(*) = 1 THEN (array_agg(p.source))[1] END AS source
+    FROM (
+        SELECT a.building_id, a.source, a.geom
+        FROM public.fnc__buildings_in_area(
+            p_min_longitude, p_min_latitude, p_max_longitude, p_max_latitude,
+            p_latitude, p_longitude, p_radius_meters, p_polygon
+        ) a
+        LIMIT p_max_points
+    ) p
+    GROUP BY postgis.ST_SnapToGrid(p.geom, p_grid_size);
+$$ LANGUAGE sql STABLE;
+
+---- create above / drop below ----
+
+DROP FUNCTION IF EXISTS public.fnc__cluster_buildings(FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, TEXT, FLOAT8, INT);
+DROP FUNCTION IF EXISTS public.fnc__search_buildings(FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, TEXT, INT);
+DROP FUNCTION IF EXISTS public.fnc__buildings_in_area(FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, FLOAT8, TEXT);
+DROP FUNCTION IF EXISTS public.fnc__building_points_in_envelope(postgis.geometry);
+DROP VIEW IF EXISTS public.vw_building_points;
@@ -0,0 +1,364 @@
+CREATE TABLE public.building_links (
+    building_links_id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    building_links_frontdoor_building_id uuid NOT NULL REFERENCES public.frontdoor_buildings(frontdoor_buildings_id) ON DELETE CASCADE,
+    building_links_shortcut_building_id uuid NOT NULL REFERENCES public.shortcut_buildings(shortcut_buildings_id) ON DELETE CASCADE,
+    building_links_status text NOT NULL DEFAULT 'linked'
+        CHECK (building_links_status IN ('linked', 'rejected')),
+    building_links_method text NOT NULL DEFAULT 'auto'
+        CHECK (building_links_method IN ('auto', 'manual')),
+    building_links_confidence float8 NOT NULL,
+    building_links_distance_meters float8,
+    building_links_address_match bool NOT NULL DEFAULT false,
+    building_links_postcode_match bool NOT NULL DEFAULT false,
+    building_links_build_year_match bool NOT NULL DEFAULT false,
+    building_links_note text,
+    building_links_created_at timestamptz NOT NULL DEFAULT now(),
+    building_links_updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (building_links_frontdoor_building_id, building_links_shortcut_building_id)
+);
+
+-- a building is linked to at most one building of the other source
+CREATE UNIQUE INDEX building_links_frontdoor_linked_key ON public.building_links(building_links_frontdoor_building_id)
+    WHERE building_links_status = 'linked';
+CREATE UNIQUE INDEX building_links_shortcut_linked_key ON public.building_links(building_links_shortcut_building_id)
+    WHERE building_links_status = 'linked';
+
+COMMENT ON TABLE public.building_links IS
+'Pairs of frontdoor and shortcut buildings that are the same physical building. Auto rows are rewritten by fnc__match_buildings, manual rows are overrides it never touches.';
+COMMENT ON COLUMN public.building_links.building_links_status IS 'linked pairs the buildings, rejected (manual only) keeps the matcher from pairing them';
+COMMENT ON COLUMN public.building_links.building_links_confidence IS 'Match score between 0 and 1, 1 for manual links';
+
+-- Street name and first house number, lower case, e.g. 'Mannerheimintie 5 A 12,
+-- 00100 Helsinki' becomes 'mannerheimintie 5'.
+CREATE OR REPLACE FUNCTION public.fnc__normalize_street_address(p_address TEXT) RETURNS TEXT AS $$
+    SELECT regexp_replace(trim(m[1]), '\s+', ' ', 'g') || ' ' || m[2]
+    FROM regexp_match(lower(p_address), '^\s*([^0-9,]+?)\s*([0-9]+)') AS m;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Rewrites the automatic links. Candidates are pairs within 100 metres of
+-- each other or with the same street address and postcode. Each building keeps
+-- its best scoring candidate, and a pair is linked when it is the best for both
+-- sides and scores at least p_min_confidence.
+CREATE OR REPLACE FUNCTION public.fnc__match_buildings(
+    p_min_confidence FLOAT8 DEFAULT 0.5
+) RETURNS INT AS $$
+DECLARE
+    v_count INT;
+BEGIN
+    CREATE TEMP TABLE tmp_building_matches ON COMMIT DROP AS
+    WITH fd AS (
+        SELECT
+            frontdoor_buildings_id AS id,
+            public.fnc__normalize_street_address(concat_ws(' ', frontdoor_buildings_street_address, frontdoor_buildings_house_number)) AS address_key,
+            frontdoor_buildings_postcode AS postcode,
+            COALESCE(frontdoor_buildings_build_year, frontdoor_buildings_construction_end_year) AS build_year,
+            frontdoor_buildings_geom AS geom
+        FROM public.frontdoor_buildings
+    ),
+    sc AS (
+        SELECT
+            shortcut_buildings_id AS id,
+            public.fnc__normalize_street_address(shortcut_buildings_address) AS address_key,
+            substring(shortcut_buildings_address FROM '\m([0-9]{5})\M') AS postcode,
+            shortcut_buildings_construction_year AS build_year,
+            shortcut_buildings_geom AS geom
+        FROM public.shortcut_buildings
+    ),
+    -- the two kinds of candidates are found with separate joins, an OR of
+    -- both conditions would compare every pair of buildings
+    pairs AS (
+        -- nearby buildings, through the geometry index of frontdoor_buildings
+        SELECT f.frontdoor_buildings_id AS frontdoor_id, s.shortcut_buildings_id AS shortcut_id
+        FROM public.shortcut_buildings s
+        JOIN public.frontdoor_buildings f
+            ON f.frontdoor_buildings_geom OPERATOR(postgis.&&) postgis.ST_Expand(s.shortcut_buildings_geom, 0.002)
+            AND postgis.ST_DWithin(f.frontdoor_buildings_geom::postgis.geography, s.shortcut_buildings_geom::postgis.geography, 100)
+        UNION
+        -- buildings with the same street address and postcode, as a hash join
+        SELECT fd.id, sc.id
+        FROM sc
+        JOIN fd ON fd.address_key = sc.address_key AND fd.postcode = sc.postcode
+    ),
+    candidates AS (
+        SELECT
+            fd.id AS frontdoor_id,
+            sc.id AS shortcut_id,
+            CASE WHEN fd.geom IS NOT NULL AND sc.geom IS NOT NULL THEN
+                postgis.ST_Distance(fd.geom::postgis.geography, sc.geom::postgis.geography)
+            END AS distance_meters,
+            COALESCE(fd.address_key = sc.address_key, false) AS address_match,
+            COALESCE(fd.postcode = sc.postcode, false) AS postcode_match,
+            COALESCE(fd.build_year = sc.build_year, false) AS build_year_match
+        FROM pairs p
+        JOIN fd ON fd.id = p.frontdoor_id
+        JOIN sc ON sc.id = p.shortcut_id
+    ),
+    scored AS (
+        SELECT
+            c.*,
+            LEAST(1.0,
+                CASE WHEN c.address_match THEN 0.5 ELSE 0 END
+                + CASE WHEN c.postcode_match THEN 0.15 ELSE 0 END
+                + COALESCE(0.35 * GREATEST(0, 1 - c.distance_meters / 100), 0)
+                + CASE WHEN c.build_year_match THEN 0.05 ELSE 0 END
+            ) AS confidence
+        FROM candidates c
+        WHERE NOT EXISTS (
+            SELECT 1 FROM public.building_links l
+            WHERE l.building_links_method = 'manual'
+              AND (
+                  (l.building_links_frontdoor_building_id = c.frontdoor_id AND l.building_links_shortcut_building_id = c.shortcut_id)
+                  OR (l.building_links_status = 'linked' AND l.building_links_frontdoor_building_id = c.frontdoor_id)
+                  OR (l.building_links_status = 'linked' AND l.building_links_shortcut_building_id = c.shortcut_id)
+              )
+        )
+    ),
+    ranked AS (
+        SELECT
+            s.*,
+            row_number() OVER (PARTITION BY s.shortcut_id ORDER BY s.confidence DESC, s.distance_meters NULLS LAST, s.frontdoor_id) AS shortcut_rank,
+            row_number() OVER (PARTITION BY s.frontdoor_id ORDER BY s.confidence DESC, s.distance_meters NULLS LAST, s.shortcut_id) AS frontdoor_rank
+        FROM scored s
+    )
+    SELECT frontdoor_id, shortcut_id, distance_meters, address_match, postcode_match, build_year_match, confidence
+    FROM ranked
+    WHERE shortcut_rank = 1
+      AND frontdoor_rank = 1
+      AND confidence >= p_min_confidence;
+
+    DELETE FROM public.building_links l
+    WHERE l.building_links_method = 'auto'
+      AND NOT EXISTS (
+          SELECT 1 FROM tmp_building_matches m
+          WHERE m.frontdoor_id = l.building_links_frontdoor_building_id
+            AND m.shortcut_id = l.building_links_shortcut_building_id
+      );
+
+    INSERT INTO public.building_links (
+        building_links_frontdoor_building_id,
+        building_links_shortcut_building_id,
+        building_links_status,
+        building_links_method,
+        building_links_confidence,
+        building_links_distance_meters,
+        building_links_address_match,
+        building_links_postcode_match,
+        building_links_build_year_match
+    )
+    SELECT frontdoor_id, shortcut_id, 'linked', 'auto', confidence, distance_meters, address_match, postcode_match, build_year_match
+    FROM tmp_building_matches
+    ON CONFLICT (building_links_frontdoor_building_id, building_links_shortcut_building_id) DO UPDATE SET
+        building_links_confidence = EXCLUDED.building_links_confidence,
+        building_links_distance_meters = EXCLUDED.building_links_distance_meters,
+        building_links_address_match = EXCLUDED.building_links_address_match,
+        building_links_postcode_match = EXCLUDED.building_links_postcode_match,
+        building_links_build_year_match = EXCLUDED.building_links_build_year_match,
+        building_links_updated_at = NOW()
+    WHERE public.building_links.building_links_method = 'auto';
+
+    SELECT COUNT(*) INTO v_count FROM tmp_building_matches;
+    RETURN v_count;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Stores a manual override for a pair. Linking a pair releases any other link
+-- of either building, rejecting it keeps the matcher from linking them again.
+CREATE OR REPLACE FUNCTION public.fnc__set_building_link(
+    p_frontdoor_building_id UUID,
+    p_shortcut_building_id UUID,
+    p_status TEXT,
+    p_note TEXT
+) RETURNS UUID AS $$
+DECLARE
+    v_link_id UUID;
+BEGIN
+    IF p_status = 'linked' THEN
+        DELETE FROM public.building_links
+        WHERE building_links_status = 'linked'
+          AND (building_links_frontdoor_building_id = p_frontdoor_building_id
+               OR building_links_shortcut_building_id = p_shortcut_building_id)
+          AND NOT (building_links_frontdoor_building_id = p_frontdoor_building_id
+                   AND building_links_shortcut_building_id = p_shortcut_building_id);
+    END IF;
+    INSERT INTO public.building_links (
+        building_links_frontdoor_building_id,
+        building_links_shortcut_building_id,
+        building_links_status,
+        building_links_method,
+        building_links_confidence,
+        building_links_note
+    ) VALUES (
+        p_frontdoor_building_id,
+        p_shortcut_building_id,
+        p_status,
+        'manual',
+        CASE WHEN p_status = 'linked' THEN 1 ELSE 0 END,
+        p_note
+    )
+    ON CONFLICT (building_links_frontdoor_building_id, building_links_shortcut_building_id) DO UPDATE SET
+        building_links_status = EXCLUDED.building_links_status,
+        building_links_method = 'manual',
+        building_links_confidence = EXCLUDED.building_links_confidence,
+        building_links_note = EXCLUDED.building_links_note,
+        building_links_updated_at = NOW()
+    RETURNING building_links_id INTO v_link_id;
+    RETURN v_link_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Linked shortcut buildings are now hidden through the link table instead of
+-- by distance.
+CREATE OR REPLACE VIEW public.vw_building_points AS
+SELECT
+    b.frontdoor_buildings_id AS building_id,
+    'frontdoor'::TEXT AS source,
+    NULLIF(concat_ws(' ', b.frontdoor_buildings_street_address, b.frontdoor_buildings_house_number), '') AS address,
+    COALESCE(b.frontdoor_buildings_build_year, b.frontdoor_buildings_construction_end_year) AS build_year,
+    b.frontdoor_buildings_geom AS geom
+FROM public.frontdoor_buildings b
+WHERE b.frontdoor_buildings_geom IS NOT NULL
+UNION ALL
+SELECT
+    s.shortcut_buildings_id AS building_id,
+    'shortcut'::TEXT AS source,
+    s.shortcut_buildings_address AS address,
+    s.shortcut_buildings_construction_year AS build_year,
+    s.shortcut_buildings_geom AS geom
+FROM public.shortcut_buildings s
+WHERE s.shortcut_buildings_geom IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM public.building_links l
+        WHERE l.building_links_shortcut_building_id = s.shortcut_buildings_id
+          AND l.building_links_status = 'linked'
+    );
+
+CREATE OR REPLACE FUNCTION public.fnc__building_points_in_envelope(
+    p_envelope postgis.geometry
+) RETURNS SETOF public.vw_building_points AS $$
+    SELECT
+        b.frontdoor_buildings_id AS building_id,
+        'frontdoor'::TEXT AS source,
+        NULLIF(concat_ws(' ', b.frontdoor_buildings_street_address, b.frontdoor_buildings_house_number), '') AS address,
+        COALESCE(b.frontdoor_buildings_build_year, b.frontdoor_buildings_construction_end_year) AS build_year,
+        b.frontdoor_buildings_geom AS geom
+    FROM public.frontdoor_buildings b
+    WHERE b.frontdoor_buildings_geom OPERATOR(postgis.&&) p_envelope
+    UNION ALL
+    SELECT
+        s.shortcut_buildings_id AS building_id,
+        'shortcut'::TEXT AS source,
+        s.shortcut_buildings_address AS address,
+        s.shortcut_buildings_construction_year AS build_year,
+        s.shortcut_buildings_geom AS geom
+    FROM public.shortcut_buildings s
+    WHERE s.shortcut_buildings_geom OPERATOR(postgis.&&) p_envelope
+        AND NOT EXISTS (
+            SELECT 1 FROM public.building_links l
+            WHERE l.building_links_shortcut_building_id = s.shortcut_buildings_id
+              AND l.building_links_status = 'linked'
+        );
+$$ LANGUAGE sql STABLE;
+
+DROP FUNCTION IF EXISTS public.fnc__nearest_shortcut_building(FLOAT8, FLOAT8, FLOAT8);
+DROP FUNCTION IF EXISTS public.fnc__nearest_frontdoor_building(FLOAT8, FLOAT8, FLOAT8);
+
+INSERT INTO task_queue.task_type_entity_type_mapping (task_type, entity_type) VALUES
+    ('building_matching', 'building_matching')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO task_queue.entity_registry (entity_id, entity_type, status, scheduling_strategy)
+VALUES ('buildings:matching', 'building_matching', 'active', 'manual')
+ON CONFLICT (entity_id) DO NOTHING;
+
+---- create above / drop below ----
+
+DELETE FROM task_queue.entity_registry WHERE entity_id = 'buildings:matching';
+DELETE FROM task_queue.task_type_entity_type_mapping WHERE task_type = 'building_matching';
+
+CREATE OR REPLACE FUNCTION public.fnc__nearest_frontdoor_building(
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_max_distance_meters FLOAT8
+) RETURNS UUID AS $$
+    SELECT frontdoor_buildings_id
+    FROM public.frontdoor_buildings
+    WHERE postgis.ST_DWithin(
+        frontdoor_buildings_geom::postgis.geography,
+        postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)::postgis.geography,
+        p_max_distance_meters
+    )
+    ORDER BY frontdoor_buildings_geom OPERATOR(postgis.<->) postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION public.fnc__nearest_shortcut_building(
+    p_latitude FLOAT8,
+    p_longitude FLOAT8,
+    p_max_distance_meters FLOAT8
+) RETURNS UUID AS $$
+    SELECT shortcut_buildings_id
+    FROM public.shortcut_buildings
+    WHERE postgis.ST_DWithin(
+        shortcut_buildings_geom::postgis.geography,
+        postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)::postgis.geography,
+        p_max_distance_meters
+    )
+    ORDER BY shortcut_buildings_geom OPERATOR(postgis.<->) postgis.ST_SetSRID(postgis.ST_MakePoint(p_longitude, p_latitude), 4326)
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE VIEW public.vw_building_points AS
+SELECT
+    b.frontdoor_buildings_id AS building_id,
+    'frontdoor'::TEXT AS source,
+    NULLIF(concat_ws(' ', b.frontdoor_buildings_street_address, b.frontdoor_buildings_house_number), '') AS address,
+    COALESCE(b.frontdoor_buildings_build_year, b.frontdoor_buildings_construction_end_year) AS build_year,
+    b.frontdoor_buildings_geom AS geom
+FROM public.frontdoor_buildings b
+WHERE b.frontdoor_buildings_geom IS NOT NULL
+UNION ALL
+SELECT
+    s.shortcut_buildings_id AS building_id,
+    'shortcut'::TEXT AS source,
+    s.shortcut_buildings_address AS address,
+    s.shortcut_buildings_construction_year AS build_year,
+    s.shortcut_buildings_geom AS geom
+FROM public.shortcut_buildings s
+WHERE s.shortcut_buildings_geom IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM public.frontdoor_buildings f
+        WHERE f.frontdoor_buildings_geom OPERATOR(postgis.&&) postgis.ST_Expand(s.shortcut_buildings_geom, 0.001)
+            AND postgis.ST_DWithin(f.frontdoor_buildings_geom::postgis.geography, s.shortcut_buildings_geom::postgis.geography, 25)
+    );
+
+CREATE OR REPLACE FUNCTION public.fnc__building_points_in_envelope(
+    p_envelope postgis.geometry
+) RETURNS SETOF public.vw_building_points AS $$
+    SELECT
+        b.frontdoor_buildings_id AS building_id,
+        'frontdoor'::TEXT AS source,
+        NULLIF(concat_ws(' ', b.frontdoor_buildings_street_address, b.frontdoor_buildings_house_number), '') AS address,
+        COALESCE(b.frontdoor_buildings_build_year, b.frontdoor_buildings_construction_end_year) AS build_year,
+        b.frontdoor_buildings_geom AS geom
+    FROM public.frontdoor_buildings b
+    WHERE b.frontdoor_buildings_geom OPERATOR(postgis.&&) p_envelope
+    UNION ALL
+    SELECT
+        s.shortcut_buildings_id AS building_id,
+        'shortcut'::TEXT AS source,
+        s.shortcut_buildings_address AS address,
+        s.shortcut_buildings_construction_year AS build_year,
+        s.shortcut_buildings_geom AS geom
+    FROM public.shortcut_buildings s
+    WHERE s.shortcut_buildings_geom OPERATOR(postgis.&&) p_envelope
+        AND NOT EXISTS (
+            SELECT 1 FROM public.frontdoor_buildings f
+            WHERE f.frontdoor_buildings_geom OPERATOR(postgis.&&) postgis.ST_Expand(s.shortcut_buildings_geom, 0.001)
+                AND postgis.ST_DWithin(f.frontdoor_buildings_geom::postgis.geography, s.shortcut_buildings_geom::postgis.geography, 25)
+        );
+$$ LANGUAGE sql STABLE;
+
+DROP FUNCTION IF EXISTS public.fnc__set_building_link(UUID, UUID, TEXT, TEXT);
+DROP FUNCTION IF EXISTS public.fnc__match_buildings(FLOAT8);
+DROP FUNCTION IF EXISTS public.fnc__normalize_street_address(TEXT);
+DROP TABLE IF EXISTS public.building_links;
@@ -0,0 +1,230 @@
+ALTER TABLE public.frontdoor_ads ADD COLUMN frontdoor_ads_delisted_at timestamptz;
+ALTER TABLE public.shortcut_ads ADD COLUMN shortcut_ads_delisted_at timestamptz;
+
+COMMENT ON COLUMN public.frontdoor_ads.frontdoor_ads_delisted_at IS 'When the ad dropped out of the sitemap or started returning 404, NULL while listed';
+COMMENT ON COLUMN public.shortcut_ads.shortcut_ads_delisted_at IS 'When the ad dropped out of the sitemap or started returning 404, NULL while listed';
+
+-- Append-only price and status history of the ads. A row is written when a
+-- sync sees the price, debt-free price or status change, and when the ad is
+-- delisted or relisted.
+CREATE TABLE public.frontdoor_ad_history (
+    frontdoor_ad_history_id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    frontdoor_ad_history_ad_id uuid NOT NULL REFERENCES public.frontdoor_ads(frontdoor_ads_id) ON DELETE CASCADE,
+    frontdoor_ad_history_event text NOT NULL
+        CHECK (frontdoor_ad_history_event IN ('listed', 'price_changed', 'status_changed', 'delisted', 'relisted')),
+    frontdoor_ad_history_price float8,
+    frontdoor_ad_history_debt_free_price float8,
+    frontdoor_ad_history_previous_price float8,
+    frontdoor_ad_history_previous_debt_free_price float8,
+    frontdoor_ad_history_status text,
+    frontdoor_ad_history_recorded_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_frontdoor_ad_history_ad_id ON public.frontdoor_ad_history(frontdoor_ad_history_ad_id, frontdoor_ad_history_recorded_at);
+
+CREATE TABLE public.shortcut_ad_history (
+    shortcut_ad_history_id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    shortcut_ad_history_ad_id int8 NOT NULL REFERENCES public.shortcut_ads(shortcut_ads_id) ON DELETE CASCADE,
+    shortcut_ad_history_event text NOT NULL
+        CHECK (shortcut_ad_history_event IN ('listed', 'price_changed', 'status_changed', 'delisted', 'relisted')),
+    shortcut_ad_history_price float8,
+    shortcut_ad_history_debt_free_price float8,
+    shortcut_ad_history_previous_price float8,
+    shortcut_ad_history_previous_debt_free_price float8,
+    shortcut_ad_history_status text,
+    shortcut_ad_history_recorded_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shortcut_ad_history_ad_id ON public.shortcut_ad_history(shortcut_ad_history_ad_id, shortcut_ad_history_recorded_at);
+
+COMMENT ON COLUMN public.frontdoor_ad_history.frontdoor_ad_history_previous_price IS 'Price of the previous row, or the previousPrice reported by frontdoor for the first row';
+COMMENT ON COLUMN public.shortcut_ad_history.shortcut_ad_history_status IS 'Ad type derived from cardType, e.g. sale or rent';
+
+CREATE OR REPLACE FUNCTION public.fnc__reject_history_update() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg__frontdoor_ad_history_append_only
+    BEFORE UPDATE ON public.frontdoor_ad_history
+    FOR EACH ROW EXECUTE FUNCTION public.fnc__reject_history_update();
+
+CREATE TRIGGER trg__shortcut_ad_history_append_only
+    BEFORE UPDATE ON public.shortcut_ad_history
+    FOR EACH ROW EXECUTE FUNCTION public.fnc__reject_history_update();
+
+-- Delisted ads are reported as removed.
+CREATE OR REPLACE VIEW public.vw_listings AS
+SELECT
+    'frontdoor:ad:' || a.frontdoor_ads_external_id AS listing_id,
+    'frontdoor'::TEXT AS source,
+    a.frontdoor_ads_external_id AS external_id,
+    a.frontdoor_ads_url AS url,
+    public.fnc__parse_number(a.frontdoor_ads_data ->> 'sellingPrice') AS price,
+    public.fnc__parse_number(a.frontdoor_ads_data ->> 'debfFreePrice') AS debt_free_price,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'livingArea') AS area,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'totalRoomCount')::INT4 AS rooms,
+    a.frontdoor_ads_data -> 'property' -> 'postCode' ->> 'postCode' AS postcode,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'property' -> 'geoCode' ->> 'latitude') AS latitude,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'property' -> 'geoCode' ->> 'longitude') AS longitude,
+    a.frontdoor_ads_first_seen_at AS first_seen_at,
+    a.frontdoor_ads_last_seen_at AS last_seen_at,
+    CASE WHEN a.frontdoor_ads_page_not_found OR a.frontdoor_ads_delisted_at IS NOT NULL THEN 'removed' ELSE 'active' END AS status
+FROM public.frontdoor_ads a
+WHERE a.frontdoor_ads_data IS NOT NULL OR a.frontdoor_ads_page_not_found
+UNION ALL
+SELECT
+    'frontdoor:announcement:' || ba.frontdoor_building_announcements_id::TEXT AS listing_id,
+    'frontdoor'::TEXT AS source,
+    COALESCE(ba.frontdoor_building_announcements_friendly_id, ba.frontdoor_building_announcements_external_id::TEXT) AS external_id,
+    NULL::TEXT AS url,
+    ba.frontdoor_building_announcements_search_price AS price,
+    ba.frontdoor_building_announcements_search_price AS debt_free_price,
+    ba.frontdoor_building_announcements_area AS area,
+    NULL::INT4 AS rooms,
+    b.frontdoor_buildings_postcode AS postcode,
+    b.frontdoor_buildings_latitude AS latitude,
+    b.frontdoor_buildings_longitude AS longitude,
+    ba.frontdoor_building_announcements_first_seen_at AS first_seen_at,
+    ba.frontdoor_building_announcements_last_seen_at AS last_seen_at,
+    CASE WHEN ba.frontdoor_building_announcements_published THEN 'active' ELSE 'removed' END AS status
+FROM public.frontdoor_building_announcements ba
+JOIN public.frontdoor_buildings b
+    ON b.frontdoor_buildings_id = ba.frontdoor_building_announcements_building_id
+-- announcements that were also synced as ads are listed once, as the ad
+WHERE NOT EXISTS (
+    SELECT 1 FROM public.frontdoor_ads a
+    WHERE a.frontdoor_ads_external_id = ba.frontdoor_building_announcements_friendly_id
+)
+UNION ALL
+SELECT
+    'shortcut:ad:' || s.shortcut_ads_id::TEXT AS listing_id,
+    'shortcut'::TEXT AS source,
+    s.shortcut_ads_id::TEXT AS external_id,
+    s.shortcut_ads_url AS url,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'price') AS price,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'debtFreePrice') AS debt_free_price,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'size') AS area,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'rooms')::INT4 AS rooms,
+    s.shortcut_ads_data -> 'address' -> 'zipCode' ->> 'name' AS postcode,
+    COALESCE(public.fnc__parse_number(s.shortcut_ads_data -> 'coordinates' ->> 'latitude'), sb.shortcut_buildings_latitude) AS latitude,
+    COALESCE(public.fnc__parse_number(s.shortcut_ads_data -> 'coordinates' ->> 'longitude'), sb.shortcut_buildings_longitude) AS longitude,
+    s.shortcut_ads_first_seen_at AS first_seen_at,
+    s.shortcut_ads_last_seen_at AS last_seen_at,
+    CASE WHEN s.shortcut_ads_delisted_at IS NULL THEN 'active' ELSE 'removed' END AS status
+FROM public.shortcut_ads s
+LEFT JOIN public.shortcut_buildings sb
+    ON sb.shortcut_buildings_id = s.shortcut_ads_building_id
+WHERE s.shortcut_ads_data IS NOT NULL
+UNION ALL
+SELECT
+    'shortcut:listing:' || l.shortcut_building_listings_id::TEXT AS listing_id,
+    'shortcut'::TEXT AS source,
+    l.shortcut_building_listings_id::TEXT AS external_id,
+    sb.shortcut_buildings_url AS url,
+    l.shortcut_building_listings_price AS price,
+    l.shortcut_building_listings_price AS debt_free_price,
+    l.shortcut_building_listings_size AS area,
+    NULL::INT4 AS rooms,
+    sb.shortcut_buildings_postcode AS postcode,
+    sb.shortcut_buildings_latitude AS latitude,
+    sb.shortcut_buildings_longitude AS longitude,
+    l.shortcut_building_listings_created_at AS first_seen_at,
+    l.shortcut_building_listings_updated_at AS last_seen_at,
+    CASE WHEN l.shortcut_building_listings_deleted_at IS NULL THEN 'active' ELSE 'removed' END AS status
+FROM public.shortcut_building_listings l
+JOIN public.shortcut_buildings sb
+    ON sb.shortcut_buildings_id = l.shortcut_building_listings_building_id;
+
+---- create above / drop below ----
+
+CREATE OR REPLACE VIEW public.vw_listings AS
+SELECT
+    'frontdoor:ad:' || a.frontdoor_ads_external_id AS listing_id,
+    'frontdoor'::TEXT AS source,
+    a.frontdoor_ads_external_id AS external_id,
+    a.frontdoor_ads_url AS url,
+    public.fnc__parse_number(a.frontdoor_ads_data ->> 'sellingPrice') AS price,
+    public.fnc__parse_number(a.frontdoor_ads_data ->> 'debfFreePrice') AS debt_free_price,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'livingArea') AS area,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'residenceDetailsDTO' ->> 'totalRoomCount')::INT4 AS rooms,
+    a.frontdoor_ads_data -> 'property' -> 'postCode' ->> 'postCode' AS postcode,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'property' -> 'geoCode' ->> 'latitude') AS latitude,
+    public.fnc__parse_number(a.frontdoor_ads_data -> 'property' -> 'geoCode' ->> 'longitude') AS longitude,
+    a.frontdoor_ads_first_seen_at AS first_seen_at,
+    a.frontdoor_ads_last_seen_at AS last_seen_at,
+    CASE WHEN a.frontdoor_ads_page_not_found THEN 'removed' ELSE 'active' END AS status
+FROM public.frontdoor_ads a
+WHERE a.frontdoor_ads_data IS NOT NULL OR a.frontdoor_ads_page_not_found
+UNION ALL
+SELECT
+    'frontdoor:announcement:' || ba.frontdoor_building_announcements_id::TEXT AS listing_id,
+    'frontdoor'::TEXT AS source,
+    COALESCE(ba.frontdoor_building_announcements_friendly_id, ba.frontdoor_building_announcements_external_id::TEXT) AS external_id,
+    NULL::TEXT AS url,
+    ba.frontdoor_building_announcements_search_price AS price,
+    ba.frontdoor_building_announcements_search_price AS debt_free_price,
+    ba.frontdoor_building_announcements_area AS area,
+    NULL::INT4 AS rooms,
+    b.frontdoor_buildings_postcode AS postcode,
+    b.frontdoor_buildings_latitude AS latitude,
+    b.frontdoor_buildings_longitude AS longitude,
+    ba.frontdoor_building_announcements_first_seen_at AS first_seen_at,
+    ba.frontdoor_building_announcements_last_seen_at AS last_seen_at,
+    CASE WHEN ba.frontdoor_building_announcements_published THEN 'active' ELSE 'removed' END AS status
+FROM public.frontdoor_building_announcements ba
+JOIN public.frontdoor_buildings b
+    ON b.frontdoor_buildings_id = ba.frontdoor_building_announcements_building_id
+-- announcements that were also synced as ads are listed once, as the ad
+WHERE NOT EXISTS (
+    SELECT 1 FROM public.frontdoor_ads a
+    WHERE a.frontdoor_ads_external_id = ba.frontdoor_building_announcements_friendly_id
+)
+UNION ALL
+SELECT
+    'shortcut:ad:' || s.shortcut_ads_id::TEXT AS listing_id,
+    'shortcut'::TEXT AS source,
+    s.shortcut_ads_id::TEXT AS external_id,
+    s.shortcut_ads_url AS url,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'price') AS price,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'debtFreePrice') AS debt_free_price,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'size') AS area,
+    public.fnc__parse_number(s.shortcut_ads_data ->> 'rooms')::INT4 AS rooms,
+    s.shortcut_ads_data -> 'address' -> 'zipCode' ->> 'name' AS postcode,
+    COALESCE(public.fnc__parse_number(s.shortcut_ads_data -> 'coordinates' ->> 'latitude'), sb.shortcut_buildings_latitude) AS latitude,
+    COALESCE(public.fnc__parse_number(s.shortcut_ads_data -> 'coordinates' ->> 'longitude'), sb.shortcut_buildings_longitude) AS longitude,
+    s.shortcut_ads_first_seen_at AS first_seen_at,
+    s.shortcut_ads_last_seen_at AS last_seen_at,
+    'active'::TEXT AS status
+FROM public.shortcut_ads s
+LEFT JOIN public.shortcut_buildings sb
+    ON sb.shortcut_buildings_id = s.shortcut_ads_building_id
+WHERE s.shortcut_ads_data IS NOT NULL
+UNION ALL
+SELECT
+    'shortcut:listing:' || l.shortcut_building_listings_id::TEXT AS listing_id,
+    'shortcut'::TEXT AS source,
+    l.shortcut_building_listings_id::TEXT AS external_id,
+    sb.shortcut_buildings_url AS url,
+    l.shortcut_building_listings_price AS price,
+    l.shortcut_building_listings_price AS debt_free_price,
+    l.shortcut_building_listings_size AS area,
+    NULL::INT4 AS rooms,
+    sb.shortcut_buildings_postcode AS postcode,
+    sb.shortcut_buildings_latitude AS latitude,
+    sb.shortcut_buildings_longitude AS longitude,
+    l.shortcut_building_listings_created_at AS first_seen_at,
+    l.shortcut_building_listings_updated_at AS last_seen_at,
+    CASE WHEN l.shortcut_building_listings_deleted_at IS NULL THEN 'active' ELSE 'removed' END AS status
+FROM public.shortcut_building_listings l
+JOIN public.shortcut_buildings sb
+    ON sb.shortcut_buildings_id = l.shortcut_building_listings_building_id;
+
+DROP TRIGGER IF EXISTS trg__shortcut_ad_history_append_only ON public.shortcut_ad_history;
+DROP TRIGGER IF EXISTS trg__frontdoor_ad_history_append_only ON public.frontdoor_ad_history;
+DROP FUNCTION IF EXISTS public.fnc__reject_history_update();
+DROP TABLE IF EXISTS public.shortcut_ad_history;
+DROP TABLE IF EXISTS public.frontdoor_ad_history;
+ALTER TABLE public.shortcut_ads DROP COLUMN IF EXISTS shortcut_ads_delisted_at;
+ALTER TABLE public.frontdoor_ads DROP COLUMN IF EXISTS frontdoor_ads_delisted_at;
@@ -0,0 +1,163 @@
+-- Entities that a sitemap crawl no longer lists are marked removed. This is
+-- kept apart from stopped, which is an admin pause, so that registering a
+-- listed entity again always reactivates it.
+ALTER TABLE task_queue.entity_registry
+    DROP CONSTRAINT entity_registry_status_check,
+    ADD CONSTRAINT entity_registry_status_check
+        CHECK (status IN ('active', 'stopped', 'removed'));
+
+-- One row per sitemap crawl with its diff against the previous crawl of the
+-- same source. The first crawl of a source has no previous crawl and is
+-- diffed against the active entities of the crawled types instead.
+CREATE TABLE task_queue.sitemap_crawl (
+    crawl_id BIGSERIAL PRIMARY KEY,
+    source TEXT NOT NULL
+        CHECK (source IN ('frontdoor', 'shortcut')),
+    previous_crawl_id BIGINT
+        REFERENCES task_queue.sitemap_crawl(crawl_id) ON DELETE SET NULL,
+    entry_count INT NOT NULL DEFAULT 0,
+    added_count INT NOT NULL DEFAULT 0,
+    removed_count INT NOT NULL DEFAULT 0,
+    crawled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sitemap_crawl_source_crawled ON task_queue.sitemap_crawl(source, crawled_at DESC);
+
+-- Entities listed by a crawl. Only the latest crawl of each source is kept,
+-- it is what the next crawl is diffed against.
+CREATE TABLE task_queue.sitemap_crawl_entry (
+    crawl_id BIGINT NOT NULL
+        REFERENCES task_queue.sitemap_crawl(crawl_id) ON DELETE CASCADE,
+    entity_id TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    PRIMARY KEY (crawl_id, entity_type, entity_id)
+);
+
+-- Entities that appeared or disappeared between a crawl and the previous one.
+CREATE TABLE task_queue.sitemap_crawl_change (
+    crawl_id BIGINT NOT NULL
+        REFERENCES task_queue.sitemap_crawl(crawl_id) ON DELETE CASCADE,
+    entity_id TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    change TEXT NOT NULL
+        CHECK (change IN ('added', 'removed')),
+    PRIMARY KEY (crawl_id, entity_type, entity_id)
+);
+
+CREATE INDEX idx_sitemap_crawl_change_crawl_change ON task_queue.sitemap_crawl_change(crawl_id, change, entity_type);
+
+-- Stores the entities of a crawl, diffs them against the previous crawl of
+-- the source and marks the removed entities so the daily syncs skip them.
+-- Entities that come back are reactivated by fnc__register_entities.
+CREATE OR REPLACE FUNCTION task_queue.fnc__record_sitemap_crawl(
+    p_source TEXT,
+    p_entity_ids TEXT[],
+    p_entity_types TEXT[]
+) RETURNS SETOF task_queue.sitemap_crawl AS $$
+DECLARE
+    v_crawl_id BIGINT;
+    v_previous_crawl_id BIGINT;
+BEGIN
+    IF cardinality(p_entity_ids) IS DISTINCT FROM cardinality(p_entity_types) THEN
+        RAISE EXCEPTION 'entity id and type arrays differ in length';
+    END IF;
+    -- serialize crawls of the same source so each one diffs against the last
+    PERFORM pg_advisory_xact_lock(hashtext('sitemap_crawl:' || p_source));
+
+    SELECT crawl_id INTO v_previous_crawl_id
+    FROM task_queue.sitemap_crawl
+    WHERE source = p_source
+    ORDER BY crawl_id DESC
+    LIMIT 1;
+
+    INSERT INTO task_queue.sitemap_crawl (source, previous_crawl_id)
+    VALUES (p_source, v_previous_crawl_id)
+    RETURNING crawl_id INTO v_crawl_id;
+
+    INSERT INTO task_queue.sitemap_crawl_entry (crawl_id, entity_id, entity_type)
+    SELECT DISTINCT v_crawl_id, e.entity_id, e.entity_type
+    FROM unnest(p_entity_ids, p_entity_types) AS e(entity_id, entity_type);
+
+    IF v_previous_crawl_id IS NULL THEN
+        -- nothing to diff against yet, so the registry is reconciled with the
+        -- crawl for the entity types it listed
+        INSERT INTO task_queue.sitemap_crawl_change (crawl_id, entity_id, entity_type, change)
+        SELECT v_crawl_id, r.entity_id, r.entity_type, 'removed'
+        FROM task_queue.entity_registry r
+        WHERE r.status = 'active'
+            AND r.entity_type IN (
+                SELECT DISTINCT entity_type FROM task_queue.sitemap_crawl_entry WHERE crawl_id = v_crawl_id
+            )
+            AND NOT EXISTS (
+                SELECT 1 FROM task_queue.sitemap_crawl_entry cur
+                WHERE cur.crawl_id = v_crawl_id
+                    AND cur.entity_type = r.entity_type
+                    AND cur.entity_id = r.entity_id
+            );
+    ELSE
+        INSERT INTO task_queue.sitemap_crawl_change (crawl_id, entity_id, entity_type, change)
+        SELECT v_crawl_id, cur.entity_id, cur.entity_type, 'added'
+        FROM task_queue.sitemap_crawl_entry cur
+        WHERE cur.crawl_id = v_crawl_id
+            AND NOT EXISTS (
+                SELECT 1 FROM task_queue.sitemap_crawl_entry prev
+                WHERE prev.crawl_id = v_previous_crawl_id
+                    AND prev.entity_type = cur.entity_type
+                    AND prev.entity_id = cur.entity_id
+            );
+
+        INSERT INTO task_queue.sitemap_crawl_change (crawl_id, entity_id, entity_type, change)
+        SELECT v_crawl_id, prev.entity_id, prev.entity_type, 'removed'
+        FROM task_queue.sitemap_crawl_entry prev
+        WHERE prev.crawl_id = v_previous_crawl_id
+            AND NOT EXISTS (
+                SELECT 1 FROM task_queue.sitemap_crawl_entry cur
+                WHERE cur.crawl_id = v_crawl_id
+                    AND cur.entity_type = prev.entity_type
+                    AND cur.entity_id = prev.entity_id
+            );
+    END IF;
+
+    -- paused entities stay paused
+    UPDATE task_queue.entity_registry r
+    SET status = 'removed',
+        updated_at = NOW()
+    FROM task_queue.sitemap_crawl_change c
+    WHERE c.crawl_id = v_crawl_id
+        AND c.change = 'removed'
+        AND r.entity_id = c.entity_id
+        AND r.entity_type = c.entity_type
+        AND r.status = 'active';
+
+    UPDATE task_queue.sitemap_crawl sc
+    SET entry_count = (SELECT COUNT(*) FROM task_queue.sitemap_crawl_entry WHERE crawl_id = v_crawl_id),
+        added_count = (SELECT COUNT(*) FROM task_queue.sitemap_crawl_change WHERE crawl_id = v_crawl_id AND change = 'added'),
+        removed_count = (SELECT COUNT(*) FROM task_queue.sitemap_crawl_change WHERE crawl_id = v_crawl_id AND change = 'removed')
+    WHERE sc.crawl_id = v_crawl_id;
+
+    DELETE FROM task_queue.sitemap_crawl_entry e
+    USING task_queue.sitemap_crawl sc
+    WHERE e.crawl_id = sc.crawl_id
+        AND sc.source = p_source
+        AND sc.crawl_id < v_crawl_id;
+
+    RETURN QUERY SELECT * FROM task_queue.sitemap_crawl WHERE crawl_id = v_crawl_id;
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+
+DROP FUNCTION IF EXISTS task_queue.fnc__record_sitemap_crawl(TEXT, TEXT[], TEXT[]);
+DROP TABLE IF EXISTS task_queue.sitemap_crawl_change;
+DROP TABLE IF EXISTS task_queue.sitemap_crawl_entry;
+DROP TABLE IF EXISTS task_queue.sitemap_crawl;
+
+UPDATE task_queue.entity_registry
+SET status = 'active',
+    updated_at = NOW()
+WHERE status = 'removed';
+
+ALTER TABLE task_queue.entity_registry
+    DROP CONSTRAINT entity_registry_status_check,
+    ADD CONSTRAINT entity_registry_status_check
+        CHECK (status IN ('active', 'stopped'));
@@ -0,0 +1,171 @@
+CREATE TABLE public.saved_searches (
+    saved_searches_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    saved_searches_name text NOT NULL,
+    saved_searches_source text
+        CHECK (saved_searches_source IN ('frontdoor', 'shortcut')),
+    saved_searches_city text,
+    saved_searches_postcodes text[] NOT NULL DEFAULT '{}',
+    saved_searches_min_price float8,
+    saved_searches_max_price float8,
+    saved_searches_min_rooms int4,
+    saved_searches_max_rooms int4,
+    saved_searches_min_area float8,
+    saved_searches_max_area float8,
+    saved_searches_notifier text NOT NULL DEFAULT 'webhook'
+        CHECK (saved_searches_notifier IN ('webhook')),
+    saved_searches_target text NOT NULL,
+    saved_searches_enabled bool NOT NULL DEFAULT true,
+    saved_searches_last_evaluated_at timestamptz,
+    saved_searches_created_at timestamptz NOT NULL DEFAULT now(),
+    saved_searches_updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- Matches waiting for or done with delivery. A listing is recorded once per
+-- saved search, so evaluating again never alerts twice.
+CREATE TABLE public.saved_search_outbox (
+    saved_search_outbox_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    saved_search_outbox_saved_search_id uuid NOT NULL
+        REFERENCES public.saved_searches(saved_searches_id) ON DELETE CASCADE,
+    saved_search_outbox_listing_id text NOT NULL,
+    saved_search_outbox_payload jsonb NOT NULL,
+    saved_search_outbox_status text NOT NULL DEFAULT 'pending'
+        CHECK (saved_search_outbox_status IN ('pending', 'delivered', 'failed')),
+    saved_search_outbox_attempts int4 NOT NULL DEFAULT 0,
+    saved_search_outbox_last_error text,
+    saved_search_outbox_created_at timestamptz NOT NULL DEFAULT now(),
+    saved_search_outbox_delivered_at timestamptz,
+    UNIQUE (saved_search_outbox_saved_search_id, saved_search_outbox_listing_id)
+);
+
+CREATE INDEX idx_saved_search_outbox_pending ON public.saved_search_outbox(saved_search_outbox_created_at)
+    WHERE saved_search_outbox_status = 'pending';
+
+COMMENT ON COLUMN public.saved_searches.saved_searches_target IS 'Where alerts are delivered, the webhook subscription ID for the webhook notifier';
+
+-- Records active listings that match enabled saved searches. A search only
+-- looks at listings first seen since its previous evaluation, less a margin
+-- for ads committed while that evaluation ran, and never at listings older
+-- than the search. Returns the number of new matches.
+CREATE OR REPLACE FUNCTION public.fnc__evaluate_saved_searches() RETURNS INT AS $$
+DECLARE
+    v_margin CONSTANT INTERVAL := INTERVAL '5 minutes';
+    v_since TIMESTAMPTZ;
+    v_count INT;
+BEGIN
+    SELECT MIN(GREATEST(s.saved_searches_created_at, s.saved_searches_last_evaluated_at - v_margin))
+    INTO v_since
+    FROM public.saved_searches s
+    WHERE s.saved_searches_enabled;
+    IF v_since IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    WITH candidates AS MATERIALIZED (
+        SELECT l.*
+        FROM public.vw_listings l
+        WHERE l.first_seen_at >= v_since
+            AND l.status = 'active'
+    )
+    INSERT INTO public.saved_search_outbox (
+        saved_search_outbox_saved_search_id,
+        saved_search_outbox_listing_id,
+        saved_search_outbox_payload
+    )
+    SELECT s.saved_searches_id, l.listing_id, to_jsonb(l)
+    FROM public.saved_searches s
+    JOIN candidates l
+        ON l.first_seen_at >= GREATEST(s.saved_searches_created_at, s.saved_searches_last_evaluated_at - v_margin)
+        AND (s.saved_searches_source IS NULL OR l.source = s.saved_searches_source)
+        AND (s.saved_searches_min_price IS NULL OR l.price >= s.saved_searches_min_price)
+        AND (s.saved_searches_max_price IS NULL OR l.price <= s.saved_searches_max_price)
+        AND (s.saved_searches_min_rooms IS NULL OR l.rooms >= s.saved_searches_min_rooms)
+        AND (s.saved_searches_max_rooms IS NULL OR l.rooms <= s.saved_searches_max_rooms)
+        AND (s.saved_searches_min_area IS NULL OR l.area >= s.saved_searches_min_area)
+        AND (s.saved_searches_max_area IS NULL OR l.area <= s.saved_searches_max_area)
+        AND (cardinality(s.saved_searches_postcodes) = 0 OR l.postcode = ANY(s.saved_searches_postcodes))
+        AND (
+            s.saved_searches_city IS NULL
+            OR l.postcode IN (
+                SELECT pc.prices_postal_codes_code
+                FROM public.prices_postal_codes pc
+                JOIN public.prices_cities c ON c.prices_cities_id = pc.prices_postal_codes_city_id
+                WHERE lower(c.prices_cities_name) = lower(s.saved_searches_city)
+            )
+        )
+    WHERE s.saved_searches_enabled
+    ON CONFLICT (saved_search_outbox_saved_search_id, saved_search_outbox_listing_id) DO NOTHING;
+    GET DIAGNOSTICS v_count = ROW_COUNT;
+
+    UPDATE public.saved_searches
+    SET saved_searches_last_evaluated_at = now()
+    WHERE saved_searches_enabled;
+
+    RETURN v_count;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Queues a task for an entity unless one is already pending and returns the
+-- pending task. Callers are serialized per entity and task type, so that
+-- concurrent triggers cannot both find no task and create one each.
+CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_singleton_task(
+    p_entity_id TEXT,
+    p_task_type TEXT,
+    p_priority INT DEFAULT 0,
+    p_max_attempts INT DEFAULT 3
+) RETURNS TABLE(pending_task_id BIGINT, created BOOLEAN) AS $$
+DECLARE
+    v_task_id BIGINT;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('fnc__schedule_singleton_task'), hashtext(p_entity_id || '/' || p_task_type));
+    SELECT t.task_id INTO v_task_id
+    FROM task_queue.task t
+    WHERE t.entity_id = p_entity_id
+      AND t.task_type = p_task_type
+      AND t.status = 'pending'
+    ORDER BY t.task_id
+    LIMIT 1;
+    IF FOUND THEN
+        RETURN QUERY SELECT v_task_id, false;
+        RETURN;
+    END IF;
+    INSERT INTO task_queue.task (
+        entity_id,
+        task_type,
+        status,
+        priority,
+        attempt,
+        max_attempts,
+        scheduled_for
+    )
+    VALUES (
+        p_entity_id,
+        p_task_type,
+        'pending',
+        p_priority,
+        0,
+        p_max_attempts,
+        NOW()
+    )
+    RETURNING task_id INTO v_task_id;
+    PERFORM task_queue.fnc__enqueue_task(v_task_id);
+    RETURN QUERY SELECT v_task_id, true;
+END;
+$$ LANGUAGE plpgsql;
+
+INSERT INTO task_queue.task_type_entity_type_mapping (task_type, entity_type) VALUES
+    ('saved_search_evaluation', 'saved_search_evaluation')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO task_queue.entity_registry (entity_id, entity_type, status, scheduling_strategy)
+VALUES ('saved_searches:evaluation', 'saved_search_evaluation', 'active', 'manual')
+ON CONFLICT (entity_id) DO NOTHING;
+
+---- create above / drop below ----
+
+DELETE FROM task_queue.entity_registry WHERE entity_id = 'saved_searches:evaluation';
+DELETE FROM task_queue.task_type_entity_type_mapping WHERE task_type = 'saved_search_evaluation';
+
+DROP FUNCTION IF EXISTS task_queue.fnc__schedule_singleton_task(TEXT, TEXT, INT, INT);
+DROP FUNCTION IF EXISTS public.fnc__evaluate_saved_searches();
+DROP TABLE IF EXISTS public.saved_search_outbox;
+DROP TABLE IF EXISTS public.saved_searches;
@@ -0,0 +1,143 @@
+-- Outbound webhook subscriptions. An empty event type list subscribes to all
+-- events.
+CREATE TABLE public.webhook_subscriptions (
+    webhook_subscriptions_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_subscriptions_url text NOT NULL,
+    webhook_subscriptions_secret text NOT NULL,
+    webhook_subscriptions_event_types text[] NOT NULL DEFAULT '{}',
+    webhook_subscriptions_description text,
+    webhook_subscriptions_enabled bool NOT NULL DEFAULT true,
+    webhook_subscriptions_created_at timestamptz NOT NULL DEFAULT now(),
+    webhook_subscriptions_updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- One row per event and subscription. Each delivery is sent by its own
+-- webhook_delivery task, which retries it and dead letters it when it keeps
+-- failing.
+CREATE TABLE public.webhook_deliveries (
+    webhook_deliveries_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_deliveries_subscription_id uuid NOT NULL
+        REFERENCES public.webhook_subscriptions(webhook_subscriptions_id) ON DELETE CASCADE,
+    webhook_deliveries_event_id uuid NOT NULL,
+    webhook_deliveries_event_type text NOT NULL,
+    webhook_deliveries_payload jsonb NOT NULL,
+    webhook_deliveries_status text NOT NULL DEFAULT 'pending'
+        CHECK (webhook_deliveries_status IN ('pending', 'delivered', 'failed')),
+    webhook_deliveries_attempts int4 NOT NULL DEFAULT 0,
+    webhook_deliveries_response_status int4,
+    webhook_deliveries_last_error text,
+    webhook_deliveries_created_at timestamptz NOT NULL DEFAULT now(),
+    webhook_deliveries_delivered_at timestamptz
+);
+
+CREATE INDEX idx_webhook_deliveries_subscription_created ON public.webhook_deliveries(webhook_deliveries_subscription_id, webhook_deliveries_created_at DESC);
+
+CREATE TABLE public.webhook_delivery_attempts (
+    webhook_delivery_attempts_id bigserial PRIMARY KEY,
+    webhook_delivery_attempts_delivery_id uuid NOT NULL
+        REFERENCES public.webhook_deliveries(webhook_deliveries_id) ON DELETE CASCADE,
+    webhook_delivery_attempts_attempt int4 NOT NULL,
+    webhook_delivery_attempts_response_status int4,
+    webhook_delivery_attempts_error text,
+    webhook_delivery_attempts_duration_ms int4 NOT NULL,
+    webhook_delivery_attempts_attempted_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery ON public.webhook_delivery_attempts(webhook_delivery_attempts_delivery_id, webhook_delivery_attempts_attempt);
+
+INSERT INTO task_queue.task_type_entity_type_mapping (task_type, entity_type) VALUES
+    ('webhook_delivery', 'webhook_delivery')
+ON CONFLICT DO NOTHING;
+
+-- Records a delivery and queues its webhook_delivery task in one transaction,
+-- so that a delivery is never left pending without a task to send it.
+CREATE OR REPLACE FUNCTION public.fnc__create_webhook_delivery(
+    p_subscription_id UUID,
+    p_event_id UUID,
+    p_event_type TEXT,
+    p_payload JSONB,
+    p_max_attempts INT
+) RETURNS UUID AS $$
+DECLARE
+    v_delivery_id UUID;
+    v_entity_id TEXT;
+    v_task_id BIGINT;
+BEGIN
+    INSERT INTO public.webhook_deliveries (
+        webhook_deliveries_subscription_id,
+        webhook_deliveries_event_id,
+        webhook_deliveries_event_type,
+        webhook_deliveries_payload
+    ) VALUES (
+        p_subscription_id,
+        p_event_id,
+        p_event_type,
+        p_payload
+    )
+    RETURNING webhook_deliveries_id INTO v_delivery_id;
+    v_entity_id := 'webhook_delivery:' || v_delivery_id;
+    PERFORM task_queue.fnc__register_entity(v_entity_id, 'webhook_delivery', 'active', 'manual');
+    INSERT INTO task_queue.task (
+        entity_id,
+        task_type,
+        status,
+        attempt,
+        max_attempts,
+        scheduled_for
+    )
+    VALUES (
+        v_entity_id,
+        'webhook_delivery',
+        'pending',
+        0,
+        p_max_attempts,
+        NOW()
+    )
+    RETURNING task_id INTO v_task_id;
+    PERFORM task_queue.fnc__enqueue_task(v_task_id);
+    RETURN v_delivery_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The entity of a delivery is only needed while its task runs. Delivered
+-- deliveries give it up right away, failed ones keep it for a week so that
+-- their dead lettered task can still be requeued. Removing the entity also
+-- removes its finished tasks.
+SELECT cron.schedule(
+    'cleanup-webhook-delivery-entities',
+    '30 * * * *',
+    $$DELETE FROM task_queue.entity_registry e
+      WHERE e.entity_type = 'webhook_delivery'
+        AND NOT EXISTS (
+            SELECT 1 FROM task_queue.task t
+            WHERE t.entity_id = e.entity_id
+              AND t.status IN ('pending', 'processing')
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM public.webhook_deliveries d
+            WHERE d.webhook_deliveries_id = split_part(e.entity_id, ':', 2)::uuid
+              AND (
+                  d.webhook_deliveries_status = 'pending'
+                  OR (d.webhook_deliveries_status = 'failed' AND d.webhook_deliveries_created_at > NOW() - INTERVAL '7 days')
+              )
+        )$$
+)
+WHERE NOT EXISTS (
+    SELECT 1 FROM cron.job WHERE jobname = 'cleanup-webhook-delivery-entities'
+);
+
+---- create above / drop below ----
+
+SELECT cron.unschedule('cleanup-webhook-delivery-entities')
+WHERE EXISTS (
+    SELECT 1 FROM cron.job WHERE jobname = 'cleanup-webhook-delivery-entities'
+);
+
+DROP FUNCTION IF EXISTS public.fnc__create_webhook_delivery(UUID, UUID, TEXT, JSONB, INT);
+
+DELETE FROM task_queue.entity_registry WHERE entity_type = 'webhook_delivery';
+DELETE FROM task_queue.task_type_entity_type_mapping WHERE task_type = 'webhook_delivery';
+
+DROP TABLE IF EXISTS public.webhook_delivery_attempts;
+DROP TABLE IF EXISTS public.webhook_deliveries;
+DROP TABLE IF EXISTS public.webhook_subscriptions;
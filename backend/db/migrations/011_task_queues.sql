@@ -0,0 +1,440 @@
+-- Routes task types to dedicated pgmq queues so that each queue can be
+-- drained by its own worker pool. Task types without a row stay on the
+-- shared 'tasks' queue. The consumer rewrites the table from its worker pool
+-- configuration on startup.
+CREATE TABLE task_queue.task_type_queue (
+    task_type TEXT PRIMARY KEY,
+    queue_name TEXT NOT NULL,
+    -- how long a task of the type may run, used to tell running tasks apart
+    -- from stuck ones
+    task_timeout_seconds INT NOT NULL DEFAULT 300,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__task_queue_name(
+    p_task_type TEXT
+) RETURNS TEXT AS $$
+    SELECT COALESCE(
+        (SELECT queue_name FROM task_queue.task_type_queue WHERE task_type = p_task_type),
+        'tasks'
+    );
+$$ LANGUAGE sql STABLE;
+
+-- The queue a message was sent to, so that it can still be found after the
+-- task type is routed elsewhere. Messages sent so far went to 'tasks'.
+ALTER TABLE task_queue.task ADD COLUMN queue_name TEXT;
+
+UPDATE task_queue.task
+SET queue_name = 'tasks'
+WHERE queue_message_id IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__enqueue_task(
+    p_task_id BIGINT
+) RETURNS BIGINT AS $$
+DECLARE
+    v_task RECORD;
+    v_queue_name TEXT;
+    v_msg_id BIGINT;
+BEGIN
+    SELECT
+        task_id,
+        entity_id,
+        task_type,
+        attempt,
+        scheduled_for,
+        status,
+        queue_message_id
+    INTO v_task
+    FROM task_queue.task
+    WHERE task_id = p_task_id
+    FOR UPDATE;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'task_id % not found', p_task_id;
+    END IF;
+    IF v_task.status <> 'pending' THEN
+        RETURN NULL;
+    END IF;
+    IF v_task.queue_message_id IS NOT NULL THEN
+        RETURN v_task.queue_message_id;
+    END IF;
+    v_queue_name := task_queue.fnc__task_queue_name(v_task.task_type);
+    v_msg_id := pgmq.send(
+        v_queue_name,
+        jsonb_build_object(
+            'task_id', v_task.task_id,
+            'entity_id', v_task.entity_id,
+            'attempt', v_task.attempt
+        ),
+        v_task.scheduled_for
+    );
+    UPDATE task_queue.task
+    SET queue_message_id = v_msg_id,
+        queue_name = v_queue_name,
+        updated_at = NOW()
+    WHERE task_id = v_task.task_id;
+    RETURN v_msg_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Moves the messages of pending tasks whose task type has been routed to
+-- another queue since they were sent, so that they are not left in a queue
+-- no worker reads anymore.
+CREATE OR REPLACE FUNCTION task_queue.fnc__reroute_pending_tasks() RETURNS INT AS $$
+DECLARE
+    v_task RECORD;
+    v_rerouted INT := 0;
+BEGIN
+    FOR v_task IN
+        SELECT task_id, queue_name, queue_message_id
+        FROM task_queue.task
+        WHERE status = 'pending'
+          AND queue_message_id IS NOT NULL
+          AND queue_name IS DISTINCT FROM task_queue.fnc__task_queue_name(task_type)
+        FOR UPDATE SKIP LOCKED
+    LOOP
+        IF v_task.queue_name IS NOT NULL THEN
+            PERFORM pgmq.delete(v_task.queue_name, v_task.queue_message_id);
+        END IF;
+        UPDATE task_queue.task
+        SET queue_message_id = NULL,
+            queue_name = NULL,
+            updated_at = NOW()
+        WHERE task_id = v_task.task_id;
+        PERFORM task_queue.fnc__enqueue_task(v_task.task_id);
+        v_rerouted := v_rerouted + 1;
+    END LOOP;
+    RETURN v_rerouted;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Tasks are only considered stuck once they ran well past their task type's
+-- timeout, so long running queues are not requeued while still processing.
+CREATE OR REPLACE FUNCTION task_queue.fnc__requeue_stuck_tasks() RETURNS INT AS $$
+DECLARE
+    v_task RECORD;
+    v_requeued INT := 0;
+BEGIN
+    FOR v_task IN
+        SELECT t.task_id, t.attempt, t.max_attempts
+        FROM task_queue.task t
+        LEFT JOIN task_queue.task_type_queue q ON q.task_type = t.task_type
+        WHERE t.status = 'processing'
+          AND t.updated_at < NOW() - GREATEST(
+              INTERVAL '15 minutes',
+              COALESCE(q.task_timeout_seconds, 0) * INTERVAL '1 second' + INTERVAL '5 minutes'
+          )
+        FOR UPDATE OF t SKIP LOCKED
+    LOOP
+        IF v_task.attempt + 1 >= v_task.max_attempts THEN
+            UPDATE task_queue.task
+            SET status = 'failed',
+                attempt = LEAST(attempt + 1, max_attempts),
+                worker_id = NULL,
+                completed_at = NOW(),
+                updated_at = NOW(),
+                last_error = COALESCE(last_error, 'auto-failed after stuck timeout')
+            WHERE task_id = v_task.task_id;
+        ELSE
+            UPDATE task_queue.task
+            SET status = 'pending',
+                attempt = attempt + 1,
+                worker_id = NULL,
+                scheduled_for = NOW(),
+                started_at = NULL,
+                updated_at = NOW(),
+                queue_message_id = NULL,
+                queue_name = NULL
+            WHERE task_id = v_task.task_id;
+            PERFORM task_queue.fnc__enqueue_task(v_task.task_id);
+            v_requeued := v_requeued + 1;
+        END IF;
+    END LOOP;
+    RETURN v_requeued;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The sitemap and cities schedulers sent to the 'tasks' queue directly, they
+-- now go through fnc__enqueue_task to follow the routing.
+CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_frontdoor_sitemap_sync() RETURNS BIGINT AS $$
+DECLARE
+    v_task_id BIGINT;
+    v_msg_id BIGINT;
+    v_existing_task RECORD;
+BEGIN
+    SELECT task_id, status INTO v_existing_task
+    FROM task_queue.task
+    WHERE entity_id = 'frontdoor:sitemap'
+      AND task_type = 'frontdoor_sitemap_sync'
+      AND run_on = CURRENT_DATE
+    LIMIT 1;
+    IF FOUND THEN
+        IF v_existing_task.status IN ('pending', 'processing') THEN
+            RAISE NOTICE 'Frontdoor sitemap sync already scheduled for today (task_id: %)', v_existing_task.task_id;
+            RETURN v_existing_task.task_id;
+        END IF;
+    END IF;
+    INSERT INTO task_queue.task (
+        entity_id,
+        task_type,
+        status,
+        attempt,
+        max_attempts,
+        scheduled_for,
+        run_on
+    )
+    VALUES (
+        'frontdoor:sitemap',
+        'frontdoor_sitemap_sync',
+        'pending',
+        0,
+        3,
+        NOW(),
+        CURRENT_DATE
+    )
+    RETURNING task_id INTO v_task_id;
+    v_msg_id := task_queue.fnc__enqueue_task(v_task_id);
+    RAISE NOTICE 'Frontdoor sitemap sync scheduled (task_id: %, msg_id: %)', v_task_id, v_msg_id;
+    RETURN v_task_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_prices_cities_init() RETURNS BIGINT AS $$
+DECLARE
+    v_task_id BIGINT;
+    v_msg_id BIGINT;
+    v_existing_task RECORD;
+BEGIN
+    SELECT task_id, status INTO v_existing_task
+    FROM task_queue.task
+    WHERE entity_id = 'prices:cities'
+      AND task_type = 'prices_cities_init'
+      AND run_on = CURRENT_DATE
+    LIMIT 1;
+    IF FOUND THEN
+        IF v_existing_task.status IN ('pending', 'processing') THEN
+            RAISE NOTICE 'Prices cities init already scheduled for today (task_id: %)', v_existing_task.task_id;
+            RETURN v_existing_task.task_id;
+        END IF;
+    END IF;
+    INSERT INTO task_queue.task (
+        entity_id,
+        task_type,
+        status,
+        attempt,
+        max_attempts,
+        scheduled_for,
+        run_on
+    )
+    VALUES (
+        'prices:cities',
+        'prices_cities_init',
+        'pending',
+        0,
+        3,
+        NOW(),
+        CURRENT_DATE
+    )
+    RETURNING task_id INTO v_task_id;
+    v_msg_id := task_queue.fnc__enqueue_task(v_task_id);
+    RAISE NOTICE 'Prices cities init scheduled (task_id: %, msg_id: %)', v_task_id, v_msg_id;
+    RETURN v_task_id;
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_prices_cities_init() RETURNS BIGINT AS $$
+DECLARE
+    v_task_id BIGINT;
+    v_msg_id BIGINT;
+    v_existing_task RECORD;
+BEGIN
+    SELECT task_id, status INTO v_existing_task
+    FROM task_queue.task
+    WHERE entity_id = 'prices:cities'
+      AND task_type = 'prices_cities_init'
+      AND run_on = CURRENT_DATE
+    LIMIT 1;
+    IF FOUND THEN
+        IF v_existing_task.status IN ('pending', 'processing') THEN
+            RAISE NOTICE 'Prices cities init already scheduled for today (task_id: %)', v_existing_task.task_id;
+            RETURN v_existing_task.task_id;
+        END IF;
+    END IF;
+    INSERT INTO task_queue.task (
+        entity_id,
+        task_type,
+        status,
+        attempt,
+        max_attempts,
+        scheduled_for,
+        run_on
+    )
+    VALUES (
+        'prices:cities',
+        'prices_cities_init',
+        'pending',
+        0,
+        3,
+        NOW(),
+        CURRENT_DATE
+    )
+    RETURNING task_id INTO v_task_id;
+    v_msg_id := pgmq.send(
+        'tasks',
+        jsonb_build_object(
+            'task_id', v_task_id,
+            'entity_id', 'prices:cities',
+            'attempt', 0
+        )
+    );
+    UPDATE task_queue.task
+    SET queue_message_id = v_msg_id,
+        updated_at = NOW()
+    WHERE task_id = v_task_id;
+    RAISE NOTICE 'Prices cities init scheduled (task_id: %, msg_id: %)', v_task_id, v_msg_id;
+    RETURN v_task_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_frontdoor_sitemap_sync() RETURNS BIGINT AS $$
+DECLARE
+    v_task_id BIGINT;
+    v_msg_id BIGINT;
+    v_existing_task RECORD;
+BEGIN
+    SELECT task_id, status INTO v_existing_task
+    FROM task_queue.task
+    WHERE entity_id = 'frontdoor:sitemap'
+      AND task_type = 'frontdoor_sitemap_sync'
+      AND run_on = CURRENT_DATE
+    LIMIT 1;
+    IF FOUND THEN
+        IF v_existing_task.status IN ('pending', 'processing') THEN
+            RAISE NOTICE 'Frontdoor sitemap sync already scheduled for today (task_id: %)', v_existing_task.task_id;
+            RETURN v_existing_task.task_id;
+        END IF;
+    END IF;
+    INSERT INTO task_queue.task (
+        entity_id,
+        task_type,
+        status,
+        attempt,
+        max_attempts,
+        scheduled_for,
+        run_on
+    )
+    VALUES (
+        'frontdoor:sitemap',
+        'frontdoor_sitemap_sync',
+        'pending',
+        0,
+        3,
+        NOW(),
+        CURRENT_DATE
+    )
+    RETURNING task_id INTO v_task_id;
+    v_msg_id := pgmq.send(
+        'tasks',
+        jsonb_build_object(
+            'task_id', v_task_id,
+            'entity_id', 'frontdoor:sitemap',
+            'attempt', 0
+        )
+    );
+    UPDATE task_queue.task
+    SET queue_message_id = v_msg_id,
+        updated_at = NOW()
+    WHERE task_id = v_task_id;
+    RAISE NOTICE 'Frontdoor sitemap sync scheduled (task_id: %, msg_id: %)', v_task_id, v_msg_id;
+    RETURN v_task_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__requeue_stuck_tasks() RETURNS INT AS $$
+DECLARE
+    v_task RECORD;
+    v_requeued INT := 0;
+BEGIN
+    FOR v_task IN
+        SELECT task_id, attempt, max_attempts
+        FROM task_queue.task
+        WHERE status = 'processing'
+          AND updated_at < NOW() - INTERVAL '15 minutes'
+        FOR UPDATE SKIP LOCKED
+    LOOP
+        IF v_task.attempt + 1 >= v_task.max_attempts THEN
+            UPDATE task_queue.task
+            SET status = 'failed',
+                attempt = LEAST(attempt + 1, max_attempts),
+                worker_id = NULL,
+                completed_at = NOW(),
+                updated_at = NOW(),
+                last_error = COALESCE(last_error, 'auto-failed after stuck timeout')
+            WHERE task_id = v_task.task_id;
+        ELSE
+            UPDATE task_queue.task
+            SET status = 'pending',
+                attempt = attempt + 1,
+                worker_id = NULL,
+                scheduled_for = NOW(),
+                started_at = NULL,
+                updated_at = NOW(),
+                queue_message_id = NULL
+            WHERE task_id = v_task.task_id;
+            PERFORM task_queue.fnc__enqueue_task(v_task.task_id);
+            v_requeued := v_requeued + 1;
+        END IF;
+    END LOOP;
+    RETURN v_requeued;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__enqueue_task(
+    p_task_id BIGINT
+) RETURNS BIGINT AS $$
+DECLARE
+    v_task RECORD;
+    v_msg_id BIGINT;
+BEGIN
+    SELECT
+        task_id,
+        entity_id,
+        attempt,
+        scheduled_for,
+        status,
+        queue_message_id
+    INTO v_task
+    FROM task_queue.task
+    WHERE task_id = p_task_id
+    FOR UPDATE;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'task_id % not found', p_task_id;
+    END IF;
+    IF v_task.status <> 'pending' THEN
+        RETURN NULL;
+    END IF;
+    IF v_task.queue_message_id IS NOT NULL THEN
+        RETURN v_task.queue_message_id;
+    END IF;
+    v_msg_id := pgmq.send(
+        'tasks',
+        jsonb_build_object(
+            'task_id', v_task.task_id,
+            'entity_id', v_task.entity_id,
+            'attempt', v_task.attempt
+        ),
+        v_task.scheduled_for
+    );
+    UPDATE task_queue.task
+    SET queue_message_id = v_msg_id,
+        updated_at = NOW()
+    WHERE task_id = v_task.task_id;
+    RETURN v_msg_id;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS task_queue.fnc__reroute_pending_tasks();
+DROP FUNCTION IF EXISTS task_queue.fnc__task_queue_name(TEXT);
+DROP TABLE IF EXISTS task_queue.task_type_queue;
+ALTER TABLE task_queue.task DROP COLUMN IF EXISTS queue_name;
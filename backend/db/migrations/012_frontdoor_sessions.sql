@@ -0,0 +1,21 @@
+-- Sessions of the frontdoor client. The newest session that has not expired
+-- or been replaced is used, so a session obtained by one process is picked up
+-- by the others and survives restarts.
+CREATE TABLE public.frontdoor_sessions (
+    frontdoor_sessions_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    frontdoor_sessions_cookie text NOT NULL,
+    frontdoor_sessions_origin text NOT NULL
+        CHECK (frontdoor_sessions_origin IN ('seed', 'refresh')),
+    frontdoor_sessions_created_at timestamptz NOT NULL DEFAULT now(),
+    frontdoor_sessions_expires_at timestamptz NOT NULL,
+    frontdoor_sessions_replaced_at timestamptz
+);
+
+COMMENT ON COLUMN public.frontdoor_sessions.frontdoor_sessions_cookie IS 'Cookie header sent with every request of the session';
+COMMENT ON COLUMN public.frontdoor_sessions.frontdoor_sessions_origin IS 'seed for FRONTDOOR_COOKIE, refresh for a session the client obtained itself';
+
+CREATE INDEX idx_frontdoor_sessions_created_at ON public.frontdoor_sessions(frontdoor_sessions_created_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS public.frontdoor_sessions;
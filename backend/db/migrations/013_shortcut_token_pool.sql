@@ -0,0 +1,33 @@
+-- Health of the pooled shortcut tokens. A token that keeps failing is
+-- quarantined for a while and given up on once it fails too often.
+ALTER TABLE public.shortcut_tokens
+    ADD COLUMN shortcut_tokens_failure_count int4 NOT NULL DEFAULT 0,
+    ADD COLUMN shortcut_tokens_quarantined_until timestamptz,
+    ADD COLUMN shortcut_tokens_dead_at timestamptz;
+
+COMMENT ON COLUMN public.shortcut_tokens.shortcut_tokens_failure_count IS 'Consecutive requests the API rejected with the token';
+COMMENT ON COLUMN public.shortcut_tokens.shortcut_tokens_quarantined_until IS 'The token is left out of the rotation until then';
+COMMENT ON COLUMN public.shortcut_tokens.shortcut_tokens_dead_at IS 'When the token was given up on, it is removed by the cleanup job';
+
+SELECT cron.schedule(
+    'cleanup-shortcut-tokens',
+    '15 * * * *',
+    $$DELETE FROM public.shortcut_tokens
+      WHERE shortcut_tokens_expires_at < NOW()
+         OR shortcut_tokens_dead_at < NOW() - INTERVAL '1 day'$$
+)
+WHERE NOT EXISTS (
+    SELECT 1 FROM cron.job WHERE jobname = 'cleanup-shortcut-tokens'
+);
+
+---- create above / drop below ----
+
+SELECT cron.unschedule('cleanup-shortcut-tokens')
+WHERE EXISTS (
+    SELECT 1 FROM cron.job WHERE jobname = 'cleanup-shortcut-tokens'
+);
+
+ALTER TABLE public.shortcut_tokens
+    DROP COLUMN IF EXISTS shortcut_tokens_dead_at,
+    DROP COLUMN IF EXISTS shortcut_tokens_quarantined_until,
+    DROP COLUMN IF EXISTS shortcut_tokens_failure_count;
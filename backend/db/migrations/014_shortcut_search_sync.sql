@@ -0,0 +1,101 @@
+-- Walks the registered search:<postcode> entities through the shortcut area
+-- search, so that new ads are found before the sitemap lists them.
+INSERT INTO task_queue.task_type_entity_type_mapping (task_type, entity_type)
+VALUES ('shortcut_search_sync', 'shortcut_search')
+ON CONFLICT (task_type, entity_type) DO NOTHING;
+
+SELECT cron.schedule(
+    'schedule-daily-shortcut-search-syncs',
+    '15 2 * * *',
+    $$SELECT task_queue.fnc__schedule_daily_syncs('shortcut_search_sync')$$
+)
+WHERE NOT EXISTS (
+    SELECT 1 FROM cron.job WHERE jobname = 'schedule-daily-shortcut-search-syncs'
+);
+
+-- Records an ad found by the area search and returns whether it was new. The
+-- ad is registered for the daily syncs and, when it was new or not registered
+-- yet, its API sync is queued in the same transaction, so a failure in between
+-- cannot leave an ad that is never synced. An ad the search finds again after
+-- it was delisted gets a relisted history row with its last known prices.
+CREATE OR REPLACE FUNCTION public.fnc__record_shortcut_search_ad(
+    p_ad_id BIGINT,
+    p_url TEXT,
+    p_type TEXT
+) RETURNS BOOLEAN AS $$
+DECLARE
+    v_entity_id TEXT := 'ad:' || p_ad_id;
+    v_delisted_at TIMESTAMPTZ;
+    v_registered BOOLEAN;
+    v_inserted BOOLEAN;
+BEGIN
+    SELECT shortcut_ads_delisted_at INTO v_delisted_at
+    FROM public.shortcut_ads
+    WHERE shortcut_ads_id = p_ad_id
+    FOR UPDATE;
+
+    INSERT INTO public.shortcut_ads (
+        shortcut_ads_id,
+        shortcut_ads_url,
+        shortcut_ads_type
+    ) VALUES (
+        p_ad_id, p_url, p_type
+    )
+    ON CONFLICT (shortcut_ads_id) DO UPDATE SET
+        shortcut_ads_url = EXCLUDED.shortcut_ads_url,
+        shortcut_ads_type = EXCLUDED.shortcut_ads_type,
+        shortcut_ads_delisted_at = NULL,
+        shortcut_ads_last_seen_at = now(),
+        shortcut_ads_updated_at = CURRENT_TIMESTAMP
+    RETURNING (xmax = 0) INTO v_inserted;
+
+    IF v_delisted_at IS NOT NULL THEN
+        INSERT INTO public.shortcut_ad_history (
+            shortcut_ad_history_ad_id,
+            shortcut_ad_history_event,
+            shortcut_ad_history_price,
+            shortcut_ad_history_debt_free_price,
+            shortcut_ad_history_previous_price,
+            shortcut_ad_history_previous_debt_free_price,
+            shortcut_ad_history_status
+        )
+        SELECT
+            p_ad_id,
+            'relisted',
+            h.shortcut_ad_history_price,
+            h.shortcut_ad_history_debt_free_price,
+            h.shortcut_ad_history_price,
+            h.shortcut_ad_history_debt_free_price,
+            h.shortcut_ad_history_status
+        FROM (SELECT 1) AS one
+        LEFT JOIN LATERAL (
+            SELECT *
+            FROM public.shortcut_ad_history
+            WHERE shortcut_ad_history_ad_id = p_ad_id
+            ORDER BY shortcut_ad_history_recorded_at DESC
+            LIMIT 1
+        ) h ON TRUE;
+    END IF;
+
+    SELECT EXISTS (
+        SELECT 1 FROM task_queue.entity_registry WHERE entity_id = v_entity_id
+    ) INTO v_registered;
+    PERFORM task_queue.fnc__register_entities(ARRAY[v_entity_id], 'shortcut_ad', 'daily');
+    IF v_inserted OR NOT v_registered THEN
+        PERFORM task_queue.fnc__schedule_singleton_task(v_entity_id, 'shortcut_api_sync', 0, 3);
+    END IF;
+    RETURN v_inserted;
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+
+DROP FUNCTION IF EXISTS public.fnc__record_shortcut_search_ad(BIGINT, TEXT, TEXT);
+
+SELECT cron.unschedule('schedule-daily-shortcut-search-syncs')
+WHERE EXISTS (
+    SELECT 1 FROM cron.job WHERE jobname = 'schedule-daily-shortcut-search-syncs'
+);
+
+DELETE FROM task_queue.task_type_entity_type_mapping
+WHERE task_type = 'shortcut_search_sync';
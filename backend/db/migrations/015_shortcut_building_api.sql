@@ -0,0 +1,25 @@
+-- Structured data of the shortcut building API. The scraped columns stay as
+-- the fallback for buildings the API does not know.
+ALTER TABLE public.shortcut_buildings
+    ADD COLUMN shortcut_buildings_vrk_id text,
+    ADD COLUMN shortcut_buildings_size_min int4,
+    ADD COLUMN shortcut_buildings_size_max int4,
+    ADD COLUMN shortcut_buildings_media jsonb,
+    ADD COLUMN shortcut_buildings_api_synced_at timestamptz;
+
+COMMENT ON COLUMN public.shortcut_buildings.shortcut_buildings_vrk_id IS 'Permanent building identifier of the national population register';
+COMMENT ON COLUMN public.shortcut_buildings.shortcut_buildings_size_min IS 'Smallest apartment of the building in square metres';
+COMMENT ON COLUMN public.shortcut_buildings.shortcut_buildings_size_max IS 'Largest apartment of the building in square metres';
+
+CREATE INDEX idx_shortcut_buildings_vrk_id ON public.shortcut_buildings(shortcut_buildings_vrk_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS public.idx_shortcut_buildings_vrk_id;
+
+ALTER TABLE public.shortcut_buildings
+    DROP COLUMN IF EXISTS shortcut_buildings_api_synced_at,
+    DROP COLUMN IF EXISTS shortcut_buildings_media,
+    DROP COLUMN IF EXISTS shortcut_buildings_size_max,
+    DROP COLUMN IF EXISTS shortcut_buildings_size_min,
+    DROP COLUMN IF EXISTS shortcut_buildings_vrk_id;
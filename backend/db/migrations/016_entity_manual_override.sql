@@ -0,0 +1,50 @@
+-- Entities whose status or scheduling strategy was changed through the admin
+-- API. The sitemap crawls register their entities again every day, which
+-- must not resume a paused entity or undo its strategy.
+ALTER TABLE task_queue.entity_registry
+    ADD COLUMN manual_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__register_entities(
+    p_entity_ids TEXT[],
+    p_entity_type TEXT,
+    p_scheduling_strategy TEXT DEFAULT 'daily'
+) RETURNS INT AS $$
+DECLARE
+    v_count INT;
+BEGIN
+    INSERT INTO task_queue.entity_registry AS r (entity_id, entity_type, status, scheduling_strategy)
+    SELECT unnest(p_entity_ids), p_entity_type, 'active', p_scheduling_strategy
+    ON CONFLICT (entity_id) DO UPDATE
+    SET status = CASE WHEN r.manual_override AND r.status = 'stopped' THEN r.status ELSE 'active' END,
+        entity_type = EXCLUDED.entity_type,
+        scheduling_strategy = CASE WHEN r.manual_override THEN r.scheduling_strategy ELSE EXCLUDED.scheduling_strategy END,
+        updated_at = NOW();
+    GET DIAGNOSTICS v_count = ROW_COUNT;
+    RETURN v_count;
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__register_entities(
+    p_entity_ids TEXT[],
+    p_entity_type TEXT,
+    p_scheduling_strategy TEXT DEFAULT 'daily'
+) RETURNS INT AS $$
+DECLARE
+    v_count INT;
+BEGIN
+    INSERT INTO task_queue.entity_registry (entity_id, entity_type, status, scheduling_strategy)
+    SELECT unnest(p_entity_ids), p_entity_type, 'active', p_scheduling_strategy
+    ON CONFLICT (entity_id) DO UPDATE
+    SET status = 'active',
+        entity_type = EXCLUDED.entity_type,
+        scheduling_strategy = EXCLUDED.scheduling_strategy,
+        updated_at = NOW();
+    GET DIAGNOSTICS v_count = ROW_COUNT;
+    RETURN v_count;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE task_queue.entity_registry
+    DROP COLUMN IF EXISTS manual_override;
@@ -86,6 +86,15 @@ CREATE TABLE public.prices_transactions (
 CREATE INDEX idx_prices_transactions_period_identifier
     ON public.prices_transactions(prices_transactions_period_identifier);
 
+CREATE INDEX idx_prices_transactions_neighborhood_price_per_sqm
+    ON public.prices_transactions(prices_neighborhoods_id, prices_transactions_price_per_square_meter, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_price
+    ON public.prices_transactions(prices_transactions_price, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_build_year
+    ON public.prices_transactions(prices_transactions_build_year, prices_transactions_id);
+
 -- ============================================
 -- Task Queue Schema
 -- ============================================
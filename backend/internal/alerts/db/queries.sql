@@ -0,0 +1,97 @@
+-- name: CreateSavedSearch :one
+INSERT INTO public.saved_searches (
+    saved_searches_name,
+    saved_searches_source,
+    saved_searches_city,
+    saved_searches_postcodes,
+    saved_searches_min_price,
+    saved_searches_max_price,
+    saved_searches_min_rooms,
+    saved_searches_max_rooms,
+    saved_searches_min_area,
+    saved_searches_max_area,
+    saved_searches_notifier,
+    saved_searches_target,
+    saved_searches_enabled
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+)
+RETURNING *;
+
+-- name: GetSavedSearch :one
+SELECT * FROM public.saved_searches
+WHERE saved_searches_id = $1;
+
+-- name: ListSavedSearches :many
+SELECT * FROM public.saved_searches
+ORDER BY saved_searches_created_at DESC, saved_searches_id
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: UpdateSavedSearch :one
+UPDATE public.saved_searches
+SET saved_searches_name = $2,
+    saved_searches_source = $3,
+    saved_searches_city = $4,
+    saved_searches_postcodes = $5,
+    saved_searches_min_price = $6,
+    saved_searches_max_price = $7,
+    saved_searches_min_rooms = $8,
+    saved_searches_max_rooms = $9,
+    saved_searches_min_area = $10,
+    saved_searches_max_area = $11,
+    saved_searches_notifier = $12,
+    saved_searches_target = $13,
+    saved_searches_enabled = $14,
+    saved_searches_updated_at = now()
+WHERE saved_searches_id = $1
+RETURNING *;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM public.saved_searches
+WHERE saved_searches_id = $1;
+
+-- name: EvaluateSavedSearches :one
+SELECT public.fnc__evaluate_saved_searches()::int4 AS count;
+
+-- name: ListPendingAlerts :many
+SELECT
+    o.saved_search_outbox_id,
+    o.saved_search_outbox_listing_id,
+    o.saved_search_outbox_payload,
+    o.saved_search_outbox_attempts,
+    o.saved_search_outbox_created_at,
+    s.saved_searches_id,
+    s.saved_searches_name,
+    s.saved_searches_notifier,
+    s.saved_searches_target
+FROM public.saved_search_outbox o
+JOIN public.saved_searches s ON s.saved_searches_id = o.saved_search_outbox_saved_search_id
+WHERE o.saved_search_outbox_status = 'pending'
+    AND s.saved_searches_enabled
+ORDER BY o.saved_search_outbox_created_at
+LIMIT $1;
+
+-- name: MarkAlertDelivered :exec
+UPDATE public.saved_search_outbox
+SET saved_search_outbox_status = 'delivered',
+    saved_search_outbox_attempts = saved_search_outbox_attempts + 1,
+    saved_search_outbox_last_error = NULL,
+    saved_search_outbox_delivered_at = now()
+WHERE saved_search_outbox_id = $1;
+
+-- name: MarkAlertAttemptFailed :exec
+UPDATE public.saved_search_outbox
+SET saved_search_outbox_attempts = saved_search_outbox_attempts + 1,
+    saved_search_outbox_last_error = sqlc.arg('last_error')::text,
+    saved_search_outbox_status = CASE
+        WHEN saved_search_outbox_attempts + 1 >= sqlc.arg('max_attempts')::int4 THEN 'failed'
+        ELSE 'pending'
+    END
+WHERE saved_search_outbox_id = sqlc.arg('id');
+
+-- name: ListSavedSearchAlerts :many
+SELECT * FROM public.saved_search_outbox
+WHERE saved_search_outbox_saved_search_id = sqlc.arg('saved_search_id')
+    AND (sqlc.narg('status')::text IS NULL OR saved_search_outbox_status = sqlc.narg('status')::text)
+ORDER BY saved_search_outbox_created_at DESC, saved_search_outbox_id
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
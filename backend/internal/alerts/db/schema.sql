@@ -0,0 +1,37 @@
+CREATE TABLE public.saved_searches (
+    saved_searches_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    saved_searches_name text NOT NULL,
+    saved_searches_source text,
+    saved_searches_city text,
+    saved_searches_postcodes text[] NOT NULL DEFAULT '{}',
+    saved_searches_min_price float8,
+    saved_searches_max_price float8,
+    saved_searches_min_rooms int4,
+    saved_searches_max_rooms int4,
+    saved_searches_min_area float8,
+    saved_searches_max_area float8,
+    saved_searches_notifier text NOT NULL DEFAULT 'webhook',
+    saved_searches_target text NOT NULL,
+    saved_searches_enabled bool NOT NULL DEFAULT true,
+    saved_searches_last_evaluated_at timestamptz,
+    saved_searches_created_at timestamptz NOT NULL DEFAULT now(),
+    saved_searches_updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (saved_searches_id)
+);
+
+CREATE TABLE public.saved_search_outbox (
+    saved_search_outbox_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    saved_search_outbox_saved_search_id uuid NOT NULL,
+    saved_search_outbox_listing_id text NOT NULL,
+    saved_search_outbox_payload jsonb NOT NULL,
+    saved_search_outbox_status text NOT NULL DEFAULT 'pending',
+    saved_search_outbox_attempts int4 NOT NULL DEFAULT 0,
+    saved_search_outbox_last_error text,
+    saved_search_outbox_created_at timestamptz NOT NULL DEFAULT now(),
+    saved_search_outbox_delivered_at timestamptz,
+    PRIMARY KEY (saved_search_outbox_id),
+    UNIQUE (saved_search_outbox_saved_search_id, saved_search_outbox_listing_id)
+);
+
+-- Function signatures for sqlc
+CREATE OR REPLACE FUNCTION public.fnc__evaluate_saved_searches() RETURNS int AS $$ BEGIN END; $$ LANGUAGE plpgsql;
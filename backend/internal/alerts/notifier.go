@@ -0,0 +1,24 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Alert is a listing that matched a saved search.
+type Alert struct {
+	ID              string          `json:"id"`
+	SavedSearchID   string          `json:"saved_search_id"`
+	SavedSearchName string          `json:"saved_search_name"`
+	ListingID       string          `json:"listing_id"`
+	Listing         json.RawMessage `json:"listing"`
+	MatchedAt       time.Time       `json:"matched_at"`
+}
+
+// Notifier delivers alerts over one channel. The target is channel specific,
+// e.g. the URL of a webhook.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, target string, alert Alert) error
+}
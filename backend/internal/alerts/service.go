@@ -0,0 +1,87 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+
+	"koditon-go/internal/alerts/db"
+	"koditon-go/internal/util"
+)
+
+const (
+	// MaxDeliveryAttempts is how many times an alert is tried before it is
+	// marked failed.
+	MaxDeliveryAttempts = 5
+	deliveryBatchSize   = 500
+)
+
+type Service struct {
+	queries   *db.Queries
+	notifiers map[string]Notifier
+}
+
+func NewService(dbtx db.DBTX, notifiers ...Notifier) *Service {
+	byName := make(map[string]Notifier, len(notifiers))
+	for _, n := range notifiers {
+		byName[n.Name()] = n
+	}
+	return &Service{
+		queries:   db.New(dbtx),
+		notifiers: byName,
+	}
+}
+
+// EvaluateSavedSearches records the new listings matching enabled saved
+// searches in the outbox and returns how many were recorded.
+func (s *Service) EvaluateSavedSearches(ctx context.Context) (int, error) {
+	count, err := s.queries.EvaluateSavedSearches(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("evaluate saved searches: %w", err)
+	}
+	return int(count), nil
+}
+
+// DeliverAlerts sends a batch of pending alerts through the notifier of their
+// saved search. An alert that fails stays pending for the next run until it
+// runs out of attempts.
+func (s *Service) DeliverAlerts(ctx context.Context) (delivered, failed int, err error) {
+	pending, err := s.queries.ListPendingAlerts(ctx, deliveryBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("list pending alerts: %w", err)
+	}
+	for _, row := range pending {
+		alert := Alert{
+			ID:              util.FromUUID(row.SavedSearchOutboxID),
+			SavedSearchID:   util.FromUUID(row.SavedSearchesID),
+			SavedSearchName: row.SavedSearchesName,
+			ListingID:       row.SavedSearchOutboxListingID,
+			Listing:         row.SavedSearchOutboxPayload,
+			MatchedAt:       row.SavedSearchOutboxCreatedAt,
+		}
+		notifyErr := s.notify(ctx, row.SavedSearchesNotifier, row.SavedSearchesTarget, alert)
+		if notifyErr == nil {
+			if err := s.queries.MarkAlertDelivered(ctx, row.SavedSearchOutboxID); err != nil {
+				return delivered, failed, fmt.Errorf("mark alert %s delivered: %w", alert.ID, err)
+			}
+			delivered++
+			continue
+		}
+		if err := s.queries.MarkAlertAttemptFailed(ctx, &db.MarkAlertAttemptFailedParams{
+			LastError:   notifyErr.Error(),
+			MaxAttempts: MaxDeliveryAttempts,
+			ID:          row.SavedSearchOutboxID,
+		}); err != nil {
+			return delivered, failed, fmt.Errorf("mark alert %s attempt failed: %w", alert.ID, err)
+		}
+		failed++
+	}
+	return delivered, failed, nil
+}
+
+func (s *Service) notify(ctx context.Context, notifierName, target string, alert Alert) error {
+	notifier, ok := s.notifiers[notifierName]
+	if !ok {
+		return fmt.Errorf("no notifier registered for %q", notifierName)
+	}
+	return notifier.Notify(ctx, target, alert)
+}
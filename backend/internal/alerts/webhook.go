@@ -0,0 +1,40 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"koditon-go/internal/webhooks"
+)
+
+const NotifierWebhook = "webhook"
+
+// WebhookNotifier hands alerts to the webhook subscription the target names.
+// The webhooks service signs, retries and logs the delivery like any other
+// webhook event, so an alert counts as delivered once it is queued there.
+type WebhookNotifier struct {
+	webhooks *webhooks.Service
+}
+
+func NewWebhookNotifier(webhooksService *webhooks.Service) *WebhookNotifier {
+	return &WebhookNotifier{
+		webhooks: webhooksService,
+	}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return NotifierWebhook
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, target string, alert Alert) error {
+	subscriptionID, err := uuid.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid webhook subscription id %q: %w", target, err)
+	}
+	if err := n.webhooks.PublishTo(ctx, subscriptionID, webhooks.EventSavedSearchMatched, alert); err != nil {
+		return fmt.Errorf("queue webhook: %w", err)
+	}
+	return nil
+}
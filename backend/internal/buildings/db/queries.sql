@@ -0,0 +1,76 @@
+-- name: SearchBuildings :many
+SELECT
+    building_id,
+    source,
+    address,
+    build_year,
+    latitude,
+    longitude
+FROM public.fnc__search_buildings(
+    sqlc.narg('min_longitude')::float8,
+    sqlc.narg('min_latitude')::float8,
+    sqlc.narg('max_longitude')::float8,
+    sqlc.narg('max_latitude')::float8,
+    sqlc.narg('latitude')::float8,
+    sqlc.narg('longitude')::float8,
+    sqlc.narg('radius_meters')::float8,
+    sqlc.narg('polygon')::text,
+    sqlc.arg('page_size')::int
+);
+
+-- name: ClusterBuildings :many
+SELECT
+    point_count,
+    latitude,
+    longitude,
+    building_id,
+    source
+FROM public.fnc__cluster_buildings(
+    sqlc.narg('min_longitude')::float8,
+    sqlc.narg('min_latitude')::float8,
+    sqlc.narg('max_longitude')::float8,
+    sqlc.narg('max_latitude')::float8,
+    sqlc.narg('latitude')::float8,
+    sqlc.narg('longitude')::float8,
+    sqlc.narg('radius_meters')::float8,
+    sqlc.narg('polygon')::text,
+    sqlc.arg('grid_size')::float8,
+    sqlc.arg('max_points')::int
+);
+
+-- name: MatchBuildings :one
+SELECT public.fnc__match_buildings(sqlc.arg('min_confidence')::float8)::int AS linked_count;
+
+-- name: GetLinkedShortcutBuildingID :one
+SELECT building_links_shortcut_building_id FROM public.building_links
+WHERE building_links_frontdoor_building_id = $1
+  AND building_links_status = 'linked';
+
+-- name: GetLinkedFrontdoorBuildingID :one
+SELECT building_links_frontdoor_building_id FROM public.building_links
+WHERE building_links_shortcut_building_id = $1
+  AND building_links_status = 'linked';
+
+-- name: GetBuildingLinkByID :one
+SELECT * FROM public.building_links
+WHERE building_links_id = $1;
+
+-- name: ListBuildingLinks :many
+SELECT * FROM public.building_links
+WHERE (sqlc.narg('status')::text IS NULL OR building_links_status = sqlc.narg('status')::text)
+  AND (sqlc.narg('method')::text IS NULL OR building_links_method = sqlc.narg('method')::text)
+  AND (sqlc.narg('max_confidence')::float8 IS NULL OR building_links_confidence <= sqlc.narg('max_confidence')::float8)
+ORDER BY building_links_confidence ASC, building_links_id
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: SetBuildingLink :one
+SELECT public.fnc__set_building_link(
+    sqlc.arg('frontdoor_building_id')::uuid,
+    sqlc.arg('shortcut_building_id')::uuid,
+    sqlc.arg('status')::text,
+    sqlc.narg('note')::text
+)::uuid AS building_links_id;
+
+-- name: DeleteBuildingLink :execrows
+DELETE FROM public.building_links
+WHERE building_links_id = $1;
@@ -0,0 +1,67 @@
+CREATE TABLE public.building_links (
+    building_links_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    building_links_frontdoor_building_id uuid NOT NULL,
+    building_links_shortcut_building_id uuid NOT NULL,
+    building_links_status text NOT NULL DEFAULT 'linked',
+    building_links_method text NOT NULL DEFAULT 'auto',
+    building_links_confidence float8 NOT NULL,
+    building_links_distance_meters float8,
+    building_links_address_match bool NOT NULL DEFAULT false,
+    building_links_postcode_match bool NOT NULL DEFAULT false,
+    building_links_build_year_match bool NOT NULL DEFAULT false,
+    building_links_note text,
+    building_links_created_at timestamptz NOT NULL DEFAULT now(),
+    building_links_updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (building_links_id),
+    UNIQUE (building_links_frontdoor_building_id, building_links_shortcut_building_id)
+);
+
+-- Function signatures for sqlc
+CREATE OR REPLACE FUNCTION public.fnc__match_buildings(
+    p_min_confidence float8 DEFAULT 0.5
+) RETURNS int AS $$ BEGIN END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.fnc__set_building_link(
+    p_frontdoor_building_id uuid,
+    p_shortcut_building_id uuid,
+    p_status text,
+    p_note text
+) RETURNS uuid AS $$ BEGIN END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.fnc__search_buildings(
+    p_min_longitude float8,
+    p_min_latitude float8,
+    p_max_longitude float8,
+    p_max_latitude float8,
+    p_latitude float8,
+    p_longitude float8,
+    p_radius_meters float8,
+    p_polygon text,
+    p_limit int
+) RETURNS TABLE(
+    building_id uuid,
+    source text,
+    address text,
+    build_year int4,
+    latitude float8,
+    longitude float8
+) AS $$ BEGIN END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.fnc__cluster_buildings(
+    p_min_longitude float8,
+    p_min_latitude float8,
+    p_max_longitude float8,
+    p_max_latitude float8,
+    p_latitude float8,
+    p_longitude float8,
+    p_radius_meters float8,
+    p_polygon text,
+    p_grid_size float8,
+    p_max_points int
+) RETURNS TABLE(
+    point_count int8,
+    latitude float8,
+    longitude float8,
+    building_id uuid,
+    source text
+) AS $$ BEGIN END; $$ LANGUAGE plpgsql;
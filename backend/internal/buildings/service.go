@@ -0,0 +1,32 @@
+package buildings
+
+import (
+	"context"
+	"fmt"
+
+	"koditon-go/internal/buildings/db"
+)
+
+// DefaultMinMatchConfidence is the lowest score at which a frontdoor and a
+// shortcut building are linked automatically.
+const DefaultMinMatchConfidence = 0.5
+
+type Service struct {
+	queries *db.Queries
+}
+
+func NewService(dbtx db.DBTX) *Service {
+	return &Service{
+		queries: db.New(dbtx),
+	}
+}
+
+// MatchBuildings rewrites the automatic building links and returns how many
+// pairs are linked. Manual links and rejections are left as they are.
+func (s *Service) MatchBuildings(ctx context.Context) (int, error) {
+	count, err := s.queries.MatchBuildings(ctx, DefaultMinMatchConfidence)
+	if err != nil {
+		return 0, fmt.Errorf("match buildings: %w", err)
+	}
+	return int(count), nil
+}
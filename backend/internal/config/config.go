@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -8,6 +9,9 @@ import (
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
+
+	shortcutclient "koditon-go/internal/shortcut/client"
+	"koditon-go/internal/transport"
 )
 
 type Environment string
@@ -31,6 +35,7 @@ type Config struct {
 	Prices          PricesConfig
 	Shortcut        ShortcutConfig
 	Frontdoor       FrontdoorConfig
+	Workers         WorkersConfig `envPrefix:"WORKER_"`
 }
 
 func (c Config) SlogLevel() slog.Level {
@@ -58,22 +63,124 @@ type DBConfig struct {
 }
 
 type PricesConfig struct {
-	BaseURL string `env:"PRICES_BASE_URL,required"`
+	BaseURL string       `env:"PRICES_BASE_URL,required"`
+	Limits  LimitsConfig `envPrefix:"PRICES_"`
 }
 
 type ShortcutConfig struct {
-	BaseURL     string `env:"SHORTCUT_BASE_URL,required"`
-	DocsBaseURL string `env:"SHORTCUT_DOCS_BASE_URL,required"`
-	AdBaseURL   string `env:"SHORTCUT_AD_BASE_URL,required"`
-	UserAgent   string `env:"SHORTCUT_USER_AGENT,required"`
-	SitemapBase string `env:"SHORTCUT_SITEMAP_BASE_URL,required"`
+	BaseURL     string          `env:"SHORTCUT_BASE_URL,required"`
+	DocsBaseURL string          `env:"SHORTCUT_DOCS_BASE_URL,required"`
+	AdBaseURL   string          `env:"SHORTCUT_AD_BASE_URL,required"`
+	UserAgent   string          `env:"SHORTCUT_USER_AGENT,required"`
+	SitemapBase string          `env:"SHORTCUT_SITEMAP_BASE_URL,required"`
+	Limits      LimitsConfig    `envPrefix:"SHORTCUT_"`
+	TokenPool   TokenPoolConfig `envPrefix:"SHORTCUT_TOKEN_POOL_"`
+	// SearchPostcodes are the areas the search sync walks every day, e.g.
+	// SHORTCUT_SEARCH_POSTCODES=00100,00120.
+	SearchPostcodes []string `env:"SHORTCUT_SEARCH_POSTCODES" envSeparator:","`
+}
+
+// TokenPoolConfig sizes the pool of anonymous user tokens the shortcut client
+// rotates over, e.g. SHORTCUT_TOKEN_POOL_SIZE=3. A token the API rejects is
+// quarantined for QUARANTINE times its consecutive failures and given up on
+// after MAX_FAILURES.
+type TokenPoolConfig struct {
+	Size        int           `env:"SIZE" envDefault:"3"`
+	MaxFailures int           `env:"MAX_FAILURES" envDefault:"3"`
+	Quarantine  time.Duration `env:"QUARANTINE" envDefault:"5m"`
 }
 
+func (c TokenPoolConfig) ClientConfig() shortcutclient.TokenPoolConfig {
+	return shortcutclient.TokenPoolConfig{
+		Size:        c.Size,
+		MaxFailures: c.MaxFailures,
+		Quarantine:  c.Quarantine,
+	}
+}
+
+// FrontdoorConfig configures the frontdoor client. FRONTDOOR_COOKIE is
+// optional, it seeds the session until the site rejects it; without it the
+// client obtains a session itself.
 type FrontdoorConfig struct {
-	BaseURL     string `env:"FRONTDOOR_BASE_URL,required"`
-	UserAgent   string `env:"FRONTDOOR_USER_AGENT,required"`
-	Cookie      string `env:"FRONTDOOR_COOKIE,required"`
-	SitemapBase string `env:"FRONTDOOR_SITEMAP_BASE_URL,required"`
+	BaseURL     string       `env:"FRONTDOOR_BASE_URL,required"`
+	UserAgent   string       `env:"FRONTDOOR_USER_AGENT,required"`
+	Cookie      string       `env:"FRONTDOOR_COOKIE"`
+	SitemapBase string       `env:"FRONTDOOR_SITEMAP_BASE_URL,required"`
+	Limits      LimitsConfig `envPrefix:"FRONTDOOR_"`
+}
+
+// LimitsConfig throttles the requests of a source to each host it crawls,
+// e.g. FRONTDOOR_RATE_LIMIT=2 for two requests per second.
+type LimitsConfig struct {
+	RateLimit     float64       `env:"RATE_LIMIT" envDefault:"1"`
+	RateBurst     int           `env:"RATE_BURST" envDefault:"2"`
+	MaxConcurrent int           `env:"MAX_CONCURRENT" envDefault:"2"`
+	MaxRetryAfter time.Duration `env:"MAX_RETRY_AFTER" envDefault:"10m"`
+}
+
+func (c LimitsConfig) TransportLimits() transport.Limits {
+	return transport.Limits{
+		RequestsPerSecond: c.RateLimit,
+		Burst:             c.RateBurst,
+		MaxConcurrent:     c.MaxConcurrent,
+		MaxRetryAfter:     c.MaxRetryAfter,
+	}
+}
+
+// WorkersConfig sizes the worker pool of the shared task queue and lists the
+// dedicated pools, e.g.
+//
+//	WORKER_POOLS=[{"queue":"prices","task_types":["prices_sync"],"workers":1,"task_timeout":"30m","visibility_timeout":"35m"}]
+type WorkersConfig struct {
+	Count             int           `env:"COUNT" envDefault:"1"`
+	VisibilityTimeout time.Duration `env:"VISIBILITY_TIMEOUT" envDefault:"5m"`
+	TaskTimeout       time.Duration `env:"TASK_TIMEOUT" envDefault:"5m"`
+	Pools             WorkerPools   `env:"POOLS"`
+	Breaker           BreakerConfig `envPrefix:"BREAKER_"`
+}
+
+// BreakerConfig trips the circuit breaker of a source once FAILURE_RATE of its
+// last WINDOW tasks failed, e.g. WORKER_BREAKER_FAILURE_RATE=0.5. The breaker
+// holds the tasks of the source back for OPEN_DURATION, doubled after every
+// failed probe up to MAX_OPEN_DURATION.
+type BreakerConfig struct {
+	FailureRate     float64       `env:"FAILURE_RATE" envDefault:"0.5"`
+	Window          int           `env:"WINDOW" envDefault:"20"`
+	MinTasks        int           `env:"MIN_TASKS" envDefault:"10"`
+	OpenDuration    time.Duration `env:"OPEN_DURATION" envDefault:"5m"`
+	MaxOpenDuration time.Duration `env:"MAX_OPEN_DURATION" envDefault:"1h"`
+}
+
+// WorkerPools is a JSON list of dedicated worker pools.
+type WorkerPools []WorkerPoolConfig
+
+func (p *WorkerPools) UnmarshalText(text []byte) error {
+	if err := json.Unmarshal(text, (*[]WorkerPoolConfig)(p)); err != nil {
+		return fmt.Errorf("parse worker pools: %w", err)
+	}
+	return nil
+}
+
+// WorkerPoolConfig is a pool of workers draining a queue of its own. Zero
+// timeouts fall back to the ones of the shared pool.
+type WorkerPoolConfig struct {
+	Queue             string   `json:"queue"`
+	TaskTypes         []string `json:"task_types"`
+	Count             int      `json:"workers"`
+	VisibilityTimeout Duration `json:"visibility_timeout"`
+	TaskTimeout       Duration `json:"task_timeout"`
+}
+
+// Duration is a time.Duration read from strings such as "30m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
 }
 
 func Load() (Config, error) {
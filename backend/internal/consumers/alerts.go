@@ -0,0 +1,37 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+const (
+	savedSearchEvaluationEntityID    = "saved_searches:evaluation"
+	savedSearchEvaluationMaxAttempts = 3
+)
+
+// handleSavedSearchEvaluation records new saved search matches and delivers
+// the pending alerts. Failed deliveries are retried by later runs, so they do
+// not fail the task.
+func (c *Consumer) handleSavedSearchEvaluation(ctx context.Context, logger *slog.Logger, _ taskqueuedb.TaskQueueTask) error {
+	matched, err := c.alertsService.EvaluateSavedSearches(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "saved search evaluation failed", "error", err)
+		return err
+	}
+	delivered, failed, err := c.alertsService.DeliverAlerts(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "alert delivery failed", "error", err)
+		return err
+	}
+	logger.InfoContext(ctx, "saved search evaluation completed", "matched", matched, "delivered", delivered, "failed", failed)
+	return nil
+}
+
+// scheduleSavedSearchEvaluation queues an evaluation after a new ad was
+// stored.
+func (c *Consumer) scheduleSavedSearchEvaluation(ctx context.Context, logger *slog.Logger) {
+	c.scheduleSingletonTask(ctx, logger, savedSearchEvaluationEntityID, taskTypeSavedSearchEvaluation, savedSearchEvaluationMaxAttempts)
+}
@@ -0,0 +1,105 @@
+package consumers
+
+import (
+	"time"
+
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+// newBreakers gives every registered source a circuit breaker. The core task
+// types do not depend on a single upstream and run unguarded.
+func (c *Consumer) newBreakers(cfg taskqueue.BreakerConfig) map[string]*taskqueue.Breaker {
+	breakers := make(map[string]*taskqueue.Breaker)
+	for _, source := range c.registry.Sources() {
+		if _, ok := source.(*coreSource); ok {
+			continue
+		}
+		breakers[source.Name()] = taskqueue.NewBreaker(source.Name(), cfg, c.logger)
+	}
+	return breakers
+}
+
+// gateTask holds back the tasks of a source whose breaker is open.
+func (c *Consumer) gateTask(task taskqueuedb.TaskQueueTask) (time.Duration, bool) {
+	source, _, ok := c.registry.Lookup(task.TaskType)
+	if !ok {
+		return 0, true
+	}
+	breaker, ok := c.breakers[source.Name()]
+	if !ok {
+		return 0, true
+	}
+	return breaker.Allow(task.TaskID)
+}
+
+// holdGroup returns the task types of the source of a held task, whose
+// queued tasks are held back along with it.
+func (c *Consumer) holdGroup(taskType string) []string {
+	source, _, ok := c.registry.Lookup(taskType)
+	if !ok {
+		return nil
+	}
+	taskTypes := source.TaskTypes()
+	names := make([]string, 0, len(taskTypes))
+	for _, t := range taskTypes {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// poolPaused reports whether every task type of a dedicated pool belongs to a
+// source whose breaker is open. The shared pool is never paused.
+func (c *Consumer) poolPaused(pool PoolConfig) func() bool {
+	var breakers []*taskqueue.Breaker
+	for _, taskType := range pool.TaskTypes {
+		source, _, ok := c.registry.Lookup(taskType)
+		if !ok {
+			return nil
+		}
+		breaker, ok := c.breakers[source.Name()]
+		if !ok {
+			return nil
+		}
+		breakers = append(breakers, breaker)
+	}
+	if len(breakers) == 0 {
+		return nil
+	}
+	return func() bool {
+		for _, breaker := range breakers {
+			if !breaker.Paused() {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// recordOutcome feeds the breaker of the source with the classified result of
+// a task. Only errors classified as failures of the source count against it;
+// anything else, like a database error, is about the task.
+func (c *Consumer) recordOutcome(source sources.Source, taskID int64, err error) {
+	breaker, ok := c.breakers[source.Name()]
+	if !ok {
+		return
+	}
+	if sources.IsSourceFailure(err) {
+		breaker.Record(taskID, err)
+		return
+	}
+	breaker.Record(taskID, nil)
+}
+
+// Breakers reports the circuit breaker of every source, in registration
+// order.
+func (c *Consumer) Breakers() []taskqueue.BreakerStatus {
+	var statuses []taskqueue.BreakerStatus
+	for _, source := range c.registry.Sources() {
+		if breaker, ok := c.breakers[source.Name()]; ok {
+			statuses = append(statuses, breaker.Status())
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,29 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+const (
+	buildingMatchingEntityID    = "buildings:matching"
+	buildingMatchingMaxAttempts = 3
+)
+
+func (c *Consumer) handleBuildingMatching(ctx context.Context, logger *slog.Logger, _ taskqueuedb.TaskQueueTask) error {
+	count, err := c.buildingsService.MatchBuildings(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "building matching failed", "error", err)
+		return err
+	}
+	logger.InfoContext(ctx, "building matching completed", "linked", count)
+	return nil
+}
+
+// scheduleBuildingMatching queues a matching run unless one is already
+// waiting.
+func (c *Consumer) scheduleBuildingMatching(ctx context.Context, logger *slog.Logger) {
+	c.scheduleSingletonTask(ctx, logger, buildingMatchingEntityID, taskTypeBuildingMatching, buildingMatchingMaxAttempts)
+}
@@ -7,72 +7,163 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
-	"koditon-go/internal/frontdoor"
-	"koditon-go/internal/prices"
-	"koditon-go/internal/shortcut"
+	"koditon-go/internal/alerts"
+	"koditon-go/internal/buildings"
+	"koditon-go/internal/sources"
 	"koditon-go/internal/taskqueue"
 	taskqueuedb "koditon-go/internal/taskqueue/db"
+	"koditon-go/internal/webhooks"
 )
 
 type Consumer struct {
 	logger           *slog.Logger
 	taskQueueClient  *taskqueue.Client
-	pricesService    *prices.Service
-	shortcutService  *shortcut.Service
-	frontdoorService *frontdoor.Service
-	workerPool       *taskqueue.WorkerPool
+	registry         *sources.Registry
+	buildingsService *buildings.Service
+	alertsService    *alerts.Service
+	webhooksService  *webhooks.Service
+	workerPools      []*taskqueue.WorkerPool
+	// breakers are keyed by source name and set up by Start
+	breakers map[string]*taskqueue.Breaker
 }
 
 type Config struct {
-	WorkerCount int
+	// Pools has exactly one pool for taskqueue.QueueName and one per
+	// dedicated queue.
+	Pools   []PoolConfig
+	Breaker taskqueue.BreakerConfig
 }
 
 func DefaultConfig() Config {
+	workerConfig := taskqueue.DefaultWorkerConfig()
 	return Config{
-		WorkerCount: 1,
+		Pools: []PoolConfig{
+			{
+				Queue:             taskqueue.QueueName,
+				WorkerCount:       1,
+				VisibilityTimeout: workerConfig.VisibilityTimeout,
+				TaskTimeout:       workerConfig.TaskTimeout,
+			},
+		},
+		Breaker: taskqueue.DefaultBreakerConfig(),
 	}
 }
 
 func New(
 	logger *slog.Logger,
 	taskQueueClient *taskqueue.Client,
-	pricesService *prices.Service,
-	shortcutService *shortcut.Service,
-	frontdoorService *frontdoor.Service,
+	buildingsService *buildings.Service,
+	alertsService *alerts.Service,
+	webhooksService *webhooks.Service,
 ) *Consumer {
-	return &Consumer{
+	c := &Consumer{
 		logger:           logger,
 		taskQueueClient:  taskQueueClient,
-		pricesService:    pricesService,
-		shortcutService:  shortcutService,
-		frontdoorService: frontdoorService,
+		registry:         sources.NewRegistry(),
+		buildingsService: buildingsService,
+		alertsService:    alertsService,
+		webhooksService:  webhooksService,
 	}
+	// the core task types are distinct constants, registering them cannot fail
+	_ = c.registry.Register(&coreSource{consumer: c})
+	return c
+}
+
+// Register adds the task types of a source to the ones the consumer handles.
+// Sources must be registered before Start.
+func (c *Consumer) Register(source sources.Source) error {
+	return c.registry.Register(source)
+}
+
+// Registry returns the task types the consumer handles and their sources.
+func (c *Consumer) Registry() *sources.Registry {
+	return c.registry
 }
 
 func (c *Consumer) Start(ctx context.Context, cfg Config, pool *pgxpool.Pool) error {
-	if err := c.taskQueueClient.EnsureQueue(ctx); err != nil {
-		return fmt.Errorf("ensure task queue: %w", err)
+	routes, err := cfg.taskTypeQueues(c.registry)
+	if err != nil {
+		return fmt.Errorf("worker pools: %w", err)
+	}
+	for _, poolCfg := range cfg.Pools {
+		if err := c.taskQueueClient.EnsureQueue(ctx, poolCfg.Queue); err != nil {
+			return fmt.Errorf("ensure task queue: %w", err)
+		}
+	}
+	if err := c.ensureTaskTypeMappings(ctx); err != nil {
+		return err
+	}
+	// the queues exist before tasks are routed to them
+	rerouted, err := c.taskQueueClient.SetTaskTypeQueues(ctx, routes)
+	if err != nil {
+		return fmt.Errorf("route task types: %w", err)
+	}
+	if rerouted > 0 {
+		c.logger.InfoContext(ctx, "moved pending tasks to their new queue", "count", rerouted)
+	}
+	c.breakers = c.newBreakers(cfg.Breaker)
+	workerCount := 0
+	for _, poolCfg := range cfg.Pools {
+		workerConfig := taskqueue.DefaultWorkerConfig()
+		workerConfig.Queue = poolCfg.Queue
+		workerConfig.VisibilityTimeout = poolCfg.VisibilityTimeout
+		workerConfig.TaskTimeout = poolCfg.TaskTimeout
+		workerConfig.Logger = c.logger
+		workerConfig.OnDeadLetter = c.handleDeadLetter
+		workerConfig.Gate = c.gateTask
+		workerConfig.HoldGroup = c.holdGroup
+		workerConfig.Paused = c.poolPaused(poolCfg)
+		workerPool := taskqueue.NewWorkerPool(
+			poolCfg.WorkerCount,
+			pool,
+			c.handleTask,
+			workerConfig,
+		)
+		workerPool.Start(ctx)
+		c.workerPools = append(c.workerPools, workerPool)
+		workerCount += poolCfg.WorkerCount
+	}
+	c.logger.InfoContext(ctx, "consumer started", "pool_count", len(c.workerPools), "worker_count", workerCount)
+	return nil
+}
+
+// ensureTaskTypeMappings lets the daily scheduling find the entities of each
+// registered task type.
+func (c *Consumer) ensureTaskTypeMappings(ctx context.Context) error {
+	for _, source := range c.registry.Sources() {
+		for _, taskType := range source.TaskTypes() {
+			for _, entityType := range taskType.EntityTypes {
+				if err := c.taskQueueClient.EnsureTaskTypeMapping(ctx, taskType.Name, entityType); err != nil {
+					return fmt.Errorf("ensure %s mapping for %s: %w", taskType.Name, source.Name(), err)
+				}
+			}
+		}
 	}
-	workerConfig := taskqueue.DefaultWorkerConfig()
-	workerConfig.Logger = c.logger
-	c.workerPool = taskqueue.NewWorkerPool(
-		cfg.WorkerCount,
-		pool,
-		c.handleTask,
-		workerConfig,
-	)
-	c.workerPool.Start(ctx)
-	c.logger.InfoContext(ctx, "consumer started", "worker_count", cfg.WorkerCount)
 	return nil
 }
 
 func (c *Consumer) Stop() {
-	if c.workerPool != nil {
-		c.logger.Info("stopping consumer worker pool")
-		c.workerPool.Stop()
-		c.workerPool.Wait()
-		c.logger.Info("consumer stopped")
+	if len(c.workerPools) == 0 {
+		return
 	}
+	c.logger.Info("stopping consumer worker pools")
+	// stop every pool first so they drain in parallel
+	for _, workerPool := range c.workerPools {
+		workerPool.Stop()
+	}
+	for _, workerPool := range c.workerPools {
+		workerPool.Wait()
+	}
+	c.logger.Info("consumer stopped")
+}
+
+// WorkerStatuses reports the liveness of each worker, or nil before Start.
+func (c *Consumer) WorkerStatuses() []taskqueue.WorkerStatus {
+	var statuses []taskqueue.WorkerStatus
+	for _, workerPool := range c.workerPools {
+		statuses = append(statuses, workerPool.Statuses()...)
+	}
+	return statuses
 }
 
 func (c *Consumer) handleTask(taskCtx context.Context, task taskqueuedb.TaskQueueTask) error {
@@ -83,30 +174,17 @@ func (c *Consumer) handleTask(taskCtx context.Context, task taskqueuedb.TaskQueu
 		"attempt", task.Attempt,
 		"priority", task.Priority,
 	)
-	var err error
-	switch task.TaskType {
-	case taskqueue.TaskTypeFrontdoorSitemapSync:
-		err = c.handleFrontdoorSitemapSync(taskCtx, taskLogger)
-	case taskqueue.TaskTypeFrontdoorSync:
-		err = c.handleFrontdoorSync(taskCtx, taskLogger, task)
-	case taskqueue.TaskTypeShortcutSitemapSync:
-		err = c.handleShortcutSitemapSync(taskCtx, taskLogger)
-	case taskqueue.TaskTypeShortcutScraperSync:
-		err = c.handleShortcutScraperSync(taskCtx, taskLogger, task)
-	case taskqueue.TaskTypeShortcutAPISync:
-		err = c.handleShortcutAPISync(taskCtx, taskLogger, task)
-	case taskqueue.TaskTypePricesCitiesInit:
-		err = c.handlePricesCitiesInit(taskCtx, taskLogger)
-	case taskqueue.TaskTypePricesSync:
-		err = c.handlePricesSync(taskCtx, taskLogger, task)
-	default:
+	source, taskType, ok := c.registry.Lookup(task.TaskType)
+	if !ok {
 		return taskqueue.NewPermanentError(
 			fmt.Errorf("unknown task type: %s", task.TaskType),
 			"unrecognized task type",
 		)
 	}
+	err := taskType.Handle(taskCtx, taskLogger.With("source", source.Name()), task)
 	if err != nil {
-		return classifyError(err, task)
+		err = classifyError(err, source)
 	}
-	return nil
+	c.recordOutcome(source, task.TaskID, err)
+	return err
 }
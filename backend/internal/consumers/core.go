@@ -0,0 +1,58 @@
+package consumers
+
+import (
+	"errors"
+	"net/http"
+
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	"koditon-go/internal/webhooks"
+)
+
+const (
+	taskTypeBuildingMatching      = "building_matching"
+	taskTypeSavedSearchEvaluation = "saved_search_evaluation"
+	taskTypeWebhookDelivery       = "webhook_delivery"
+)
+
+// coreSource handles the task types that work on stored data rather than an
+// external source.
+type coreSource struct {
+	consumer *Consumer
+}
+
+func (s *coreSource) Name() string {
+	return "core"
+}
+
+func (s *coreSource) TaskTypes() []sources.TaskType {
+	return []sources.TaskType{
+		{
+			Name:        taskTypeBuildingMatching,
+			EntityTypes: []string{"building_matching"},
+			Handle:      s.consumer.handleBuildingMatching,
+		},
+		{
+			Name:        taskTypeSavedSearchEvaluation,
+			EntityTypes: []string{"saved_search_evaluation"},
+			Handle:      s.consumer.handleSavedSearchEvaluation,
+		},
+		{
+			Name:        taskTypeWebhookDelivery,
+			EntityTypes: []string{webhooks.DeliveryEntityType},
+			Handle:      s.consumer.handleWebhookDelivery,
+		},
+	}
+}
+
+func (s *coreSource) ClassifyError(err error) error {
+	var webhookHTTPErr *webhooks.HTTPStatusError
+	if errors.As(err, &webhookHTTPErr) {
+		if webhookHTTPErr.StatusCode == http.StatusUnauthorized || webhookHTTPErr.StatusCode == http.StatusForbidden {
+			// unlike a source, an endpoint refusing us will not change its mind
+			return taskqueue.NewPermanentError(err, "webhook endpoint rejected the delivery")
+		}
+		return sources.ClassifyHTTPStatus(err, webhookHTTPErr.StatusCode, webhookHTTPErr.RetryAfter)
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+
+	"koditon-go/internal/listings"
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	"koditon-go/internal/webhooks"
+)
+
+var _ sources.Hooks = (*Consumer)(nil)
+
+// AdSynced announces new and repriced ads. Saved searches alert on new
+// listings only, so an evaluation is queued only when an ad is listed.
+func (c *Consumer) AdSynced(ctx context.Context, logger *slog.Logger, source, externalID string, entry *listings.HistoryEntry) {
+	c.publishAdEvent(ctx, logger, source, externalID, entry)
+	if entry != nil && entry.Event == listings.HistoryEventListed {
+		c.scheduleSavedSearchEvaluation(ctx, logger)
+	}
+}
+
+// BuildingSynced announces the building and queues building matching for it.
+// A single matching run links every building synced while it waits, so a run
+// is only queued when none is pending.
+func (c *Consumer) BuildingSynced(ctx context.Context, logger *slog.Logger, source, buildingID string) {
+	c.publishWebhookEvent(ctx, logger, webhooks.EventBuildingSynced, webhooks.BuildingEventData{
+		Source:     source,
+		BuildingID: buildingID,
+	})
+	c.scheduleBuildingMatching(ctx, logger)
+}
+
+// SitemapSynced records the crawl and returns the ads it found removed.
+func (c *Consumer) SitemapSynced(ctx context.Context, logger *slog.Logger, source string, failed int, entities taskqueue.SitemapEntities, adEntityType string) []string {
+	crawl := c.recordSitemapCrawl(ctx, logger, source, failed, entities)
+	removed := c.removedSitemapAds(ctx, logger, crawl, adEntityType)
+	return removed
+}
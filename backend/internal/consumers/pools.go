@@ -0,0 +1,127 @@
+package consumers
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"koditon-go/internal/config"
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+)
+
+// PoolConfig is a pool of workers draining one queue.
+type PoolConfig struct {
+	Queue string
+	// TaskTypes are routed to the queue. The pool of the shared queue lists
+	// none, it runs every task type that is not routed elsewhere.
+	TaskTypes         []string
+	WorkerCount       int
+	VisibilityTimeout time.Duration
+	TaskTimeout       time.Duration
+}
+
+// NewConfig builds the consumer configuration from the environment. The
+// dedicated pools inherit unset timeouts from the shared pool.
+func NewConfig(cfg config.WorkersConfig) Config {
+	shared := PoolConfig{
+		Queue:             taskqueue.QueueName,
+		WorkerCount:       cfg.Count,
+		VisibilityTimeout: cfg.VisibilityTimeout,
+		TaskTimeout:       cfg.TaskTimeout,
+	}
+	pools := []PoolConfig{shared}
+	for _, p := range cfg.Pools {
+		pool := PoolConfig{
+			Queue:             p.Queue,
+			TaskTypes:         p.TaskTypes,
+			WorkerCount:       p.Count,
+			VisibilityTimeout: time.Duration(p.VisibilityTimeout),
+			TaskTimeout:       time.Duration(p.TaskTimeout),
+		}
+		if pool.WorkerCount == 0 {
+			pool.WorkerCount = 1
+		}
+		if pool.VisibilityTimeout == 0 {
+			pool.VisibilityTimeout = shared.VisibilityTimeout
+		}
+		if pool.TaskTimeout == 0 {
+			pool.TaskTimeout = shared.TaskTimeout
+		}
+		pools = append(pools, pool)
+	}
+	return Config{
+		Pools: pools,
+		Breaker: taskqueue.BreakerConfig{
+			FailureRate:     cfg.Breaker.FailureRate,
+			Window:          cfg.Breaker.Window,
+			MinTasks:        cfg.Breaker.MinTasks,
+			OpenDuration:    cfg.Breaker.OpenDuration,
+			MaxOpenDuration: cfg.Breaker.MaxOpenDuration,
+		},
+	}
+}
+
+// pgmq creates a table per queue, so queue names have to be identifiers.
+var queueNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// taskTypeQueues validates the pools against the registered task types and
+// returns the queue of every task type.
+func (cfg Config) taskTypeQueues(registry *sources.Registry) ([]taskqueue.TaskTypeQueue, error) {
+	var shared *PoolConfig
+	queues := make(map[string]bool, len(cfg.Pools))
+	routed := make(map[string]PoolConfig)
+	for i, pool := range cfg.Pools {
+		switch {
+		case !queueNamePattern.MatchString(pool.Queue):
+			return nil, fmt.Errorf("worker pool %d: invalid queue name %q", i, pool.Queue)
+		case queues[pool.Queue]:
+			return nil, fmt.Errorf("worker pool %s: queue has more than one pool", pool.Queue)
+		case pool.WorkerCount < 1:
+			return nil, fmt.Errorf("worker pool %s: worker count must be positive", pool.Queue)
+		case pool.TaskTimeout <= 0:
+			return nil, fmt.Errorf("worker pool %s: task timeout must be positive", pool.Queue)
+		case pool.VisibilityTimeout < pool.TaskTimeout:
+			// the message would be handed to another worker while the task runs
+			return nil, fmt.Errorf("worker pool %s: visibility timeout %s is shorter than task timeout %s", pool.Queue, pool.VisibilityTimeout, pool.TaskTimeout)
+		}
+		queues[pool.Queue] = true
+		if pool.Queue == taskqueue.QueueName {
+			if len(pool.TaskTypes) > 0 {
+				return nil, fmt.Errorf("worker pool %s: the shared queue runs every unrouted task type and cannot list task types", pool.Queue)
+			}
+			shared = &cfg.Pools[i]
+			continue
+		}
+		if len(pool.TaskTypes) == 0 {
+			return nil, fmt.Errorf("worker pool %s: no task types", pool.Queue)
+		}
+		for _, taskType := range pool.TaskTypes {
+			if _, _, ok := registry.Lookup(taskType); !ok {
+				return nil, fmt.Errorf("worker pool %s: unknown task type %s", pool.Queue, taskType)
+			}
+			if other, ok := routed[taskType]; ok {
+				return nil, fmt.Errorf("worker pool %s: task type %s is already routed to %s", pool.Queue, taskType, other.Queue)
+			}
+			routed[taskType] = pool
+		}
+	}
+	if shared == nil {
+		return nil, fmt.Errorf("no worker pool for the shared queue %s", taskqueue.QueueName)
+	}
+	var routes []taskqueue.TaskTypeQueue
+	for _, source := range registry.Sources() {
+		for _, taskType := range source.TaskTypes() {
+			pool, ok := routed[taskType.Name]
+			if !ok {
+				pool = *shared
+			}
+			routes = append(routes, taskqueue.TaskTypeQueue{
+				TaskType:    taskType.Name,
+				Queue:       pool.Queue,
+				TaskTimeout: pool.TaskTimeout,
+			})
+		}
+	}
+	return routes, nil
+}
@@ -0,0 +1,57 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+// recordSitemapCrawl diffs the entities of a sitemap crawl against the
+// previous crawl of the source, which marks the entities that disappeared as
+// removed. A crawl with entries that could not be stored or sitemaps that
+// could not be fetched is not recorded as the missing entries would look
+// removed. Failures are only logged so that they never fail the sitemap sync.
+func (c *Consumer) recordSitemapCrawl(ctx context.Context, logger *slog.Logger, source string, failed int, entities taskqueue.SitemapEntities) *taskqueuedb.TaskQueueSitemapCrawl {
+	if failed > 0 {
+		logger.WarnContext(ctx, "skipping sitemap diff for incomplete crawl", "source", source, "failed", failed)
+		return nil
+	}
+	crawl, err := c.taskQueueClient.RecordSitemapCrawl(ctx, source, entities)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to record sitemap crawl", "source", source, "error", err)
+		return nil
+	}
+	logger.InfoContext(ctx, "sitemap crawl recorded",
+		"source", source,
+		"crawl_id", crawl.CrawlID,
+		"entries", crawl.EntryCount,
+		"added", crawl.AddedCount,
+		"removed", crawl.RemovedCount,
+	)
+	return crawl
+}
+
+// removedSitemapAds returns the external IDs of the ads a crawl found removed.
+func (c *Consumer) removedSitemapAds(ctx context.Context, logger *slog.Logger, crawl *taskqueuedb.TaskQueueSitemapCrawl, entityType string) []string {
+	if crawl == nil || crawl.RemovedCount == 0 {
+		return nil
+	}
+	entityIDs, err := c.taskQueueClient.ListRemovedSitemapEntities(ctx, crawl.CrawlID, entityType)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to list removed sitemap ads", "crawl_id", crawl.CrawlID, "error", err)
+		return nil
+	}
+	externalIDs := make([]string, 0, len(entityIDs))
+	for _, entityID := range entityIDs {
+		kind, externalID, err := sources.ParseEntityID(entityID)
+		if err != nil || kind != "ad" {
+			logger.WarnContext(ctx, "skipping unexpected removed entity", "entity_id", entityID)
+			continue
+		}
+		externalIDs = append(externalIDs, externalID)
+	}
+	return externalIDs
+}
@@ -1,16 +1,25 @@
 package consumers
 
 import (
-	"strings"
+	"context"
+	"log/slog"
+
+	"koditon-go/internal/taskqueue"
 )
 
-func parseEntityID(entityID string) (entityType, value string, err error) {
-	idx := strings.Index(entityID, ":")
-	if idx == -1 || idx == 0 || idx == len(entityID)-1 {
-		return "", "", &EntityParseError{
-			EntityID: entityID,
-			Reason:   "expected 'type:value' format",
-		}
+// scheduleSingletonTask queues a task for an entity unless one is already
+// waiting, so bursts of triggers collapse into a single run. The database
+// serializes the check, so concurrent workers cannot queue it twice. Failures
+// are only logged so that they never fail the task that asked for the run.
+func (c *Consumer) scheduleSingletonTask(ctx context.Context, logger *slog.Logger, entityID, taskType string, maxAttempts int) {
+	taskID, created, err := c.taskQueueClient.ScheduleSingletonTask(ctx, entityID, taskType, taskqueue.PriorityNormal, maxAttempts)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to schedule task", "task_type", taskType, "error", err)
+		return
+	}
+	if !created {
+		logger.DebugContext(ctx, "task already pending", "task_type", taskType, "task_id", taskID)
+		return
 	}
-	return entityID[:idx], entityID[idx+1:], nil
+	logger.InfoContext(ctx, "task scheduled", "task_type", taskType, "task_id", taskID)
 }
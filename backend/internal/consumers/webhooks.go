@@ -0,0 +1,112 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"koditon-go/internal/listings"
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+	"koditon-go/internal/webhooks"
+)
+
+func (c *Consumer) handleWebhookDelivery(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error {
+	_, value, err := sources.ParseEntityID(task.EntityID)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to parse entity ID", "entity_id", task.EntityID, "error", err)
+		return err
+	}
+	deliveryID, err := uuid.Parse(value)
+	if err != nil {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   "invalid webhook delivery UUID",
+			Err:      err,
+		}
+	}
+	if err := c.webhooksService.Deliver(ctx, deliveryID); err != nil {
+		if errors.Is(err, webhooks.ErrDeliveryNotFound) {
+			return taskqueue.NewPermanentError(err, "webhook delivery not found")
+		}
+		if errors.Is(err, webhooks.ErrForbiddenTarget) {
+			return taskqueue.NewPermanentError(err, "webhook target is not a public address")
+		}
+		logger.WarnContext(ctx, "webhook delivery failed", "delivery_id", deliveryID, "error", err)
+		return fmt.Errorf("deliver webhook %s: %w", deliveryID, err)
+	}
+	logger.InfoContext(ctx, "webhook delivered", "delivery_id", deliveryID)
+	return nil
+}
+
+// handleDeadLetter announces dead lettered tasks to webhook subscribers. A
+// dead lettered delivery only marks the delivery failed, announcing it could
+// fail the same way again and loop.
+func (c *Consumer) handleDeadLetter(ctx context.Context, task taskqueuedb.TaskQueueTask, lastErr error) {
+	logger := c.logger.With("task_id", task.TaskID, "task_type", task.TaskType, "entity_id", task.EntityID)
+	if task.TaskType == taskTypeWebhookDelivery {
+		_, value, err := sources.ParseEntityID(task.EntityID)
+		if err != nil {
+			return
+		}
+		deliveryID, err := uuid.Parse(value)
+		if err != nil {
+			return
+		}
+		if err := c.webhooksService.MarkDeliveryFailed(ctx, deliveryID, lastErr.Error()); err != nil {
+			logger.ErrorContext(ctx, "failed to mark webhook delivery failed", "delivery_id", deliveryID, "error", err)
+		}
+		return
+	}
+	c.publishWebhookEvent(ctx, logger, webhooks.EventTaskDeadLettered, webhooks.TaskEventData{
+		TaskID:   task.TaskID,
+		TaskType: task.TaskType,
+		EntityID: task.EntityID,
+		Attempts: task.Attempt + 1,
+		Error:    lastErr.Error(),
+	})
+}
+
+// publishAdEvent publishes ad.created for new ads and ad.price_changed for
+// price changes. Other history events are not published.
+func (c *Consumer) publishAdEvent(ctx context.Context, logger *slog.Logger, source, externalID string, entry *listings.HistoryEntry) {
+	if entry == nil {
+		return
+	}
+	var eventType string
+	switch entry.Event {
+	case listings.HistoryEventListed:
+		eventType = webhooks.EventAdCreated
+	case listings.HistoryEventPriceChanged:
+		eventType = webhooks.EventAdPriceChanged
+	default:
+		return
+	}
+	c.publishWebhookEvent(ctx, logger, eventType, webhooks.AdEventData{
+		ListingID:             source + ":" + taskqueue.EntityPrefixAd + externalID,
+		Source:                source,
+		ExternalID:            externalID,
+		Price:                 entry.Price,
+		DebtFreePrice:         entry.DebtFreePrice,
+		PreviousPrice:         entry.PreviousPrice,
+		PreviousDebtFreePrice: entry.PreviousDebtFreePrice,
+		Status:                entry.Status,
+	})
+}
+
+// publishWebhookEvent queues the event for its subscribers. Failures are only
+// logged so that they never fail the task that produced the event.
+func (c *Consumer) publishWebhookEvent(ctx context.Context, logger *slog.Logger, eventType string, data any) {
+	queued, err := c.webhooksService.Publish(ctx, eventType, data)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to publish webhook event", "event_type", eventType, "queued", queued, "error", err)
+		return
+	}
+	if queued > 0 {
+		logger.DebugContext(ctx, "webhook event published", "event_type", eventType, "queued", queued)
+	}
+}
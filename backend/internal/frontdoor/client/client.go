@@ -6,16 +6,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
+
+	"koditon-go/internal/transport"
 )
 
 const (
+	// SourceName labels the requests of the client in the transport stats.
+	SourceName            = "frontdoor"
 	defaultRequestTimeout = 30 * time.Second
 	maxRetries            = 3
 	initialBackoff        = 1 * time.Second
@@ -31,9 +35,15 @@ var (
 type HTTPStatusError struct {
 	StatusCode int
 	Body       string
+	// RetryAfter is the delay the response asked for, zero when it had no
+	// Retry-After.
+	RetryAfter time.Duration
 }
 
 func (e *HTTPStatusError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("frontdoor: HTTP %d: %s (retry-after: %s)", e.StatusCode, e.Body, e.RetryAfter)
+	}
 	return fmt.Sprintf("frontdoor: HTTP %d: %s", e.StatusCode, e.Body)
 }
 
@@ -42,6 +52,11 @@ func (e *HTTPStatusError) IsNotFound() bool {
 	return e.StatusCode == http.StatusNotFound
 }
 
+// IsAuthFailure returns true if the session was rejected.
+func (e *HTTPStatusError) IsAuthFailure() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
 // IsHTTPStatusError checks if an error is an HTTPStatusError and returns it.
 func IsHTTPStatusError(err error) (*HTTPStatusError, bool) {
 	var httpErr *HTTPStatusError
@@ -66,15 +81,21 @@ type SitemapEntry struct {
 
 type Client struct {
 	httpClient     *http.Client
+	logger         *slog.Logger
 	baseURL        string
 	userAgent      string
-	cookie         string
 	timeout        time.Duration
 	sitemapBaseURL string
+	sessions       *sessionManager
 }
 
-func New(baseURL, userAgent, cookie, sitemapBaseURL string) *Client {
-	transport := &http.Transport{
+// New returns a client that obtains its session itself. seedCookie, when
+// set, is used as the first session until the site rejects it.
+func New(logger *slog.Logger, sessionLoad SessionLoader, sessionStore SessionStore, baseURL, userAgent, seedCookie, sitemapBaseURL string, limiter *transport.Limiter) *Client {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	baseTransport := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
 			KeepAlive: 30 * time.Second,
@@ -88,15 +109,22 @@ func New(baseURL, userAgent, cookie, sitemapBaseURL string) *Client {
 	}
 	httpClient := &http.Client{
 		Timeout:   defaultRequestTimeout,
-		Transport: transport,
+		Transport: transport.New(limiter, baseTransport),
 	}
-	return &Client{
+	c := &Client{
 		httpClient:     httpClient,
+		logger:         logger.With("component", "frontdoor-client"),
 		baseURL:        baseURL,
 		userAgent:      userAgent,
-		cookie:         cookie,
 		sitemapBaseURL: sitemapBaseURL,
 	}
+	c.sessions = &sessionManager{
+		client:     c,
+		load:       sessionLoad,
+		store:      sessionStore,
+		seedCookie: seedCookie,
+	}
+	return c
 }
 
 func (c *Client) GetAdByFriendlyID(ctx context.Context, friendlyID string) (*AdResponse, error) {
@@ -114,26 +142,9 @@ func (c *Client) GetAdByFriendlyID(ctx context.Context, friendlyID string) (*AdR
 	q := u.Query()
 	q.Set("friendlyId", friendlyID)
 	u.RawQuery = q.Encode()
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	c.applyDefaultHeaders(req)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("perform request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return nil, &HTTPStatusError{
-			StatusCode: resp.StatusCode,
-			Body:       strings.TrimSpace(string(body)),
-		}
-	}
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	body, err := c.get(reqCtx, u.String(), 1024*1024)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, err
 	}
 	var ad AdResponse
 	if err := json.Unmarshal(body, &ad); err != nil {
@@ -152,26 +163,9 @@ func (c *Client) GetBuildingPageData(ctx context.Context, pageURL string) (*Hous
 	if pageURL == "" {
 		return nil, fmt.Errorf("build request: pageURL is required")
 	}
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, pageURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	c.applyDefaultHeaders(req)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("perform request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return nil, &HTTPStatusError{
-			StatusCode: resp.StatusCode,
-			Body:       strings.TrimSpace(string(body)),
-		}
-	}
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
+	body, err := c.get(reqCtx, pageURL, 5*1024*1024)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, err
 	}
 	raw, err := extractInitialState(body)
 	if err != nil {
@@ -184,7 +178,10 @@ func (c *Client) GetBuildingPageData(ctx context.Context, pageURL string) (*Hous
 	return &respPayload, nil
 }
 
-func (c *Client) GetSitemapEntries(ctx context.Context) ([]SitemapEntry, error) {
+// GetSitemapEntries fetches the sitemaps of every building type. The errors of
+// the sitemaps that could not be fetched are returned next to the entries of
+// the others, the call fails only when none of them could be fetched.
+func (c *Client) GetSitemapEntries(ctx context.Context) ([]SitemapEntry, []error, error) {
 	sitemapURLs := []string{
 		c.joinSitemap("sitemap_row_house.xml"),
 		c.joinSitemap("sitemap_detached_house.xml"),
@@ -208,22 +205,65 @@ func (c *Client) GetSitemapEntries(ctx context.Context) ([]SitemapEntry, error)
 				entries = append(entries, *entry)
 			}
 		}
-		time.Sleep(100 * time.Millisecond)
 	}
 	if len(entries) == 0 && len(fetchErrors) > 0 {
-		return nil, fmt.Errorf("all sitemap fetches failed: %w", errors.Join(fetchErrors...))
+		return nil, nil, fmt.Errorf("all sitemap fetches failed: %w", errors.Join(fetchErrors...))
+	}
+	return entries, fetchErrors, nil
+}
+
+// get reads a page with the current session. When the site rejects the
+// session, it is replaced and the request is sent once more.
+func (c *Client) get(ctx context.Context, pageURL string, maxBytes int64) ([]byte, error) {
+	session, err := c.sessions.get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	body, err := c.doGet(ctx, pageURL, session, maxBytes)
+	if httpErr, ok := IsHTTPStatusError(err); ok && httpErr.IsAuthFailure() {
+		session, refreshErr := c.sessions.refresh(ctx, session)
+		if refreshErr != nil {
+			return nil, fmt.Errorf("refresh session after HTTP %d: %w", httpErr.StatusCode, refreshErr)
+		}
+		return c.doGet(ctx, pageURL, session, maxBytes)
 	}
-	return entries, nil
+	return body, err
 }
 
-func (c *Client) applyDefaultHeaders(req *http.Request) {
+func (c *Client) doGet(ctx context.Context, pageURL string, session *Session, maxBytes int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	c.applyDefaultHeaders(req, session)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("perform request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return nil, &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(body)),
+			RetryAfter: transport.RetryAfter(resp),
+		}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return body, nil
+}
+
+func (c *Client) applyDefaultHeaders(req *http.Request, session *Session) {
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("Accept-Language", "en-GB,en;q=0.9")
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("User-Agent", c.userAgent)
-	if c.cookie != "" {
-		req.Header.Set("Cookie", c.cookie)
+	if session != nil && session.Cookie != "" {
+		req.Header.Set("Cookie", session.Cookie)
 	}
 }
 
@@ -325,10 +365,10 @@ func (c *Client) fetchXML(ctx context.Context, url string) (string, error) {
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
 		return "", &HTTPStatusError{
 			StatusCode: resp.StatusCode,
-			Body:       strings.TrimSpace(string(body)) + retryAfterSuffix(retryAfter),
+			Body:       strings.TrimSpace(string(body)),
+			RetryAfter: transport.RetryAfter(resp),
 		}
 	}
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
@@ -358,21 +398,3 @@ func (c *Client) joinSitemap(path string) string {
 	}
 	return base.ResolveReference(ref).String()
 }
-
-func parseRetryAfter(value string) int {
-	if value == "" {
-		return 0
-	}
-	seconds, err := strconv.Atoi(value)
-	if err != nil {
-		return 0
-	}
-	return seconds
-}
-
-func retryAfterSuffix(seconds int) string {
-	if seconds > 0 {
-		return fmt.Sprintf(" (retry-after: %ds)", seconds)
-	}
-	return ""
-}
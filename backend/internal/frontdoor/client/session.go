@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"koditon-go/internal/transport"
+)
+
+// defaultSessionLifetime is how long a stored session is offered to other
+// processes. The site does not tell when a session ends, a rejected request
+// is what replaces it.
+const defaultSessionLifetime = 24 * time.Hour
+
+var ErrNoSessionCookie = errors.New("frontdoor: no session cookie set")
+
+type SessionOrigin string
+
+const (
+	// SessionOriginSeed is the cookie configured with FRONTDOOR_COOKIE.
+	SessionOriginSeed SessionOrigin = "seed"
+	// SessionOriginRefresh is a session the client obtained itself.
+	SessionOriginRefresh SessionOrigin = "refresh"
+)
+
+type Session struct {
+	Cookie    string
+	Origin    SessionOrigin
+	ExpiresAt time.Time
+}
+
+type SessionLoader func(ctx context.Context) (*Session, error)
+type SessionStore func(ctx context.Context, session *Session) error
+
+// sessionManager hands out the session of the client. The session is loaded
+// from the store, seeded from the configured cookie or obtained from the site,
+// in that order, and concurrent requests share a single refresh.
+type sessionManager struct {
+	client     *Client
+	load       SessionLoader
+	store      SessionStore
+	seedCookie string
+
+	mu         sync.RWMutex
+	session    *Session
+	fetchGroup singleflight.Group
+}
+
+func (m *sessionManager) get(ctx context.Context) (*Session, error) {
+	m.mu.RLock()
+	if m.session != nil {
+		session := m.session
+		m.mu.RUnlock()
+		return session, nil
+	}
+	m.mu.RUnlock()
+	v, err, _ := m.fetchGroup.Do("fetch-session", func() (any, error) {
+		sessionCtx, cancel := detachedContext(ctx)
+		defer cancel()
+		m.mu.RLock()
+		session := m.session
+		m.mu.RUnlock()
+		if session != nil {
+			return session, nil
+		}
+		if m.load != nil {
+			if session, err := m.load(sessionCtx); err == nil {
+				m.set(session)
+				return session, nil
+			}
+		}
+		if m.seedCookie != "" {
+			session := &Session{
+				Cookie:    m.seedCookie,
+				Origin:    SessionOriginSeed,
+				ExpiresAt: time.Now().Add(defaultSessionLifetime),
+			}
+			m.save(sessionCtx, session)
+			m.set(session)
+			return session, nil
+		}
+		return m.obtain(sessionCtx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Session), nil
+}
+
+// refresh replaces a session the site rejected. When another request or
+// process already replaced it, its replacement is returned.
+func (m *sessionManager) refresh(ctx context.Context, rejected *Session) (*Session, error) {
+	v, err, _ := m.fetchGroup.Do("refresh-session", func() (any, error) {
+		sessionCtx, cancel := detachedContext(ctx)
+		defer cancel()
+		m.mu.Lock()
+		if m.session != nil && m.session != rejected {
+			session := m.session
+			m.mu.Unlock()
+			return session, nil
+		}
+		m.session = nil
+		m.mu.Unlock()
+		// another process may have replaced it already
+		if m.load != nil {
+			if session, err := m.load(sessionCtx); err == nil && session.Cookie != rejected.Cookie {
+				m.set(session)
+				return session, nil
+			}
+		}
+		m.client.logger.WarnContext(ctx, "frontdoor session rejected, obtaining a new one", "origin", rejected.Origin)
+		return m.obtain(sessionCtx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Session), nil
+}
+
+// obtain opens the front page of the site and keeps the cookies it sets as
+// the new session.
+func (m *sessionManager) obtain(ctx context.Context) (*Session, error) {
+	c := m.client
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("create cookie jar: %w", err)
+	}
+	httpClient := &http.Client{
+		Transport: c.httpClient.Transport,
+		Timeout:   c.httpClient.Timeout,
+		Jar:       jar,
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build session request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-GB,en;q=0.9")
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("perform session request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return nil, &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(body)),
+			RetryAfter: transport.RetryAfter(resp),
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	cookies := jar.Cookies(base)
+	if len(cookies) == 0 {
+		return nil, ErrNoSessionCookie
+	}
+	pairs := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		pairs = append(pairs, cookie.Name+"="+cookie.Value)
+	}
+	session := &Session{
+		Cookie:    strings.Join(pairs, "; "),
+		Origin:    SessionOriginRefresh,
+		ExpiresAt: time.Now().Add(defaultSessionLifetime),
+	}
+	m.save(ctx, session)
+	m.set(session)
+	c.logger.InfoContext(ctx, "frontdoor session obtained", "cookies", len(cookies))
+	return session, nil
+}
+
+func (m *sessionManager) set(session *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.session = session
+}
+
+// save persists a session. A session that cannot be stored is still used by
+// this process.
+func (m *sessionManager) save(ctx context.Context, session *Session) {
+	if m.store == nil {
+		return
+	}
+	if err := m.store(ctx, session); err != nil {
+		m.client.logger.WarnContext(ctx, "failed to store frontdoor session", "error", err)
+	}
+}
+
+// detachedContext keeps the deadline of ctx but not its cancellation, so that
+// one request giving up does not fail the others waiting for the session.
+func detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(context.Background(), deadline)
+	}
+	return context.WithCancel(context.Background())
+}
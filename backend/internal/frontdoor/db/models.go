@@ -22,6 +22,19 @@ type FrontdoorAd struct {
 	FrontdoorAdsProcessedAt    pgtype.Timestamptz `db:"frontdoor_ads_processed_at" json:"frontdoor_ads_processed_at"`
 	FrontdoorAdsPageNotFound   bool               `db:"frontdoor_ads_page_not_found" json:"frontdoor_ads_page_not_found"`
 	FrontdoorAdsPublishingTime pgtype.Timestamptz `db:"frontdoor_ads_publishing_time" json:"frontdoor_ads_publishing_time"`
+	FrontdoorAdsDelistedAt     pgtype.Timestamptz `db:"frontdoor_ads_delisted_at" json:"frontdoor_ads_delisted_at"`
+}
+
+type FrontdoorAdHistory struct {
+	FrontdoorAdHistoryID                    pgtype.UUID        `db:"frontdoor_ad_history_id" json:"frontdoor_ad_history_id"`
+	FrontdoorAdHistoryAdID                  pgtype.UUID        `db:"frontdoor_ad_history_ad_id" json:"frontdoor_ad_history_ad_id"`
+	FrontdoorAdHistoryEvent                 string             `db:"frontdoor_ad_history_event" json:"frontdoor_ad_history_event"`
+	FrontdoorAdHistoryPrice                 *float64           `db:"frontdoor_ad_history_price" json:"frontdoor_ad_history_price"`
+	FrontdoorAdHistoryDebtFreePrice         *float64           `db:"frontdoor_ad_history_debt_free_price" json:"frontdoor_ad_history_debt_free_price"`
+	FrontdoorAdHistoryPreviousPrice         *float64           `db:"frontdoor_ad_history_previous_price" json:"frontdoor_ad_history_previous_price"`
+	FrontdoorAdHistoryPreviousDebtFreePrice *float64           `db:"frontdoor_ad_history_previous_debt_free_price" json:"frontdoor_ad_history_previous_debt_free_price"`
+	FrontdoorAdHistoryStatus                *string            `db:"frontdoor_ad_history_status" json:"frontdoor_ad_history_status"`
+	FrontdoorAdHistoryRecordedAt            pgtype.Timestamptz `db:"frontdoor_ad_history_recorded_at" json:"frontdoor_ad_history_recorded_at"`
 }
 
 type FrontdoorBuilding struct {
@@ -113,3 +126,12 @@ type FrontdoorBuildingAnnouncement struct {
 	FrontdoorBuildingAnnouncementsLastSeenAt               pgtype.Timestamptz `db:"frontdoor_building_announcements_last_seen_at" json:"frontdoor_building_announcements_last_seen_at"`
 	FrontdoorBuildingAnnouncementsUnpublishingTimeDate     *time.Time         `db:"frontdoor_building_announcements_unpublishing_time_date" json:"frontdoor_building_announcements_unpublishing_time_date"`
 }
+
+type FrontdoorSession struct {
+	FrontdoorSessionsID         pgtype.UUID        `db:"frontdoor_sessions_id" json:"frontdoor_sessions_id"`
+	FrontdoorSessionsCookie     string             `db:"frontdoor_sessions_cookie" json:"frontdoor_sessions_cookie"`
+	FrontdoorSessionsOrigin     string             `db:"frontdoor_sessions_origin" json:"frontdoor_sessions_origin"`
+	FrontdoorSessionsCreatedAt  pgtype.Timestamptz `db:"frontdoor_sessions_created_at" json:"frontdoor_sessions_created_at"`
+	FrontdoorSessionsExpiresAt  pgtype.Timestamptz `db:"frontdoor_sessions_expires_at" json:"frontdoor_sessions_expires_at"`
+	FrontdoorSessionsReplacedAt pgtype.Timestamptz `db:"frontdoor_sessions_replaced_at" json:"frontdoor_sessions_replaced_at"`
+}
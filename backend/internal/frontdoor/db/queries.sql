@@ -39,7 +39,8 @@ UPDATE public.frontdoor_ads
 SET frontdoor_ads_data = $2::jsonb,
     frontdoor_ads_processed_at = NOW(),
     frontdoor_ads_updated_at = NOW(),
-    frontdoor_ads_page_not_found = false
+    frontdoor_ads_page_not_found = false,
+    frontdoor_ads_delisted_at = NULL
 WHERE frontdoor_ads_external_id = $1;
 
 -- name: MarkFrontdoorAdProcessed :exec
@@ -50,6 +51,7 @@ WHERE frontdoor_ads_id = $1;
 -- name: MarkFrontdoorAdNotFoundByExternalID :exec
 UPDATE public.frontdoor_ads
 SET frontdoor_ads_page_not_found = true,
+    frontdoor_ads_delisted_at = COALESCE(frontdoor_ads_delisted_at, NOW()),
     frontdoor_ads_processed_at = NOW(),
     frontdoor_ads_updated_at = NOW()
 WHERE frontdoor_ads_external_id = $1;
@@ -59,6 +61,62 @@ UPDATE public.frontdoor_ads
 SET frontdoor_ads_page_not_found = true, frontdoor_ads_updated_at = now()
 WHERE frontdoor_ads_id = $1;
 
+-- name: DelistFrontdoorAds :execrows
+WITH delisted AS (
+    UPDATE public.frontdoor_ads
+    SET frontdoor_ads_delisted_at = NOW(),
+        frontdoor_ads_updated_at = NOW()
+    WHERE frontdoor_ads_external_id = ANY(sqlc.arg('external_ids')::text[])
+      AND frontdoor_ads_delisted_at IS NULL
+    RETURNING frontdoor_ads_id
+)
+INSERT INTO public.frontdoor_ad_history (
+    frontdoor_ad_history_ad_id,
+    frontdoor_ad_history_event,
+    frontdoor_ad_history_price,
+    frontdoor_ad_history_debt_free_price,
+    frontdoor_ad_history_previous_price,
+    frontdoor_ad_history_previous_debt_free_price,
+    frontdoor_ad_history_status
+)
+SELECT d.frontdoor_ads_id, 'delisted', h.frontdoor_ad_history_price, h.frontdoor_ad_history_debt_free_price,
+    h.frontdoor_ad_history_price, h.frontdoor_ad_history_debt_free_price, h.frontdoor_ad_history_status
+FROM delisted d
+LEFT JOIN LATERAL (
+    SELECT * FROM public.frontdoor_ad_history
+    WHERE frontdoor_ad_history_ad_id = d.frontdoor_ads_id
+    ORDER BY frontdoor_ad_history_recorded_at DESC
+    LIMIT 1
+) h ON true
+WHERE h.frontdoor_ad_history_event IS DISTINCT FROM 'delisted';
+
+-- name: GetLatestFrontdoorAdHistory :one
+SELECT h.* FROM public.frontdoor_ad_history h
+JOIN public.frontdoor_ads a ON a.frontdoor_ads_id = h.frontdoor_ad_history_ad_id
+WHERE a.frontdoor_ads_external_id = $1
+ORDER BY h.frontdoor_ad_history_recorded_at DESC
+LIMIT 1;
+
+-- name: InsertFrontdoorAdHistory :exec
+INSERT INTO public.frontdoor_ad_history (
+    frontdoor_ad_history_ad_id,
+    frontdoor_ad_history_event,
+    frontdoor_ad_history_price,
+    frontdoor_ad_history_debt_free_price,
+    frontdoor_ad_history_previous_price,
+    frontdoor_ad_history_previous_debt_free_price,
+    frontdoor_ad_history_status
+)
+SELECT frontdoor_ads_id, sqlc.arg(event)::text, sqlc.narg(price)::float8, sqlc.narg(debt_free_price)::float8,
+    sqlc.narg(previous_price)::float8, sqlc.narg(previous_debt_free_price)::float8, sqlc.narg(status)::text
+FROM public.frontdoor_ads
+WHERE frontdoor_ads_external_id = sqlc.arg(external_id);
+
+-- name: ListFrontdoorAdHistory :many
+SELECT * FROM public.frontdoor_ad_history
+WHERE frontdoor_ad_history_ad_id = $1
+ORDER BY frontdoor_ad_history_recorded_at ASC;
+
 -- name: GetFrontdoorBuildingByID :one
 SELECT * FROM public.frontdoor_buildings
 WHERE frontdoor_buildings_id = $1;
@@ -268,3 +326,31 @@ SET frontdoor_building_announcements_last_seen_at = now(),
     frontdoor_building_announcements_rental_unique_no = COALESCE(EXCLUDED.frontdoor_building_announcements_rental_unique_no, frontdoor_building_announcements.frontdoor_building_announcements_rental_unique_no),
     frontdoor_building_announcements_unpublishing_time_date = COALESCE(EXCLUDED.frontdoor_building_announcements_unpublishing_time_date, frontdoor_building_announcements.frontdoor_building_announcements_unpublishing_time_date)
 RETURNING *;
+
+-- name: GetFrontdoorSession :one
+SELECT * FROM public.frontdoor_sessions
+WHERE frontdoor_sessions_replaced_at IS NULL
+    AND frontdoor_sessions_expires_at > now()
+ORDER BY frontdoor_sessions_created_at DESC
+LIMIT 1;
+
+-- name: InsertFrontdoorSession :one
+-- Replaces the sessions in use, only the newest one is used.
+WITH replaced AS (
+    UPDATE public.frontdoor_sessions
+    SET frontdoor_sessions_replaced_at = now()
+    WHERE frontdoor_sessions_replaced_at IS NULL
+)
+INSERT INTO public.frontdoor_sessions (
+    frontdoor_sessions_cookie,
+    frontdoor_sessions_origin,
+    frontdoor_sessions_expires_at
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: DeleteOldFrontdoorSessions :execrows
+DELETE FROM public.frontdoor_sessions
+WHERE frontdoor_sessions_replaced_at < $1
+    OR frontdoor_sessions_expires_at < $1;
@@ -13,8 +13,60 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteOldFrontdoorSessions = `-- name: DeleteOldFrontdoorSessions :execrows
+DELETE FROM public.frontdoor_sessions
+WHERE frontdoor_sessions_replaced_at < $1
+    OR frontdoor_sessions_expires_at < $1
+`
+
+func (q *Queries) DeleteOldFrontdoorSessions(ctx context.Context, frontdoorSessionsReplacedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldFrontdoorSessions, frontdoorSessionsReplacedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const delistFrontdoorAds = `-- name: DelistFrontdoorAds :execrows
+WITH delisted AS (
+    UPDATE public.frontdoor_ads
+    SET frontdoor_ads_delisted_at = NOW(),
+        frontdoor_ads_updated_at = NOW()
+    WHERE frontdoor_ads_external_id = ANY($1::text[])
+      AND frontdoor_ads_delisted_at IS NULL
+    RETURNING frontdoor_ads_id
+)
+INSERT INTO public.frontdoor_ad_history (
+    frontdoor_ad_history_ad_id,
+    frontdoor_ad_history_event,
+    frontdoor_ad_history_price,
+    frontdoor_ad_history_debt_free_price,
+    frontdoor_ad_history_previous_price,
+    frontdoor_ad_history_previous_debt_free_price,
+    frontdoor_ad_history_status
+)
+SELECT d.frontdoor_ads_id, 'delisted', h.frontdoor_ad_history_price, h.frontdoor_ad_history_debt_free_price,
+    h.frontdoor_ad_history_price, h.frontdoor_ad_history_debt_free_price, h.frontdoor_ad_history_status
+FROM delisted d
+LEFT JOIN LATERAL (
+    SELECT * FROM public.frontdoor_ad_history
+    WHERE frontdoor_ad_history_ad_id = d.frontdoor_ads_id
+    ORDER BY frontdoor_ad_history_recorded_at DESC
+    LIMIT 1
+) h ON true
+WHERE h.frontdoor_ad_history_event IS DISTINCT FROM 'delisted'
+`
+
+func (q *Queries) DelistFrontdoorAds(ctx context.Context, externalIds []string) (int64, error) {
+	result, err := q.db.Exec(ctx, delistFrontdoorAds, externalIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getFrontdoorAdByExternalID = `-- name: GetFrontdoorAdByExternalID :one
-SELECT frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time FROM public.frontdoor_ads
+SELECT frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time, frontdoor_ads_delisted_at FROM public.frontdoor_ads
 WHERE frontdoor_ads_external_id = $1
 `
 
@@ -32,6 +84,7 @@ func (q *Queries) GetFrontdoorAdByExternalID(ctx context.Context, frontdoorAdsEx
 		&i.FrontdoorAdsProcessedAt,
 		&i.FrontdoorAdsPageNotFound,
 		&i.FrontdoorAdsPublishingTime,
+		&i.FrontdoorAdsDelistedAt,
 	)
 	return i, err
 }
@@ -237,8 +290,167 @@ func (q *Queries) GetFrontdoorBuildingURLByHousingCompanyID(ctx context.Context,
 	return frontdoor_buildings_url, err
 }
 
+const getFrontdoorSession = `-- name: GetFrontdoorSession :one
+SELECT frontdoor_sessions_id, frontdoor_sessions_cookie, frontdoor_sessions_origin, frontdoor_sessions_created_at, frontdoor_sessions_expires_at, frontdoor_sessions_replaced_at FROM public.frontdoor_sessions
+WHERE frontdoor_sessions_replaced_at IS NULL
+    AND frontdoor_sessions_expires_at > now()
+ORDER BY frontdoor_sessions_created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetFrontdoorSession(ctx context.Context) (FrontdoorSession, error) {
+	row := q.db.QueryRow(ctx, getFrontdoorSession)
+	var i FrontdoorSession
+	err := row.Scan(
+		&i.FrontdoorSessionsID,
+		&i.FrontdoorSessionsCookie,
+		&i.FrontdoorSessionsOrigin,
+		&i.FrontdoorSessionsCreatedAt,
+		&i.FrontdoorSessionsExpiresAt,
+		&i.FrontdoorSessionsReplacedAt,
+	)
+	return i, err
+}
+
+const getLatestFrontdoorAdHistory = `-- name: GetLatestFrontdoorAdHistory :one
+SELECT h.frontdoor_ad_history_id, h.frontdoor_ad_history_ad_id, h.frontdoor_ad_history_event, h.frontdoor_ad_history_price, h.frontdoor_ad_history_debt_free_price, h.frontdoor_ad_history_previous_price, h.frontdoor_ad_history_previous_debt_free_price, h.frontdoor_ad_history_status, h.frontdoor_ad_history_recorded_at FROM public.frontdoor_ad_history h
+JOIN public.frontdoor_ads a ON a.frontdoor_ads_id = h.frontdoor_ad_history_ad_id
+WHERE a.frontdoor_ads_external_id = $1
+ORDER BY h.frontdoor_ad_history_recorded_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestFrontdoorAdHistory(ctx context.Context, frontdoorAdsExternalID string) (FrontdoorAdHistory, error) {
+	row := q.db.QueryRow(ctx, getLatestFrontdoorAdHistory, frontdoorAdsExternalID)
+	var i FrontdoorAdHistory
+	err := row.Scan(
+		&i.FrontdoorAdHistoryID,
+		&i.FrontdoorAdHistoryAdID,
+		&i.FrontdoorAdHistoryEvent,
+		&i.FrontdoorAdHistoryPrice,
+		&i.FrontdoorAdHistoryDebtFreePrice,
+		&i.FrontdoorAdHistoryPreviousPrice,
+		&i.FrontdoorAdHistoryPreviousDebtFreePrice,
+		&i.FrontdoorAdHistoryStatus,
+		&i.FrontdoorAdHistoryRecordedAt,
+	)
+	return i, err
+}
+
+const insertFrontdoorAdHistory = `-- name: InsertFrontdoorAdHistory :exec
+INSERT INTO public.frontdoor_ad_history (
+    frontdoor_ad_history_ad_id,
+    frontdoor_ad_history_event,
+    frontdoor_ad_history_price,
+    frontdoor_ad_history_debt_free_price,
+    frontdoor_ad_history_previous_price,
+    frontdoor_ad_history_previous_debt_free_price,
+    frontdoor_ad_history_status
+)
+SELECT frontdoor_ads_id, $1::text, $2::float8, $3::float8,
+    $4::float8, $5::float8, $6::text
+FROM public.frontdoor_ads
+WHERE frontdoor_ads_external_id = $7
+`
+
+type InsertFrontdoorAdHistoryParams struct {
+	Event                 string   `db:"event" json:"event"`
+	Price                 *float64 `db:"price" json:"price"`
+	DebtFreePrice         *float64 `db:"debt_free_price" json:"debt_free_price"`
+	PreviousPrice         *float64 `db:"previous_price" json:"previous_price"`
+	PreviousDebtFreePrice *float64 `db:"previous_debt_free_price" json:"previous_debt_free_price"`
+	Status                *string  `db:"status" json:"status"`
+	ExternalID            string   `db:"external_id" json:"external_id"`
+}
+
+func (q *Queries) InsertFrontdoorAdHistory(ctx context.Context, arg *InsertFrontdoorAdHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertFrontdoorAdHistory,
+		arg.Event,
+		arg.Price,
+		arg.DebtFreePrice,
+		arg.PreviousPrice,
+		arg.PreviousDebtFreePrice,
+		arg.Status,
+		arg.ExternalID,
+	)
+	return err
+}
+
+const insertFrontdoorSession = `-- name: InsertFrontdoorSession :one
+WITH replaced AS (
+    UPDATE public.frontdoor_sessions
+    SET frontdoor_sessions_replaced_at = now()
+    WHERE frontdoor_sessions_replaced_at IS NULL
+)
+INSERT INTO public.frontdoor_sessions (
+    frontdoor_sessions_cookie,
+    frontdoor_sessions_origin,
+    frontdoor_sessions_expires_at
+) VALUES (
+    $1, $2, $3
+)
+RETURNING frontdoor_sessions_id, frontdoor_sessions_cookie, frontdoor_sessions_origin, frontdoor_sessions_created_at, frontdoor_sessions_expires_at, frontdoor_sessions_replaced_at
+`
+
+type InsertFrontdoorSessionParams struct {
+	FrontdoorSessionsCookie    string             `db:"frontdoor_sessions_cookie" json:"frontdoor_sessions_cookie"`
+	FrontdoorSessionsOrigin    string             `db:"frontdoor_sessions_origin" json:"frontdoor_sessions_origin"`
+	FrontdoorSessionsExpiresAt pgtype.Timestamptz `db:"frontdoor_sessions_expires_at" json:"frontdoor_sessions_expires_at"`
+}
+
+// Replaces the sessions in use, only the newest one is used.
+func (q *Queries) InsertFrontdoorSession(ctx context.Context, arg *InsertFrontdoorSessionParams) (FrontdoorSession, error) {
+	row := q.db.QueryRow(ctx, insertFrontdoorSession, arg.FrontdoorSessionsCookie, arg.FrontdoorSessionsOrigin, arg.FrontdoorSessionsExpiresAt)
+	var i FrontdoorSession
+	err := row.Scan(
+		&i.FrontdoorSessionsID,
+		&i.FrontdoorSessionsCookie,
+		&i.FrontdoorSessionsOrigin,
+		&i.FrontdoorSessionsCreatedAt,
+		&i.FrontdoorSessionsExpiresAt,
+		&i.FrontdoorSessionsReplacedAt,
+	)
+	return i, err
+}
+
+const listFrontdoorAdHistory = `-- name: ListFrontdoorAdHistory :many
+SELECT frontdoor_ad_history_id, frontdoor_ad_history_ad_id, frontdoor_ad_history_event, frontdoor_ad_history_price, frontdoor_ad_history_debt_free_price, frontdoor_ad_history_previous_price, frontdoor_ad_history_previous_debt_free_price, frontdoor_ad_history_status, frontdoor_ad_history_recorded_at FROM public.frontdoor_ad_history
+WHERE frontdoor_ad_history_ad_id = $1
+ORDER BY frontdoor_ad_history_recorded_at ASC
+`
+
+func (q *Queries) ListFrontdoorAdHistory(ctx context.Context, frontdoorAdHistoryAdID pgtype.UUID) ([]FrontdoorAdHistory, error) {
+	rows, err := q.db.Query(ctx, listFrontdoorAdHistory, frontdoorAdHistoryAdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FrontdoorAdHistory{}
+	for rows.Next() {
+		var i FrontdoorAdHistory
+		if err := rows.Scan(
+			&i.FrontdoorAdHistoryID,
+			&i.FrontdoorAdHistoryAdID,
+			&i.FrontdoorAdHistoryEvent,
+			&i.FrontdoorAdHistoryPrice,
+			&i.FrontdoorAdHistoryDebtFreePrice,
+			&i.FrontdoorAdHistoryPreviousPrice,
+			&i.FrontdoorAdHistoryPreviousDebtFreePrice,
+			&i.FrontdoorAdHistoryStatus,
+			&i.FrontdoorAdHistoryRecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFrontdoorAds = `-- name: ListFrontdoorAds :many
-SELECT frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time FROM public.frontdoor_ads
+SELECT frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time, frontdoor_ads_delisted_at FROM public.frontdoor_ads
 ORDER BY frontdoor_ads_last_seen_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -268,6 +480,7 @@ func (q *Queries) ListFrontdoorAds(ctx context.Context, arg *ListFrontdoorAdsPar
 			&i.FrontdoorAdsProcessedAt,
 			&i.FrontdoorAdsPageNotFound,
 			&i.FrontdoorAdsPublishingTime,
+			&i.FrontdoorAdsDelistedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -423,7 +636,7 @@ func (q *Queries) ListFrontdoorBuildings(ctx context.Context, arg *ListFrontdoor
 }
 
 const listUnprocessedFrontdoorAds = `-- name: ListUnprocessedFrontdoorAds :many
-SELECT frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time FROM public.frontdoor_ads
+SELECT frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time, frontdoor_ads_delisted_at FROM public.frontdoor_ads
 WHERE frontdoor_ads_processed_at IS NULL AND frontdoor_ads_page_not_found = false
 ORDER BY frontdoor_ads_first_seen_at ASC
 LIMIT $1
@@ -449,6 +662,7 @@ func (q *Queries) ListUnprocessedFrontdoorAds(ctx context.Context, limit int64)
 			&i.FrontdoorAdsProcessedAt,
 			&i.FrontdoorAdsPageNotFound,
 			&i.FrontdoorAdsPublishingTime,
+			&i.FrontdoorAdsDelistedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -556,6 +770,7 @@ func (q *Queries) MarkFrontdoorAdNotFound(ctx context.Context, frontdoorAdsID pg
 const markFrontdoorAdNotFoundByExternalID = `-- name: MarkFrontdoorAdNotFoundByExternalID :exec
 UPDATE public.frontdoor_ads
 SET frontdoor_ads_page_not_found = true,
+    frontdoor_ads_delisted_at = COALESCE(frontdoor_ads_delisted_at, NOW()),
     frontdoor_ads_processed_at = NOW(),
     frontdoor_ads_updated_at = NOW()
 WHERE frontdoor_ads_external_id = $1
@@ -593,7 +808,8 @@ UPDATE public.frontdoor_ads
 SET frontdoor_ads_data = $2::jsonb,
     frontdoor_ads_processed_at = NOW(),
     frontdoor_ads_updated_at = NOW(),
-    frontdoor_ads_page_not_found = false
+    frontdoor_ads_page_not_found = false,
+    frontdoor_ads_delisted_at = NULL
 WHERE frontdoor_ads_external_id = $1
 `
 
@@ -921,7 +1137,7 @@ ON CONFLICT (frontdoor_ads_external_id) DO UPDATE
 SET frontdoor_ads_last_seen_at = now(),
     frontdoor_ads_updated_at = now(),
     frontdoor_ads_url = COALESCE(EXCLUDED.frontdoor_ads_url, frontdoor_ads.frontdoor_ads_url)
-RETURNING frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time
+RETURNING frontdoor_ads_id, frontdoor_ads_external_id, frontdoor_ads_url, frontdoor_ads_first_seen_at, frontdoor_ads_last_seen_at, frontdoor_ads_updated_at, frontdoor_ads_data, frontdoor_ads_processed_at, frontdoor_ads_page_not_found, frontdoor_ads_publishing_time, frontdoor_ads_delisted_at
 `
 
 type UpsertFrontdoorAdFromSitemapParams struct {
@@ -943,6 +1159,7 @@ func (q *Queries) UpsertFrontdoorAdFromSitemap(ctx context.Context, arg *UpsertF
 		&i.FrontdoorAdsProcessedAt,
 		&i.FrontdoorAdsPageNotFound,
 		&i.FrontdoorAdsPublishingTime,
+		&i.FrontdoorAdsDelistedAt,
 	)
 	return i, err
 }
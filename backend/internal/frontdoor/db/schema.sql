@@ -9,6 +9,7 @@ CREATE TABLE public.frontdoor_ads (
     frontdoor_ads_processed_at timestamptz,
     frontdoor_ads_page_not_found bool NOT NULL DEFAULT false,
     frontdoor_ads_publishing_time timestamptz,
+    frontdoor_ads_delisted_at timestamptz,
     PRIMARY KEY (frontdoor_ads_id)
 );
 
@@ -16,6 +17,21 @@ CREATE UNIQUE INDEX frontdoor_ads_external_id_key ON public.frontdoor_ads USING
 CREATE INDEX idx_frontdoor_ads_processed_at ON public.frontdoor_ads(frontdoor_ads_processed_at);
 CREATE INDEX idx_frontdoor_ads_page_not_found ON public.frontdoor_ads(frontdoor_ads_page_not_found);
 
+CREATE TABLE public.frontdoor_ad_history (
+    frontdoor_ad_history_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    frontdoor_ad_history_ad_id uuid NOT NULL REFERENCES public.frontdoor_ads(frontdoor_ads_id) ON DELETE CASCADE,
+    frontdoor_ad_history_event text NOT NULL,
+    frontdoor_ad_history_price float8,
+    frontdoor_ad_history_debt_free_price float8,
+    frontdoor_ad_history_previous_price float8,
+    frontdoor_ad_history_previous_debt_free_price float8,
+    frontdoor_ad_history_status text,
+    frontdoor_ad_history_recorded_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (frontdoor_ad_history_id)
+);
+
+CREATE INDEX idx_frontdoor_ad_history_ad_id ON public.frontdoor_ad_history(frontdoor_ad_history_ad_id, frontdoor_ad_history_recorded_at);
+
 CREATE TABLE public.frontdoor_buildings (
     frontdoor_buildings_id uuid NOT NULL DEFAULT gen_random_uuid(),
     frontdoor_buildings_url text,
@@ -120,3 +136,14 @@ CREATE UNIQUE INDEX frontdoor_building_announcements_ext_id_unpub_time_price_key
     frontdoor_building_announcements_search_price
 );
 CREATE INDEX idx_frontdoor_building_announcements_building_id ON public.frontdoor_building_announcements(frontdoor_building_announcements_building_id);
+
+CREATE TABLE public.frontdoor_sessions (
+    frontdoor_sessions_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    frontdoor_sessions_cookie text NOT NULL,
+    frontdoor_sessions_origin text NOT NULL,
+    frontdoor_sessions_created_at timestamptz NOT NULL DEFAULT now(),
+    frontdoor_sessions_expires_at timestamptz NOT NULL,
+    frontdoor_sessions_replaced_at timestamptz,
+    PRIMARY KEY (frontdoor_sessions_id)
+);
+CREATE INDEX idx_frontdoor_sessions_created_at ON public.frontdoor_sessions(frontdoor_sessions_created_at DESC);
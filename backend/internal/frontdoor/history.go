@@ -0,0 +1,65 @@
+package frontdoor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"koditon-go/internal/frontdoor/client"
+	"koditon-go/internal/frontdoor/db"
+	"koditon-go/internal/listings"
+)
+
+// recordAdHistory appends a history row when the ad differs from its last
+// recorded state and returns it, or nil when nothing changed. previous is the
+// previous price reported by frontdoor, which is only used for the first row
+// of an ad.
+func (s *Service) recordAdHistory(ctx context.Context, friendlyID string, next listings.AdSnapshot, previous *client.PreviousPrice) (*listings.HistoryEntry, error) {
+	var reported *listings.AdSnapshot
+	if previous != nil {
+		reported = &listings.AdSnapshot{
+			Price:         previous.SellingPrice,
+			DebtFreePrice: previous.DebtFreePrice,
+		}
+	}
+	return listings.RecordAdHistory(ctx, adHistory{queries: s.queries, friendlyID: friendlyID}, next, reported)
+}
+
+// adHistory is the history of a frontdoor ad.
+type adHistory struct {
+	queries    *db.Queries
+	friendlyID string
+}
+
+func (h adHistory) Latest(ctx context.Context) (*listings.HistoryEntry, error) {
+	latest, err := h.queries.GetLatestFrontdoorAdHistory(ctx, h.friendlyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest ad history (friendly_id=%s): %w", h.friendlyID, err)
+	}
+	return &listings.HistoryEntry{
+		Event:         latest.FrontdoorAdHistoryEvent,
+		Price:         latest.FrontdoorAdHistoryPrice,
+		DebtFreePrice: latest.FrontdoorAdHistoryDebtFreePrice,
+		Status:        latest.FrontdoorAdHistoryStatus,
+	}, nil
+}
+
+func (h adHistory) Append(ctx context.Context, entry listings.HistoryEntry) error {
+	if err := h.queries.InsertFrontdoorAdHistory(ctx, &db.InsertFrontdoorAdHistoryParams{
+		Event:                 entry.Event,
+		Price:                 entry.Price,
+		DebtFreePrice:         entry.DebtFreePrice,
+		PreviousPrice:         entry.PreviousPrice,
+		PreviousDebtFreePrice: entry.PreviousDebtFreePrice,
+		Status:                entry.Status,
+		ExternalID:            h.friendlyID,
+	}); err != nil {
+		return fmt.Errorf("insert ad history (friendly_id=%s): %w", h.friendlyID, err)
+	}
+	return nil
+}
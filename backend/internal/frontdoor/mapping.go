@@ -5,6 +5,7 @@ import (
 
 	"koditon-go/internal/frontdoor/client"
 	"koditon-go/internal/frontdoor/db"
+	"koditon-go/internal/listings"
 	"koditon-go/internal/util"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -20,6 +21,14 @@ func mapAdParams(friendlyID string, ad *client.AdResponse) *db.UpdateFrontdoorAd
 	return params
 }
 
+func mapAdSnapshot(ad *client.AdResponse) listings.AdSnapshot {
+	return listings.AdSnapshot{
+		Price:         ad.SellingPrice,
+		DebtFreePrice: ad.DebfFreePrice,
+		Status:        util.ToStringPtr(ad.Status),
+	}
+}
+
 func mapBuildingParams(housingCompanyID int64, data *client.HousingCompanyResponse) *db.UpdateFrontdoorBuildingDetailsByHousingCompanyIDParams {
 	p := &db.UpdateFrontdoorBuildingDetailsByHousingCompanyIDParams{
 		FrontdoorBuildingsHousingCompanyID: util.ToInt8(housingCompanyID),
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
+	"time"
 
 	"koditon-go/internal/frontdoor/client"
 	"koditon-go/internal/frontdoor/db"
+	"koditon-go/internal/listings"
+	"koditon-go/internal/transport"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -17,29 +22,76 @@ type Service struct {
 	queries *db.Queries
 }
 
+// sessionRetention is how long replaced and expired sessions are kept.
+const sessionRetention = 7 * 24 * time.Hour
+
 func NewService(
 	dbtx db.DBTX,
+	logger *slog.Logger,
 	baseURL string,
 	userAgent string,
-	cookie string,
+	seedCookie string,
 	sitemapBase string,
+	limiter *transport.Limiter,
 ) *Service {
+	queries := db.New(dbtx)
+	sessionLoad := func(ctx context.Context) (*client.Session, error) {
+		dbSession, err := queries.GetFrontdoorSession(ctx)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errors.New("no valid session found")
+			}
+			return nil, err
+		}
+		return &client.Session{
+			Cookie:    dbSession.FrontdoorSessionsCookie,
+			Origin:    client.SessionOrigin(dbSession.FrontdoorSessionsOrigin),
+			ExpiresAt: dbSession.FrontdoorSessionsExpiresAt.Time,
+		}, nil
+	}
+	sessionStore := func(ctx context.Context, session *client.Session) error {
+		if _, err := queries.InsertFrontdoorSession(ctx, &db.InsertFrontdoorSessionParams{
+			FrontdoorSessionsCookie:    session.Cookie,
+			FrontdoorSessionsOrigin:    string(session.Origin),
+			FrontdoorSessionsExpiresAt: pgtype.Timestamptz{Time: session.ExpiresAt, Valid: true},
+		}); err != nil {
+			return err
+		}
+		_, err := queries.DeleteOldFrontdoorSessions(ctx, pgtype.Timestamptz{Time: time.Now().Add(-sessionRetention), Valid: true})
+		return err
+	}
 	frontdoorClient := client.New(
+		logger,
+		sessionLoad,
+		sessionStore,
 		baseURL,
 		userAgent,
-		cookie,
+		seedCookie,
 		sitemapBase,
+		limiter,
 	)
 	return &Service{
 		client:  frontdoorClient,
-		queries: db.New(dbtx),
+		queries: queries,
 	}
 }
 
-func (s *Service) SyncSitemap(ctx context.Context) (adIDs []string, buildingIDs []string, err error) {
-	entries, fetchErr := s.client.GetSitemapEntries(ctx)
+// SitemapSync is the outcome of a sitemap crawl.
+type SitemapSync struct {
+	AdIDs       []string
+	BuildingIDs []string
+	// Failed counts the sitemap entries that could not be stored and the
+	// sitemaps that could not be fetched. The IDs miss entries the site still
+	// lists while it is not zero.
+	Failed int
+	// FetchErrors are the errors of the sitemaps that could not be fetched.
+	FetchErrors []error
+}
+
+func (s *Service) SyncSitemap(ctx context.Context) (*SitemapSync, error) {
+	entries, fetchErrors, fetchErr := s.client.GetSitemapEntries(ctx)
 	if fetchErr != nil {
-		return nil, nil, fmt.Errorf("fetch sitemap entries: %w", fetchErr)
+		return nil, fmt.Errorf("fetch sitemap entries: %w", fetchErr)
 	}
 	var adEntries []client.SitemapEntry
 	var buildingEntries []client.SitemapEntry
@@ -51,6 +103,7 @@ func (s *Service) SyncSitemap(ctx context.Context) (adIDs []string, buildingIDs
 			buildingEntries = append(buildingEntries, entry)
 		}
 	}
+	var adIDs, buildingIDs []string
 	var upsertErrors []error
 	if len(adEntries) > 0 {
 		successCount := 0
@@ -96,26 +149,45 @@ func (s *Service) SyncSitemap(ctx context.Context) (adIDs []string, buildingIDs
 		_ = successCount
 	}
 	if len(adIDs) == 0 && len(buildingIDs) == 0 && len(upsertErrors) > 0 {
-		return nil, nil, fmt.Errorf("all upserts failed: %w", errors.Join(upsertErrors...))
+		return nil, fmt.Errorf("all upserts failed: %w", errors.Join(upsertErrors...))
 	}
-	return adIDs, buildingIDs, nil
+	return &SitemapSync{
+		AdIDs:       adIDs,
+		BuildingIDs: buildingIDs,
+		Failed:      len(upsertErrors) + len(fetchErrors),
+		FetchErrors: fetchErrors,
+	}, nil
 }
 
-func (s *Service) SyncAd(ctx context.Context, friendlyID string) error {
+// DelistAds marks ads that dropped out of the sitemap as delisted.
+func (s *Service) DelistAds(ctx context.Context, friendlyIDs []string) (int64, error) {
+	if len(friendlyIDs) == 0 {
+		return 0, nil
+	}
+	count, err := s.queries.DelistFrontdoorAds(ctx, friendlyIDs)
+	if err != nil {
+		return 0, fmt.Errorf("delist ads: %w", err)
+	}
+	return count, nil
+}
+
+// SyncAd fetches an ad and records its history. It returns the recorded
+// history entry, or nil when the ad did not change.
+func (s *Service) SyncAd(ctx context.Context, friendlyID string) (*listings.HistoryEntry, error) {
 	ad, err := s.client.GetAdByFriendlyID(ctx, friendlyID)
 	if err != nil {
 		if httpErr, ok := client.IsHTTPStatusError(err); ok && httpErr.IsNotFound() {
 			if markErr := s.queries.MarkFrontdoorAdNotFoundByExternalID(ctx, friendlyID); markErr != nil {
-				return fmt.Errorf("mark ad not found (friendly_id=%s): %w", friendlyID, markErr)
+				return nil, fmt.Errorf("mark ad not found (friendly_id=%s): %w", friendlyID, markErr)
 			}
-			return nil
+			return s.recordAdHistory(ctx, friendlyID, listings.AdSnapshot{Delisted: true}, nil)
 		}
-		return fmt.Errorf("fetch ad data (friendly_id=%s): %w", friendlyID, err)
+		return nil, fmt.Errorf("fetch ad data (friendly_id=%s): %w", friendlyID, err)
 	}
 	if err := s.queries.UpdateFrontdoorAdData(ctx, mapAdParams(friendlyID, ad)); err != nil {
-		return fmt.Errorf("update ad data (friendly_id=%s): %w", friendlyID, err)
+		return nil, fmt.Errorf("update ad data (friendly_id=%s): %w", friendlyID, err)
 	}
-	return nil
+	return s.recordAdHistory(ctx, friendlyID, mapAdSnapshot(ad), ad.PreviousPrice)
 }
 
 func (s *Service) SyncBuilding(ctx context.Context, externalID string) error {
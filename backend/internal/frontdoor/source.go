@@ -0,0 +1,138 @@
+package frontdoor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"koditon-go/internal/frontdoor/client"
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+const (
+	SourceName = "frontdoor"
+
+	EntityTypeSitemap  = "frontdoor_sitemap"
+	EntityTypeAd       = "frontdoor_ad"
+	EntityTypeBuilding = "frontdoor_building"
+
+	TaskTypeSitemapSync = "frontdoor_sitemap_sync"
+	TaskTypeSync        = "frontdoor_sync"
+)
+
+// Source runs the frontdoor sitemap and entity syncs for the task queue.
+type Source struct {
+	service         *Service
+	taskQueueClient *taskqueue.Client
+	hooks           sources.Hooks
+}
+
+func NewSource(service *Service, taskQueueClient *taskqueue.Client, hooks sources.Hooks) *Source {
+	return &Source{
+		service:         service,
+		taskQueueClient: taskQueueClient,
+		hooks:           hooks,
+	}
+}
+
+func (s *Source) Name() string {
+	return SourceName
+}
+
+func (s *Source) TaskTypes() []sources.TaskType {
+	return []sources.TaskType{
+		{
+			Name:        TaskTypeSitemapSync,
+			EntityTypes: []string{EntityTypeSitemap},
+			Handle:      s.handleSitemapSync,
+		},
+		{
+			Name:        TaskTypeSync,
+			EntityTypes: []string{EntityTypeAd, EntityTypeBuilding},
+			Handle:      s.handleSync,
+		},
+	}
+}
+
+func (s *Source) ClassifyError(err error) error {
+	if httpErr, ok := client.IsHTTPStatusError(err); ok {
+		return sources.ClassifyHTTPStatus(err, httpErr.StatusCode, httpErr.RetryAfter)
+	}
+	return err
+}
+
+func (s *Source) handleSitemapSync(ctx context.Context, logger *slog.Logger, _ taskqueuedb.TaskQueueTask) error {
+	result, err := s.service.SyncSitemap(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "frontdoor sitemap sync failed", "error", err)
+		return fmt.Errorf("frontdoor sitemap sync: %w", err)
+	}
+	if len(result.FetchErrors) > 0 {
+		logger.WarnContext(ctx, "frontdoor sitemaps could not be fetched", "count", len(result.FetchErrors), "error", errors.Join(result.FetchErrors...))
+	}
+	adIDs, buildingIDs := result.AdIDs, result.BuildingIDs
+	var regErrors []error
+	if len(adIDs) > 0 {
+		if _, regErr := s.taskQueueClient.RegisterEntities(ctx, adIDs, EntityTypeAd, "daily"); regErr != nil {
+			logger.ErrorContext(ctx, "failed to register ad entities", "error", regErr, "count", len(adIDs))
+			regErrors = append(regErrors, fmt.Errorf("register ad entities: %w", regErr))
+		}
+	}
+	if len(buildingIDs) > 0 {
+		if _, regErr := s.taskQueueClient.RegisterEntities(ctx, buildingIDs, EntityTypeBuilding, "daily"); regErr != nil {
+			logger.ErrorContext(ctx, "failed to register building entities", "error", regErr, "count", len(buildingIDs))
+			regErrors = append(regErrors, fmt.Errorf("register building entities: %w", regErr))
+		}
+	}
+	if len(regErrors) > 0 && len(adIDs) == 0 && len(buildingIDs) == 0 {
+		return fmt.Errorf("frontdoor sitemap sync: all entity registrations failed")
+	}
+	removed := s.hooks.SitemapSynced(ctx, logger, SourceName, result.Failed, taskqueue.SitemapEntities{
+		EntityTypeAd:       adIDs,
+		EntityTypeBuilding: buildingIDs,
+	}, EntityTypeAd)
+	if len(removed) > 0 {
+		if count, err := s.service.DelistAds(ctx, removed); err != nil {
+			logger.ErrorContext(ctx, "failed to delist removed ads", "count", len(removed), "error", err)
+		} else {
+			logger.InfoContext(ctx, "removed ads delisted", "count", count)
+		}
+	}
+	logger.InfoContext(ctx, "frontdoor sitemap sync completed", "ads", len(adIDs), "buildings", len(buildingIDs))
+	return nil
+}
+
+func (s *Source) handleSync(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error {
+	entityType, externalID, err := sources.ParseEntityID(task.EntityID)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to parse entity ID", "entity_id", task.EntityID, "error", err)
+		return err
+	}
+	switch entityType {
+	case "ad":
+		entry, err := s.service.SyncAd(ctx, externalID)
+		if err != nil {
+			logger.ErrorContext(ctx, "frontdoor ad sync failed", "external_id", externalID, "error", err)
+			return fmt.Errorf("sync frontdoor ad %s: %w", externalID, err)
+		}
+		logger.InfoContext(ctx, "frontdoor ad synced", "external_id", externalID)
+		s.hooks.AdSynced(ctx, logger, SourceName, externalID, entry)
+		return nil
+	case "building":
+		if err := s.service.SyncBuilding(ctx, externalID); err != nil {
+			logger.ErrorContext(ctx, "frontdoor building sync failed", "external_id", externalID, "error", err)
+			return fmt.Errorf("sync frontdoor building %s: %w", externalID, err)
+		}
+		logger.InfoContext(ctx, "frontdoor building synced", "external_id", externalID)
+		s.hooks.BuildingSynced(ctx, logger, SourceName, externalID)
+		return nil
+	default:
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   fmt.Sprintf("unknown frontdoor entity type: %s", entityType),
+		}
+	}
+}
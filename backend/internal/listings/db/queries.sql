@@ -0,0 +1,32 @@
+-- name: SearchListings :many
+SELECT
+    listing_id,
+    source,
+    external_id,
+    url,
+    price,
+    debt_free_price,
+    area,
+    rooms,
+    postcode,
+    latitude,
+    longitude,
+    first_seen_at,
+    last_seen_at,
+    status
+FROM public.vw_listings
+WHERE (sqlc.narg('source')::text IS NULL OR source = sqlc.narg('source')::text)
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+    AND (sqlc.narg('min_price')::float8 IS NULL OR price >= sqlc.narg('min_price')::float8)
+    AND (sqlc.narg('max_price')::float8 IS NULL OR price <= sqlc.narg('max_price')::float8)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_rooms')::int4 IS NULL OR rooms >= sqlc.narg('min_rooms')::int4)
+    AND (sqlc.narg('max_rooms')::int4 IS NULL OR rooms <= sqlc.narg('max_rooms')::int4)
+    AND (cardinality(sqlc.arg('postcodes')::text[]) = 0 OR postcode = ANY(sqlc.arg('postcodes')::text[]))
+    AND (
+        sqlc.narg('cursor_first_seen_at')::timestamptz IS NULL
+        OR (first_seen_at, listing_id) < (sqlc.narg('cursor_first_seen_at')::timestamptz, sqlc.narg('cursor_listing_id')::text)
+    )
+ORDER BY first_seen_at DESC, listing_id DESC
+LIMIT sqlc.arg('page_size');
@@ -0,0 +1,18 @@
+-- View signatures for sqlc, see db/migrations/003_listings_view.sql
+
+CREATE TABLE public.vw_listings (
+    listing_id text NOT NULL,
+    source text NOT NULL,
+    external_id text NOT NULL,
+    url text,
+    price float8,
+    debt_free_price float8,
+    area float8,
+    rooms int4,
+    postcode text,
+    latitude float8,
+    longitude float8,
+    first_seen_at timestamptz NOT NULL,
+    last_seen_at timestamptz NOT NULL,
+    status text NOT NULL
+);
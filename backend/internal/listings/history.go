@@ -0,0 +1,118 @@
+package listings
+
+import "context"
+
+// Ad history events, as stored in the frontdoor_ad_history and
+// shortcut_ad_history tables.
+const (
+	HistoryEventListed        = "listed"
+	HistoryEventPriceChanged  = "price_changed"
+	HistoryEventStatusChanged = "status_changed"
+	HistoryEventDelisted      = "delisted"
+	HistoryEventRelisted      = "relisted"
+)
+
+// AdSnapshot is the state of an ad the history tracks.
+type AdSnapshot struct {
+	Price         *float64
+	DebtFreePrice *float64
+	Status        *string
+	Delisted      bool
+}
+
+// HistoryEntry is a history row recorded for an ad.
+type HistoryEntry struct {
+	Event                 string
+	Price                 *float64
+	DebtFreePrice         *float64
+	PreviousPrice         *float64
+	PreviousDebtFreePrice *float64
+	Status                *string
+}
+
+// AdHistoryStore reads and appends the history rows of a single ad.
+type AdHistoryStore interface {
+	// Latest returns the last recorded entry, or nil for an ad without history.
+	Latest(ctx context.Context) (*HistoryEntry, error)
+	Append(ctx context.Context, entry HistoryEntry) error
+}
+
+// RecordAdHistory appends a history entry when the ad differs from its last
+// recorded state and returns it, or nil when nothing changed. reported, when
+// set, holds the previous prices the source reported for the ad, which are
+// only used for its first entry.
+func RecordAdHistory(ctx context.Context, store AdHistoryStore, next AdSnapshot, reported *AdSnapshot) (*HistoryEntry, error) {
+	latest, err := store.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var last *AdSnapshot
+	if latest != nil {
+		last = &AdSnapshot{
+			Price:         latest.Price,
+			DebtFreePrice: latest.DebtFreePrice,
+			Status:        latest.Status,
+			Delisted:      latest.Event == HistoryEventDelisted,
+		}
+	}
+	event := NextHistoryEvent(last, next)
+	if event == "" {
+		return nil, nil
+	}
+	entry := HistoryEntry{
+		Event:         event,
+		Price:         next.Price,
+		DebtFreePrice: next.DebtFreePrice,
+		Status:        next.Status,
+	}
+	switch {
+	case last != nil:
+		entry.PreviousPrice = last.Price
+		entry.PreviousDebtFreePrice = last.DebtFreePrice
+		if next.Delisted {
+			// a delisted ad has no price of its own, keep the last known one
+			entry.Price = last.Price
+			entry.DebtFreePrice = last.DebtFreePrice
+			entry.Status = last.Status
+		}
+	case reported != nil:
+		entry.PreviousPrice = reported.Price
+		entry.PreviousDebtFreePrice = reported.DebtFreePrice
+	}
+	if err := store.Append(ctx, entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// NextHistoryEvent returns the event to record when an ad is seen in the next
+// state, or an empty string when nothing worth recording changed. last is nil
+// for an ad without history.
+func NextHistoryEvent(last *AdSnapshot, next AdSnapshot) string {
+	switch {
+	case last == nil && next.Delisted:
+		return HistoryEventDelisted
+	case last == nil:
+		return HistoryEventListed
+	case next.Delisted:
+		if last.Delisted {
+			return ""
+		}
+		return HistoryEventDelisted
+	case last.Delisted:
+		return HistoryEventRelisted
+	case !equalPtr(last.Status, next.Status):
+		return HistoryEventStatusChanged
+	case !equalPtr(last.Price, next.Price) || !equalPtr(last.DebtFreePrice, next.DebtFreePrice):
+		return HistoryEventPriceChanged
+	default:
+		return ""
+	}
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,23 @@
+// Package metrics holds the Prometheus registry of the counters and
+// histograms that live in process.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// DefaultDurationBuckets are histogram buckets in seconds suited for sync
+// tasks, which range from sub-second API calls to multi-minute sitemap crawls.
+var DefaultDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+// Registry holds the metrics of the process, including the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
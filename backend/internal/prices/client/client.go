@@ -14,9 +14,13 @@ import (
 	"time"
 
 	"golang.org/x/text/encoding/charmap"
+
+	"koditon-go/internal/transport"
 )
 
 const (
+	// SourceName labels the requests of the client in the transport stats.
+	SourceName            = "prices"
 	defaultRequestTimeout = 30 * time.Second
 )
 
@@ -29,6 +33,9 @@ var (
 type HTTPStatusError struct {
 	StatusCode int
 	Body       string
+	// RetryAfter is the delay the response asked for, zero when it had no
+	// Retry-After.
+	RetryAfter time.Duration
 }
 
 func (e *HTTPStatusError) Error() string {
@@ -152,12 +159,12 @@ type Client struct {
 	baseURL    *url.URL
 }
 
-func NewClient(baseURL string) (*Client, error) {
+func NewClient(baseURL string, limiter *transport.Limiter) (*Client, error) {
 	parsedBaseURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("parse base URL: %w", err)
 	}
-	transport := &http.Transport{
+	baseTransport := &http.Transport{
 		MaxIdleConns:          100,
 		MaxIdleConnsPerHost:   10,
 		MaxConnsPerHost:       10,
@@ -169,7 +176,7 @@ func NewClient(baseURL string) (*Client, error) {
 	}
 	httpClient := &http.Client{
 		Timeout:   defaultRequestTimeout,
-		Transport: transport,
+		Transport: transport.New(limiter, baseTransport),
 	}
 	return &Client{
 		httpClient: httpClient,
@@ -205,6 +212,7 @@ func (c *Client) doRequest(ctx context.Context, req *http.Request, target any) e
 		return &HTTPStatusError{
 			StatusCode: resp.StatusCode,
 			Body:       string(bytes.TrimSpace(body)),
+			RetryAfter: transport.RetryAfter(resp),
 		}
 	}
 	reader := c.getBodyReader(resp)
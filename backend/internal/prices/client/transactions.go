@@ -7,9 +7,10 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"koditon-go/internal/transport"
 )
 
 func (c *Client) GetTransactionsForPage(ctx context.Context, params *ApartmentSearchParams, page int) (*TransactionResponse, error) {
@@ -47,6 +48,7 @@ func (c *Client) GetTransactionsForPage(ctx context.Context, params *ApartmentSe
 		return nil, &HTTPStatusError{
 			StatusCode: resp.StatusCode,
 			Body:       strings.TrimSpace(string(body)),
+			RetryAfter: transport.RetryAfter(resp),
 		}
 	}
 	html, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
@@ -144,9 +146,6 @@ func (c *Client) GetAllTransactions(ctx context.Context, city string) ([]*Transa
 	*nextPage = 0
 	for nextPage != nil {
 		page := *nextPage
-		if page > 0 {
-			time.Sleep(1 * time.Second)
-		}
 		response, err := c.GetTransactionsForPage(ctx, NewApartmentSearchParams(city), page)
 		if err != nil {
 			return nil, fmt.Errorf("fetch page %d: %w", page, err)
@@ -52,3 +52,24 @@ type PricesTransaction struct {
 	PricesTransactionsCategory            string             `db:"prices_transactions_category" json:"prices_transactions_category"`
 	PricesNeighborhoodsID                 pgtype.UUID        `db:"prices_neighborhoods_id" json:"prices_neighborhoods_id"`
 }
+
+type VwPricesTransaction struct {
+	PricesTransactionsID                  pgtype.UUID `db:"prices_transactions_id" json:"prices_transactions_id"`
+	PricesTransactionsDescription         string      `db:"prices_transactions_description" json:"prices_transactions_description"`
+	PricesTransactionsType                string      `db:"prices_transactions_type" json:"prices_transactions_type"`
+	PricesTransactionsArea                float64     `db:"prices_transactions_area" json:"prices_transactions_area"`
+	PricesTransactionsPrice               int32       `db:"prices_transactions_price" json:"prices_transactions_price"`
+	PricesTransactionsPricePerSquareMeter int32       `db:"prices_transactions_price_per_square_meter" json:"prices_transactions_price_per_square_meter"`
+	PricesTransactionsBuildYear           int32       `db:"prices_transactions_build_year" json:"prices_transactions_build_year"`
+	PricesTransactionsFloor               *string     `db:"prices_transactions_floor" json:"prices_transactions_floor"`
+	PricesTransactionsElevator            bool        `db:"prices_transactions_elevator" json:"prices_transactions_elevator"`
+	PricesTransactionsCondition           *string     `db:"prices_transactions_condition" json:"prices_transactions_condition"`
+	PricesTransactionsPlot                *string     `db:"prices_transactions_plot" json:"prices_transactions_plot"`
+	PricesTransactionsEnergyClass         *string     `db:"prices_transactions_energy_class" json:"prices_transactions_energy_class"`
+	PricesTransactionsPeriodIdentifier    string      `db:"prices_transactions_period_identifier" json:"prices_transactions_period_identifier"`
+	PricesTransactionsCategory            string      `db:"prices_transactions_category" json:"prices_transactions_category"`
+	PricesNeighborhoodsID                 pgtype.UUID `db:"prices_neighborhoods_id" json:"prices_neighborhoods_id"`
+	PricesNeighborhoodsName               *string     `db:"prices_neighborhoods_name" json:"prices_neighborhoods_name"`
+	PricesPostalCodesCode                 *string     `db:"prices_postal_codes_code" json:"prices_postal_codes_code"`
+	PricesCitiesName                      *string     `db:"prices_cities_name" json:"prices_cities_name"`
+}
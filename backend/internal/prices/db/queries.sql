@@ -1,3 +1,31 @@
+-- name: GetPricesTransactionStatistics :many
+SELECT
+    ht.prices_neighborhoods_id,
+    hn.prices_neighborhoods_name,
+    ht.prices_transactions_period_identifier,
+    (CASE WHEN sqlc.arg(split_by_category)::bool
+        THEN ht.prices_transactions_category
+    END)::text AS category,
+    (CASE WHEN sqlc.arg(build_year_bucket)::int > 0
+        THEN (ht.prices_transactions_build_year / sqlc.arg(build_year_bucket)::int) * sqlc.arg(build_year_bucket)::int
+    END)::int AS build_year_bucket_start,
+    COUNT(*)::bigint AS transaction_count,
+    AVG(ht.prices_transactions_price_per_square_meter)::float8 AS mean_price_per_square_meter,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY ht.prices_transactions_price_per_square_meter)::float8 AS median_price_per_square_meter,
+    percentile_cont(0.1) WITHIN GROUP (ORDER BY ht.prices_transactions_price_per_square_meter)::float8 AS p10_price_per_square_meter,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY ht.prices_transactions_price_per_square_meter)::float8 AS p90_price_per_square_meter,
+    AVG(ht.prices_transactions_area)::float8 AS average_area
+FROM public.prices_transactions AS ht
+LEFT JOIN public.prices_neighborhoods AS hn
+    ON ht.prices_neighborhoods_id = hn.prices_neighborhoods_id
+WHERE (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR ht.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('period_from')::text IS NULL OR ht.prices_transactions_period_identifier >= sqlc.narg('period_from')::text)
+    AND (sqlc.narg('period_to')::text IS NULL OR ht.prices_transactions_period_identifier <= sqlc.narg('period_to')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR ht.prices_transactions_type = sqlc.narg('building_type')::text)
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY hn.prices_neighborhoods_name, ht.prices_transactions_period_identifier, 4, 5;
+
 -- name: ListCitiesWithNeighborhoods :many
 SELECT
     hc.prices_cities_id,
@@ -261,3 +289,123 @@ ON CONFLICT (
     prices_transactions_period_identifier
 ) DO UPDATE
 SET prices_transactions_updated_at = now();
+
+-- name: SearchPricesTransactionsByPriceAsc :many
+SELECT * FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price, t.prices_transactions_id) > (sqlc.arg(cursor_value)::int, sqlc.arg(cursor_id)::uuid)
+    AND (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('postal_code')::text IS NULL OR t.prices_postal_codes_code = sqlc.narg('postal_code')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR t.prices_transactions_type = sqlc.narg('building_type')::text)
+    AND (sqlc.narg('category')::text IS NULL OR t.prices_transactions_category = sqlc.narg('category')::text)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR t.prices_transactions_area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR t.prices_transactions_area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_build_year')::int IS NULL OR t.prices_transactions_build_year >= sqlc.narg('min_build_year')::int)
+    AND (sqlc.narg('max_build_year')::int IS NULL OR t.prices_transactions_build_year <= sqlc.narg('max_build_year')::int)
+    AND (sqlc.narg('elevator')::bool IS NULL OR t.prices_transactions_elevator = sqlc.narg('elevator')::bool)
+    AND (sqlc.narg('condition')::text IS NULL OR t.prices_transactions_condition = sqlc.narg('condition')::text)
+    AND (sqlc.narg('energy_class')::text IS NULL OR t.prices_transactions_energy_class = sqlc.narg('energy_class')::text)
+    AND (sqlc.narg('period_identifier')::text IS NULL
+        OR t.prices_transactions_period_identifier = sqlc.narg('period_identifier')::text)
+ORDER BY t.prices_transactions_price, t.prices_transactions_id
+LIMIT sqlc.arg(page_size)::int;
+
+-- name: SearchPricesTransactionsByPriceDesc :many
+SELECT * FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price, t.prices_transactions_id) < (sqlc.arg(cursor_value)::int, sqlc.arg(cursor_id)::uuid)
+    AND (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('postal_code')::text IS NULL OR t.prices_postal_codes_code = sqlc.narg('postal_code')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR t.prices_transactions_type = sqlc.narg('building_type')::text)
+    AND (sqlc.narg('category')::text IS NULL OR t.prices_transactions_category = sqlc.narg('category')::text)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR t.prices_transactions_area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR t.prices_transactions_area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_build_year')::int IS NULL OR t.prices_transactions_build_year >= sqlc.narg('min_build_year')::int)
+    AND (sqlc.narg('max_build_year')::int IS NULL OR t.prices_transactions_build_year <= sqlc.narg('max_build_year')::int)
+    AND (sqlc.narg('elevator')::bool IS NULL OR t.prices_transactions_elevator = sqlc.narg('elevator')::bool)
+    AND (sqlc.narg('condition')::text IS NULL OR t.prices_transactions_condition = sqlc.narg('condition')::text)
+    AND (sqlc.narg('energy_class')::text IS NULL OR t.prices_transactions_energy_class = sqlc.narg('energy_class')::text)
+    AND (sqlc.narg('period_identifier')::text IS NULL
+        OR t.prices_transactions_period_identifier = sqlc.narg('period_identifier')::text)
+ORDER BY t.prices_transactions_price DESC, t.prices_transactions_id DESC
+LIMIT sqlc.arg(page_size)::int;
+
+-- name: SearchPricesTransactionsByPricePerSquareMeterAsc :many
+SELECT * FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price_per_square_meter, t.prices_transactions_id) > (sqlc.arg(cursor_value)::int, sqlc.arg(cursor_id)::uuid)
+    AND (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('postal_code')::text IS NULL OR t.prices_postal_codes_code = sqlc.narg('postal_code')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR t.prices_transactions_type = sqlc.narg('building_type')::text)
+    AND (sqlc.narg('category')::text IS NULL OR t.prices_transactions_category = sqlc.narg('category')::text)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR t.prices_transactions_area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR t.prices_transactions_area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_build_year')::int IS NULL OR t.prices_transactions_build_year >= sqlc.narg('min_build_year')::int)
+    AND (sqlc.narg('max_build_year')::int IS NULL OR t.prices_transactions_build_year <= sqlc.narg('max_build_year')::int)
+    AND (sqlc.narg('elevator')::bool IS NULL OR t.prices_transactions_elevator = sqlc.narg('elevator')::bool)
+    AND (sqlc.narg('condition')::text IS NULL OR t.prices_transactions_condition = sqlc.narg('condition')::text)
+    AND (sqlc.narg('energy_class')::text IS NULL OR t.prices_transactions_energy_class = sqlc.narg('energy_class')::text)
+    AND (sqlc.narg('period_identifier')::text IS NULL
+        OR t.prices_transactions_period_identifier = sqlc.narg('period_identifier')::text)
+ORDER BY t.prices_transactions_price_per_square_meter, t.prices_transactions_id
+LIMIT sqlc.arg(page_size)::int;
+
+-- name: SearchPricesTransactionsByPricePerSquareMeterDesc :many
+SELECT * FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price_per_square_meter, t.prices_transactions_id) < (sqlc.arg(cursor_value)::int, sqlc.arg(cursor_id)::uuid)
+    AND (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('postal_code')::text IS NULL OR t.prices_postal_codes_code = sqlc.narg('postal_code')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR t.prices_transactions_type = sqlc.narg('building_type')::text)
+    AND (sqlc.narg('category')::text IS NULL OR t.prices_transactions_category = sqlc.narg('category')::text)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR t.prices_transactions_area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR t.prices_transactions_area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_build_year')::int IS NULL OR t.prices_transactions_build_year >= sqlc.narg('min_build_year')::int)
+    AND (sqlc.narg('max_build_year')::int IS NULL OR t.prices_transactions_build_year <= sqlc.narg('max_build_year')::int)
+    AND (sqlc.narg('elevator')::bool IS NULL OR t.prices_transactions_elevator = sqlc.narg('elevator')::bool)
+    AND (sqlc.narg('condition')::text IS NULL OR t.prices_transactions_condition = sqlc.narg('condition')::text)
+    AND (sqlc.narg('energy_class')::text IS NULL OR t.prices_transactions_energy_class = sqlc.narg('energy_class')::text)
+    AND (sqlc.narg('period_identifier')::text IS NULL
+        OR t.prices_transactions_period_identifier = sqlc.narg('period_identifier')::text)
+ORDER BY t.prices_transactions_price_per_square_meter DESC, t.prices_transactions_id DESC
+LIMIT sqlc.arg(page_size)::int;
+
+-- name: SearchPricesTransactionsByBuildYearAsc :many
+SELECT * FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_build_year, t.prices_transactions_id) > (sqlc.arg(cursor_value)::int, sqlc.arg(cursor_id)::uuid)
+    AND (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('postal_code')::text IS NULL OR t.prices_postal_codes_code = sqlc.narg('postal_code')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR t.prices_transactions_type = sqlc.narg('building_type')::text)
+    AND (sqlc.narg('category')::text IS NULL OR t.prices_transactions_category = sqlc.narg('category')::text)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR t.prices_transactions_area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR t.prices_transactions_area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_build_year')::int IS NULL OR t.prices_transactions_build_year >= sqlc.narg('min_build_year')::int)
+    AND (sqlc.narg('max_build_year')::int IS NULL OR t.prices_transactions_build_year <= sqlc.narg('max_build_year')::int)
+    AND (sqlc.narg('elevator')::bool IS NULL OR t.prices_transactions_elevator = sqlc.narg('elevator')::bool)
+    AND (sqlc.narg('condition')::text IS NULL OR t.prices_transactions_condition = sqlc.narg('condition')::text)
+    AND (sqlc.narg('energy_class')::text IS NULL OR t.prices_transactions_energy_class = sqlc.narg('energy_class')::text)
+    AND (sqlc.narg('period_identifier')::text IS NULL
+        OR t.prices_transactions_period_identifier = sqlc.narg('period_identifier')::text)
+ORDER BY t.prices_transactions_build_year, t.prices_transactions_id
+LIMIT sqlc.arg(page_size)::int;
+
+-- name: SearchPricesTransactionsByBuildYearDesc :many
+SELECT * FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_build_year, t.prices_transactions_id) < (sqlc.arg(cursor_value)::int, sqlc.arg(cursor_id)::uuid)
+    AND (sqlc.narg('neighborhood_ids')::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY(sqlc.narg('neighborhood_ids')::uuid[]))
+    AND (sqlc.narg('postal_code')::text IS NULL OR t.prices_postal_codes_code = sqlc.narg('postal_code')::text)
+    AND (sqlc.narg('building_type')::text IS NULL OR t.prices_transactions_type = sqlc.narg('building_type')::text)
+    AND (sqlc.narg('category')::text IS NULL OR t.prices_transactions_category = sqlc.narg('category')::text)
+    AND (sqlc.narg('min_area')::float8 IS NULL OR t.prices_transactions_area >= sqlc.narg('min_area')::float8)
+    AND (sqlc.narg('max_area')::float8 IS NULL OR t.prices_transactions_area <= sqlc.narg('max_area')::float8)
+    AND (sqlc.narg('min_build_year')::int IS NULL OR t.prices_transactions_build_year >= sqlc.narg('min_build_year')::int)
+    AND (sqlc.narg('max_build_year')::int IS NULL OR t.prices_transactions_build_year <= sqlc.narg('max_build_year')::int)
+    AND (sqlc.narg('elevator')::bool IS NULL OR t.prices_transactions_elevator = sqlc.narg('elevator')::bool)
+    AND (sqlc.narg('condition')::text IS NULL OR t.prices_transactions_condition = sqlc.narg('condition')::text)
+    AND (sqlc.narg('energy_class')::text IS NULL OR t.prices_transactions_energy_class = sqlc.narg('energy_class')::text)
+    AND (sqlc.narg('period_identifier')::text IS NULL
+        OR t.prices_transactions_period_identifier = sqlc.narg('period_identifier')::text)
+ORDER BY t.prices_transactions_build_year DESC, t.prices_transactions_id DESC
+LIMIT sqlc.arg(page_size)::int;
@@ -11,6 +11,98 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getPricesTransactionStatistics = `-- name: GetPricesTransactionStatistics :many
+SELECT
+    ht.prices_neighborhoods_id,
+    hn.prices_neighborhoods_name,
+    ht.prices_transactions_period_identifier,
+    (CASE WHEN $1::bool
+        THEN ht.prices_transactions_category
+    END)::text AS category,
+    (CASE WHEN $2::int > 0
+        THEN (ht.prices_transactions_build_year / $2::int) * $2::int
+    END)::int AS build_year_bucket_start,
+    COUNT(*)::bigint AS transaction_count,
+    AVG(ht.prices_transactions_price_per_square_meter)::float8 AS mean_price_per_square_meter,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY ht.prices_transactions_price_per_square_meter)::float8 AS median_price_per_square_meter,
+    percentile_cont(0.1) WITHIN GROUP (ORDER BY ht.prices_transactions_price_per_square_meter)::float8 AS p10_price_per_square_meter,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY ht.prices_transactions_price_per_square_meter)::float8 AS p90_price_per_square_meter,
+    AVG(ht.prices_transactions_area)::float8 AS average_area
+FROM public.prices_transactions AS ht
+LEFT JOIN public.prices_neighborhoods AS hn
+    ON ht.prices_neighborhoods_id = hn.prices_neighborhoods_id
+WHERE ($3::uuid[] IS NULL
+        OR ht.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR ht.prices_transactions_period_identifier >= $4::text)
+    AND ($5::text IS NULL OR ht.prices_transactions_period_identifier <= $5::text)
+    AND ($6::text IS NULL OR ht.prices_transactions_type = $6::text)
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY hn.prices_neighborhoods_name, ht.prices_transactions_period_identifier, 4, 5
+
+`
+
+type GetPricesTransactionStatisticsParams struct {
+	SplitByCategory bool          `db:"split_by_category" json:"split_by_category"`
+	BuildYearBucket int32         `db:"build_year_bucket" json:"build_year_bucket"`
+	NeighborhoodIds []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PeriodFrom      *string       `db:"period_from" json:"period_from"`
+	PeriodTo        *string       `db:"period_to" json:"period_to"`
+	BuildingType    *string       `db:"building_type" json:"building_type"`
+}
+
+type GetPricesTransactionStatisticsRow struct {
+	PricesNeighborhoodsID              pgtype.UUID `db:"prices_neighborhoods_id" json:"prices_neighborhoods_id"`
+	PricesNeighborhoodsName            *string     `db:"prices_neighborhoods_name" json:"prices_neighborhoods_name"`
+	PricesTransactionsPeriodIdentifier string      `db:"prices_transactions_period_identifier" json:"prices_transactions_period_identifier"`
+	Category                           *string     `db:"category" json:"category"`
+	BuildYearBucketStart               *int32      `db:"build_year_bucket_start" json:"build_year_bucket_start"`
+	TransactionCount                   int64       `db:"transaction_count" json:"transaction_count"`
+	MeanPricePerSquareMeter            float64     `db:"mean_price_per_square_meter" json:"mean_price_per_square_meter"`
+	MedianPricePerSquareMeter          float64     `db:"median_price_per_square_meter" json:"median_price_per_square_meter"`
+	P10PricePerSquareMeter             float64     `db:"p10_price_per_square_meter" json:"p10_price_per_square_meter"`
+	P90PricePerSquareMeter             float64     `db:"p90_price_per_square_meter" json:"p90_price_per_square_meter"`
+	AverageArea                        float64     `db:"average_area" json:"average_area"`
+}
+
+func (q *Queries) GetPricesTransactionStatistics(ctx context.Context, arg *GetPricesTransactionStatisticsParams) ([]GetPricesTransactionStatisticsRow, error) {
+	rows, err := q.db.Query(ctx, getPricesTransactionStatistics,
+		arg.SplitByCategory,
+		arg.BuildYearBucket,
+		arg.NeighborhoodIds,
+		arg.PeriodFrom,
+		arg.PeriodTo,
+		arg.BuildingType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPricesTransactionStatisticsRow{}
+	for rows.Next() {
+		var i GetPricesTransactionStatisticsRow
+		if err := rows.Scan(
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.Category,
+			&i.BuildYearBucketStart,
+			&i.TransactionCount,
+			&i.MeanPricePerSquareMeter,
+			&i.MedianPricePerSquareMeter,
+			&i.P10PricePerSquareMeter,
+			&i.P90PricePerSquareMeter,
+			&i.AverageArea,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCitiesWithNeighborhoods = `-- name: ListCitiesWithNeighborhoods :many
 SELECT
     hc.prices_cities_id,
@@ -176,6 +268,570 @@ func (q *Queries) ListTransactionsByNeighborhoods(ctx context.Context, neighborh
 	return items, nil
 }
 
+const searchPricesTransactionsByBuildYearAsc = `-- name: SearchPricesTransactionsByBuildYearAsc :many
+SELECT prices_transactions_id, prices_transactions_description, prices_transactions_type, prices_transactions_area, prices_transactions_price, prices_transactions_price_per_square_meter, prices_transactions_build_year, prices_transactions_floor, prices_transactions_elevator, prices_transactions_condition, prices_transactions_plot, prices_transactions_energy_class, prices_transactions_period_identifier, prices_transactions_category, prices_neighborhoods_id, prices_neighborhoods_name, prices_postal_codes_code, prices_cities_name FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_build_year, t.prices_transactions_id) > ($1::int, $2::uuid)
+    AND ($3::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR t.prices_postal_codes_code = $4::text)
+    AND ($5::text IS NULL OR t.prices_transactions_type = $5::text)
+    AND ($6::text IS NULL OR t.prices_transactions_category = $6::text)
+    AND ($7::float8 IS NULL OR t.prices_transactions_area >= $7::float8)
+    AND ($8::float8 IS NULL OR t.prices_transactions_area <= $8::float8)
+    AND ($9::int IS NULL OR t.prices_transactions_build_year >= $9::int)
+    AND ($10::int IS NULL OR t.prices_transactions_build_year <= $10::int)
+    AND ($11::bool IS NULL OR t.prices_transactions_elevator = $11::bool)
+    AND ($12::text IS NULL OR t.prices_transactions_condition = $12::text)
+    AND ($13::text IS NULL OR t.prices_transactions_energy_class = $13::text)
+    AND ($14::text IS NULL
+        OR t.prices_transactions_period_identifier = $14::text)
+ORDER BY t.prices_transactions_build_year, t.prices_transactions_id
+LIMIT $15::int
+`
+
+type SearchPricesTransactionsByBuildYearAscParams struct {
+	CursorValue      int32         `db:"cursor_value" json:"cursor_value"`
+	CursorID         pgtype.UUID   `db:"cursor_id" json:"cursor_id"`
+	NeighborhoodIds  []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PostalCode       *string       `db:"postal_code" json:"postal_code"`
+	BuildingType     *string       `db:"building_type" json:"building_type"`
+	Category         *string       `db:"category" json:"category"`
+	MinArea          *float64      `db:"min_area" json:"min_area"`
+	MaxArea          *float64      `db:"max_area" json:"max_area"`
+	MinBuildYear     *int32        `db:"min_build_year" json:"min_build_year"`
+	MaxBuildYear     *int32        `db:"max_build_year" json:"max_build_year"`
+	Elevator         *bool         `db:"elevator" json:"elevator"`
+	Condition        *string       `db:"condition" json:"condition"`
+	EnergyClass      *string       `db:"energy_class" json:"energy_class"`
+	PeriodIdentifier *string       `db:"period_identifier" json:"period_identifier"`
+	PageSize         int32         `db:"page_size" json:"page_size"`
+}
+
+func (q *Queries) SearchPricesTransactionsByBuildYearAsc(ctx context.Context, arg *SearchPricesTransactionsByBuildYearAscParams) ([]VwPricesTransaction, error) {
+	rows, err := q.db.Query(ctx, searchPricesTransactionsByBuildYearAsc,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.NeighborhoodIds,
+		arg.PostalCode,
+		arg.BuildingType,
+		arg.Category,
+		arg.MinArea,
+		arg.MaxArea,
+		arg.MinBuildYear,
+		arg.MaxBuildYear,
+		arg.Elevator,
+		arg.Condition,
+		arg.EnergyClass,
+		arg.PeriodIdentifier,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VwPricesTransaction{}
+	for rows.Next() {
+		var i VwPricesTransaction
+		if err := rows.Scan(
+			&i.PricesTransactionsID,
+			&i.PricesTransactionsDescription,
+			&i.PricesTransactionsType,
+			&i.PricesTransactionsArea,
+			&i.PricesTransactionsPrice,
+			&i.PricesTransactionsPricePerSquareMeter,
+			&i.PricesTransactionsBuildYear,
+			&i.PricesTransactionsFloor,
+			&i.PricesTransactionsElevator,
+			&i.PricesTransactionsCondition,
+			&i.PricesTransactionsPlot,
+			&i.PricesTransactionsEnergyClass,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.PricesTransactionsCategory,
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesPostalCodesCode,
+			&i.PricesCitiesName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPricesTransactionsByBuildYearDesc = `-- name: SearchPricesTransactionsByBuildYearDesc :many
+SELECT prices_transactions_id, prices_transactions_description, prices_transactions_type, prices_transactions_area, prices_transactions_price, prices_transactions_price_per_square_meter, prices_transactions_build_year, prices_transactions_floor, prices_transactions_elevator, prices_transactions_condition, prices_transactions_plot, prices_transactions_energy_class, prices_transactions_period_identifier, prices_transactions_category, prices_neighborhoods_id, prices_neighborhoods_name, prices_postal_codes_code, prices_cities_name FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_build_year, t.prices_transactions_id) < ($1::int, $2::uuid)
+    AND ($3::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR t.prices_postal_codes_code = $4::text)
+    AND ($5::text IS NULL OR t.prices_transactions_type = $5::text)
+    AND ($6::text IS NULL OR t.prices_transactions_category = $6::text)
+    AND ($7::float8 IS NULL OR t.prices_transactions_area >= $7::float8)
+    AND ($8::float8 IS NULL OR t.prices_transactions_area <= $8::float8)
+    AND ($9::int IS NULL OR t.prices_transactions_build_year >= $9::int)
+    AND ($10::int IS NULL OR t.prices_transactions_build_year <= $10::int)
+    AND ($11::bool IS NULL OR t.prices_transactions_elevator = $11::bool)
+    AND ($12::text IS NULL OR t.prices_transactions_condition = $12::text)
+    AND ($13::text IS NULL OR t.prices_transactions_energy_class = $13::text)
+    AND ($14::text IS NULL
+        OR t.prices_transactions_period_identifier = $14::text)
+ORDER BY t.prices_transactions_build_year DESC, t.prices_transactions_id DESC
+LIMIT $15::int
+`
+
+type SearchPricesTransactionsByBuildYearDescParams struct {
+	CursorValue      int32         `db:"cursor_value" json:"cursor_value"`
+	CursorID         pgtype.UUID   `db:"cursor_id" json:"cursor_id"`
+	NeighborhoodIds  []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PostalCode       *string       `db:"postal_code" json:"postal_code"`
+	BuildingType     *string       `db:"building_type" json:"building_type"`
+	Category         *string       `db:"category" json:"category"`
+	MinArea          *float64      `db:"min_area" json:"min_area"`
+	MaxArea          *float64      `db:"max_area" json:"max_area"`
+	MinBuildYear     *int32        `db:"min_build_year" json:"min_build_year"`
+	MaxBuildYear     *int32        `db:"max_build_year" json:"max_build_year"`
+	Elevator         *bool         `db:"elevator" json:"elevator"`
+	Condition        *string       `db:"condition" json:"condition"`
+	EnergyClass      *string       `db:"energy_class" json:"energy_class"`
+	PeriodIdentifier *string       `db:"period_identifier" json:"period_identifier"`
+	PageSize         int32         `db:"page_size" json:"page_size"`
+}
+
+func (q *Queries) SearchPricesTransactionsByBuildYearDesc(ctx context.Context, arg *SearchPricesTransactionsByBuildYearDescParams) ([]VwPricesTransaction, error) {
+	rows, err := q.db.Query(ctx, searchPricesTransactionsByBuildYearDesc,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.NeighborhoodIds,
+		arg.PostalCode,
+		arg.BuildingType,
+		arg.Category,
+		arg.MinArea,
+		arg.MaxArea,
+		arg.MinBuildYear,
+		arg.MaxBuildYear,
+		arg.Elevator,
+		arg.Condition,
+		arg.EnergyClass,
+		arg.PeriodIdentifier,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VwPricesTransaction{}
+	for rows.Next() {
+		var i VwPricesTransaction
+		if err := rows.Scan(
+			&i.PricesTransactionsID,
+			&i.PricesTransactionsDescription,
+			&i.PricesTransactionsType,
+			&i.PricesTransactionsArea,
+			&i.PricesTransactionsPrice,
+			&i.PricesTransactionsPricePerSquareMeter,
+			&i.PricesTransactionsBuildYear,
+			&i.PricesTransactionsFloor,
+			&i.PricesTransactionsElevator,
+			&i.PricesTransactionsCondition,
+			&i.PricesTransactionsPlot,
+			&i.PricesTransactionsEnergyClass,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.PricesTransactionsCategory,
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesPostalCodesCode,
+			&i.PricesCitiesName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPricesTransactionsByPriceAsc = `-- name: SearchPricesTransactionsByPriceAsc :many
+SELECT prices_transactions_id, prices_transactions_description, prices_transactions_type, prices_transactions_area, prices_transactions_price, prices_transactions_price_per_square_meter, prices_transactions_build_year, prices_transactions_floor, prices_transactions_elevator, prices_transactions_condition, prices_transactions_plot, prices_transactions_energy_class, prices_transactions_period_identifier, prices_transactions_category, prices_neighborhoods_id, prices_neighborhoods_name, prices_postal_codes_code, prices_cities_name FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price, t.prices_transactions_id) > ($1::int, $2::uuid)
+    AND ($3::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR t.prices_postal_codes_code = $4::text)
+    AND ($5::text IS NULL OR t.prices_transactions_type = $5::text)
+    AND ($6::text IS NULL OR t.prices_transactions_category = $6::text)
+    AND ($7::float8 IS NULL OR t.prices_transactions_area >= $7::float8)
+    AND ($8::float8 IS NULL OR t.prices_transactions_area <= $8::float8)
+    AND ($9::int IS NULL OR t.prices_transactions_build_year >= $9::int)
+    AND ($10::int IS NULL OR t.prices_transactions_build_year <= $10::int)
+    AND ($11::bool IS NULL OR t.prices_transactions_elevator = $11::bool)
+    AND ($12::text IS NULL OR t.prices_transactions_condition = $12::text)
+    AND ($13::text IS NULL OR t.prices_transactions_energy_class = $13::text)
+    AND ($14::text IS NULL
+        OR t.prices_transactions_period_identifier = $14::text)
+ORDER BY t.prices_transactions_price, t.prices_transactions_id
+LIMIT $15::int
+`
+
+type SearchPricesTransactionsByPriceAscParams struct {
+	CursorValue      int32         `db:"cursor_value" json:"cursor_value"`
+	CursorID         pgtype.UUID   `db:"cursor_id" json:"cursor_id"`
+	NeighborhoodIds  []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PostalCode       *string       `db:"postal_code" json:"postal_code"`
+	BuildingType     *string       `db:"building_type" json:"building_type"`
+	Category         *string       `db:"category" json:"category"`
+	MinArea          *float64      `db:"min_area" json:"min_area"`
+	MaxArea          *float64      `db:"max_area" json:"max_area"`
+	MinBuildYear     *int32        `db:"min_build_year" json:"min_build_year"`
+	MaxBuildYear     *int32        `db:"max_build_year" json:"max_build_year"`
+	Elevator         *bool         `db:"elevator" json:"elevator"`
+	Condition        *string       `db:"condition" json:"condition"`
+	EnergyClass      *string       `db:"energy_class" json:"energy_class"`
+	PeriodIdentifier *string       `db:"period_identifier" json:"period_identifier"`
+	PageSize         int32         `db:"page_size" json:"page_size"`
+}
+
+func (q *Queries) SearchPricesTransactionsByPriceAsc(ctx context.Context, arg *SearchPricesTransactionsByPriceAscParams) ([]VwPricesTransaction, error) {
+	rows, err := q.db.Query(ctx, searchPricesTransactionsByPriceAsc,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.NeighborhoodIds,
+		arg.PostalCode,
+		arg.BuildingType,
+		arg.Category,
+		arg.MinArea,
+		arg.MaxArea,
+		arg.MinBuildYear,
+		arg.MaxBuildYear,
+		arg.Elevator,
+		arg.Condition,
+		arg.EnergyClass,
+		arg.PeriodIdentifier,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VwPricesTransaction{}
+	for rows.Next() {
+		var i VwPricesTransaction
+		if err := rows.Scan(
+			&i.PricesTransactionsID,
+			&i.PricesTransactionsDescription,
+			&i.PricesTransactionsType,
+			&i.PricesTransactionsArea,
+			&i.PricesTransactionsPrice,
+			&i.PricesTransactionsPricePerSquareMeter,
+			&i.PricesTransactionsBuildYear,
+			&i.PricesTransactionsFloor,
+			&i.PricesTransactionsElevator,
+			&i.PricesTransactionsCondition,
+			&i.PricesTransactionsPlot,
+			&i.PricesTransactionsEnergyClass,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.PricesTransactionsCategory,
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesPostalCodesCode,
+			&i.PricesCitiesName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPricesTransactionsByPriceDesc = `-- name: SearchPricesTransactionsByPriceDesc :many
+SELECT prices_transactions_id, prices_transactions_description, prices_transactions_type, prices_transactions_area, prices_transactions_price, prices_transactions_price_per_square_meter, prices_transactions_build_year, prices_transactions_floor, prices_transactions_elevator, prices_transactions_condition, prices_transactions_plot, prices_transactions_energy_class, prices_transactions_period_identifier, prices_transactions_category, prices_neighborhoods_id, prices_neighborhoods_name, prices_postal_codes_code, prices_cities_name FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price, t.prices_transactions_id) < ($1::int, $2::uuid)
+    AND ($3::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR t.prices_postal_codes_code = $4::text)
+    AND ($5::text IS NULL OR t.prices_transactions_type = $5::text)
+    AND ($6::text IS NULL OR t.prices_transactions_category = $6::text)
+    AND ($7::float8 IS NULL OR t.prices_transactions_area >= $7::float8)
+    AND ($8::float8 IS NULL OR t.prices_transactions_area <= $8::float8)
+    AND ($9::int IS NULL OR t.prices_transactions_build_year >= $9::int)
+    AND ($10::int IS NULL OR t.prices_transactions_build_year <= $10::int)
+    AND ($11::bool IS NULL OR t.prices_transactions_elevator = $11::bool)
+    AND ($12::text IS NULL OR t.prices_transactions_condition = $12::text)
+    AND ($13::text IS NULL OR t.prices_transactions_energy_class = $13::text)
+    AND ($14::text IS NULL
+        OR t.prices_transactions_period_identifier = $14::text)
+ORDER BY t.prices_transactions_price DESC, t.prices_transactions_id DESC
+LIMIT $15::int
+`
+
+type SearchPricesTransactionsByPriceDescParams struct {
+	CursorValue      int32         `db:"cursor_value" json:"cursor_value"`
+	CursorID         pgtype.UUID   `db:"cursor_id" json:"cursor_id"`
+	NeighborhoodIds  []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PostalCode       *string       `db:"postal_code" json:"postal_code"`
+	BuildingType     *string       `db:"building_type" json:"building_type"`
+	Category         *string       `db:"category" json:"category"`
+	MinArea          *float64      `db:"min_area" json:"min_area"`
+	MaxArea          *float64      `db:"max_area" json:"max_area"`
+	MinBuildYear     *int32        `db:"min_build_year" json:"min_build_year"`
+	MaxBuildYear     *int32        `db:"max_build_year" json:"max_build_year"`
+	Elevator         *bool         `db:"elevator" json:"elevator"`
+	Condition        *string       `db:"condition" json:"condition"`
+	EnergyClass      *string       `db:"energy_class" json:"energy_class"`
+	PeriodIdentifier *string       `db:"period_identifier" json:"period_identifier"`
+	PageSize         int32         `db:"page_size" json:"page_size"`
+}
+
+func (q *Queries) SearchPricesTransactionsByPriceDesc(ctx context.Context, arg *SearchPricesTransactionsByPriceDescParams) ([]VwPricesTransaction, error) {
+	rows, err := q.db.Query(ctx, searchPricesTransactionsByPriceDesc,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.NeighborhoodIds,
+		arg.PostalCode,
+		arg.BuildingType,
+		arg.Category,
+		arg.MinArea,
+		arg.MaxArea,
+		arg.MinBuildYear,
+		arg.MaxBuildYear,
+		arg.Elevator,
+		arg.Condition,
+		arg.EnergyClass,
+		arg.PeriodIdentifier,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VwPricesTransaction{}
+	for rows.Next() {
+		var i VwPricesTransaction
+		if err := rows.Scan(
+			&i.PricesTransactionsID,
+			&i.PricesTransactionsDescription,
+			&i.PricesTransactionsType,
+			&i.PricesTransactionsArea,
+			&i.PricesTransactionsPrice,
+			&i.PricesTransactionsPricePerSquareMeter,
+			&i.PricesTransactionsBuildYear,
+			&i.PricesTransactionsFloor,
+			&i.PricesTransactionsElevator,
+			&i.PricesTransactionsCondition,
+			&i.PricesTransactionsPlot,
+			&i.PricesTransactionsEnergyClass,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.PricesTransactionsCategory,
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesPostalCodesCode,
+			&i.PricesCitiesName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPricesTransactionsByPricePerSquareMeterAsc = `-- name: SearchPricesTransactionsByPricePerSquareMeterAsc :many
+SELECT prices_transactions_id, prices_transactions_description, prices_transactions_type, prices_transactions_area, prices_transactions_price, prices_transactions_price_per_square_meter, prices_transactions_build_year, prices_transactions_floor, prices_transactions_elevator, prices_transactions_condition, prices_transactions_plot, prices_transactions_energy_class, prices_transactions_period_identifier, prices_transactions_category, prices_neighborhoods_id, prices_neighborhoods_name, prices_postal_codes_code, prices_cities_name FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price_per_square_meter, t.prices_transactions_id) > ($1::int, $2::uuid)
+    AND ($3::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR t.prices_postal_codes_code = $4::text)
+    AND ($5::text IS NULL OR t.prices_transactions_type = $5::text)
+    AND ($6::text IS NULL OR t.prices_transactions_category = $6::text)
+    AND ($7::float8 IS NULL OR t.prices_transactions_area >= $7::float8)
+    AND ($8::float8 IS NULL OR t.prices_transactions_area <= $8::float8)
+    AND ($9::int IS NULL OR t.prices_transactions_build_year >= $9::int)
+    AND ($10::int IS NULL OR t.prices_transactions_build_year <= $10::int)
+    AND ($11::bool IS NULL OR t.prices_transactions_elevator = $11::bool)
+    AND ($12::text IS NULL OR t.prices_transactions_condition = $12::text)
+    AND ($13::text IS NULL OR t.prices_transactions_energy_class = $13::text)
+    AND ($14::text IS NULL
+        OR t.prices_transactions_period_identifier = $14::text)
+ORDER BY t.prices_transactions_price_per_square_meter, t.prices_transactions_id
+LIMIT $15::int
+`
+
+type SearchPricesTransactionsByPricePerSquareMeterAscParams struct {
+	CursorValue      int32         `db:"cursor_value" json:"cursor_value"`
+	CursorID         pgtype.UUID   `db:"cursor_id" json:"cursor_id"`
+	NeighborhoodIds  []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PostalCode       *string       `db:"postal_code" json:"postal_code"`
+	BuildingType     *string       `db:"building_type" json:"building_type"`
+	Category         *string       `db:"category" json:"category"`
+	MinArea          *float64      `db:"min_area" json:"min_area"`
+	MaxArea          *float64      `db:"max_area" json:"max_area"`
+	MinBuildYear     *int32        `db:"min_build_year" json:"min_build_year"`
+	MaxBuildYear     *int32        `db:"max_build_year" json:"max_build_year"`
+	Elevator         *bool         `db:"elevator" json:"elevator"`
+	Condition        *string       `db:"condition" json:"condition"`
+	EnergyClass      *string       `db:"energy_class" json:"energy_class"`
+	PeriodIdentifier *string       `db:"period_identifier" json:"period_identifier"`
+	PageSize         int32         `db:"page_size" json:"page_size"`
+}
+
+func (q *Queries) SearchPricesTransactionsByPricePerSquareMeterAsc(ctx context.Context, arg *SearchPricesTransactionsByPricePerSquareMeterAscParams) ([]VwPricesTransaction, error) {
+	rows, err := q.db.Query(ctx, searchPricesTransactionsByPricePerSquareMeterAsc,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.NeighborhoodIds,
+		arg.PostalCode,
+		arg.BuildingType,
+		arg.Category,
+		arg.MinArea,
+		arg.MaxArea,
+		arg.MinBuildYear,
+		arg.MaxBuildYear,
+		arg.Elevator,
+		arg.Condition,
+		arg.EnergyClass,
+		arg.PeriodIdentifier,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VwPricesTransaction{}
+	for rows.Next() {
+		var i VwPricesTransaction
+		if err := rows.Scan(
+			&i.PricesTransactionsID,
+			&i.PricesTransactionsDescription,
+			&i.PricesTransactionsType,
+			&i.PricesTransactionsArea,
+			&i.PricesTransactionsPrice,
+			&i.PricesTransactionsPricePerSquareMeter,
+			&i.PricesTransactionsBuildYear,
+			&i.PricesTransactionsFloor,
+			&i.PricesTransactionsElevator,
+			&i.PricesTransactionsCondition,
+			&i.PricesTransactionsPlot,
+			&i.PricesTransactionsEnergyClass,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.PricesTransactionsCategory,
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesPostalCodesCode,
+			&i.PricesCitiesName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPricesTransactionsByPricePerSquareMeterDesc = `-- name: SearchPricesTransactionsByPricePerSquareMeterDesc :many
+SELECT prices_transactions_id, prices_transactions_description, prices_transactions_type, prices_transactions_area, prices_transactions_price, prices_transactions_price_per_square_meter, prices_transactions_build_year, prices_transactions_floor, prices_transactions_elevator, prices_transactions_condition, prices_transactions_plot, prices_transactions_energy_class, prices_transactions_period_identifier, prices_transactions_category, prices_neighborhoods_id, prices_neighborhoods_name, prices_postal_codes_code, prices_cities_name FROM public.vw_prices_transactions AS t
+WHERE (t.prices_transactions_price_per_square_meter, t.prices_transactions_id) < ($1::int, $2::uuid)
+    AND ($3::uuid[] IS NULL
+        OR t.prices_neighborhoods_id = ANY($3::uuid[]))
+    AND ($4::text IS NULL OR t.prices_postal_codes_code = $4::text)
+    AND ($5::text IS NULL OR t.prices_transactions_type = $5::text)
+    AND ($6::text IS NULL OR t.prices_transactions_category = $6::text)
+    AND ($7::float8 IS NULL OR t.prices_transactions_area >= $7::float8)
+    AND ($8::float8 IS NULL OR t.prices_transactions_area <= $8::float8)
+    AND ($9::int IS NULL OR t.prices_transactions_build_year >= $9::int)
+    AND ($10::int IS NULL OR t.prices_transactions_build_year <= $10::int)
+    AND ($11::bool IS NULL OR t.prices_transactions_elevator = $11::bool)
+    AND ($12::text IS NULL OR t.prices_transactions_condition = $12::text)
+    AND ($13::text IS NULL OR t.prices_transactions_energy_class = $13::text)
+    AND ($14::text IS NULL
+        OR t.prices_transactions_period_identifier = $14::text)
+ORDER BY t.prices_transactions_price_per_square_meter DESC, t.prices_transactions_id DESC
+LIMIT $15::int
+`
+
+type SearchPricesTransactionsByPricePerSquareMeterDescParams struct {
+	CursorValue      int32         `db:"cursor_value" json:"cursor_value"`
+	CursorID         pgtype.UUID   `db:"cursor_id" json:"cursor_id"`
+	NeighborhoodIds  []pgtype.UUID `db:"neighborhood_ids" json:"neighborhood_ids"`
+	PostalCode       *string       `db:"postal_code" json:"postal_code"`
+	BuildingType     *string       `db:"building_type" json:"building_type"`
+	Category         *string       `db:"category" json:"category"`
+	MinArea          *float64      `db:"min_area" json:"min_area"`
+	MaxArea          *float64      `db:"max_area" json:"max_area"`
+	MinBuildYear     *int32        `db:"min_build_year" json:"min_build_year"`
+	MaxBuildYear     *int32        `db:"max_build_year" json:"max_build_year"`
+	Elevator         *bool         `db:"elevator" json:"elevator"`
+	Condition        *string       `db:"condition" json:"condition"`
+	EnergyClass      *string       `db:"energy_class" json:"energy_class"`
+	PeriodIdentifier *string       `db:"period_identifier" json:"period_identifier"`
+	PageSize         int32         `db:"page_size" json:"page_size"`
+}
+
+func (q *Queries) SearchPricesTransactionsByPricePerSquareMeterDesc(ctx context.Context, arg *SearchPricesTransactionsByPricePerSquareMeterDescParams) ([]VwPricesTransaction, error) {
+	rows, err := q.db.Query(ctx, searchPricesTransactionsByPricePerSquareMeterDesc,
+		arg.CursorValue,
+		arg.CursorID,
+		arg.NeighborhoodIds,
+		arg.PostalCode,
+		arg.BuildingType,
+		arg.Category,
+		arg.MinArea,
+		arg.MaxArea,
+		arg.MinBuildYear,
+		arg.MaxBuildYear,
+		arg.Elevator,
+		arg.Condition,
+		arg.EnergyClass,
+		arg.PeriodIdentifier,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VwPricesTransaction{}
+	for rows.Next() {
+		var i VwPricesTransaction
+		if err := rows.Scan(
+			&i.PricesTransactionsID,
+			&i.PricesTransactionsDescription,
+			&i.PricesTransactionsType,
+			&i.PricesTransactionsArea,
+			&i.PricesTransactionsPrice,
+			&i.PricesTransactionsPricePerSquareMeter,
+			&i.PricesTransactionsBuildYear,
+			&i.PricesTransactionsFloor,
+			&i.PricesTransactionsElevator,
+			&i.PricesTransactionsCondition,
+			&i.PricesTransactionsPlot,
+			&i.PricesTransactionsEnergyClass,
+			&i.PricesTransactionsPeriodIdentifier,
+			&i.PricesTransactionsCategory,
+			&i.PricesNeighborhoodsID,
+			&i.PricesNeighborhoodsName,
+			&i.PricesPostalCodesCode,
+			&i.PricesCitiesName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertPricesCity = `-- name: UpsertPricesCity :one
 INSERT INTO public.prices_cities (
     prices_cities_name,
@@ -64,3 +64,37 @@ CREATE TABLE public.prices_transactions (
 
 CREATE INDEX idx_prices_transactions_period_identifier
     ON public.prices_transactions(prices_transactions_period_identifier);
+
+CREATE INDEX idx_prices_transactions_neighborhood_price_per_sqm
+    ON public.prices_transactions(prices_neighborhoods_id, prices_transactions_price_per_square_meter, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_price_per_sqm
+    ON public.prices_transactions(prices_transactions_price_per_square_meter, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_price
+    ON public.prices_transactions(prices_transactions_price, prices_transactions_id);
+
+CREATE INDEX idx_prices_transactions_build_year
+    ON public.prices_transactions(prices_transactions_build_year, prices_transactions_id);
+
+-- View signature for sqlc, see db/migrations/002_prices_transactions_search.sql
+CREATE TABLE public.vw_prices_transactions (
+    prices_transactions_id                          uuid             NOT NULL,
+    prices_transactions_description                 text             NOT NULL,
+    prices_transactions_type                        text             NOT NULL,
+    prices_transactions_area                        double precision NOT NULL,
+    prices_transactions_price                       integer          NOT NULL,
+    prices_transactions_price_per_square_meter      integer          NOT NULL,
+    prices_transactions_build_year                  integer          NOT NULL,
+    prices_transactions_floor                       text,
+    prices_transactions_elevator                    boolean          NOT NULL,
+    prices_transactions_condition                   text,
+    prices_transactions_plot                        text,
+    prices_transactions_energy_class                text,
+    prices_transactions_period_identifier           text             NOT NULL,
+    prices_transactions_category                    text             NOT NULL,
+    prices_neighborhoods_id                         uuid,
+    prices_neighborhoods_name                       text,
+    prices_postal_codes_code                        text,
+    prices_cities_name                              text
+);
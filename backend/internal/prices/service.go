@@ -10,6 +10,7 @@ import (
 
 	"koditon-go/internal/prices/client"
 	"koditon-go/internal/prices/db"
+	"koditon-go/internal/transport"
 	"koditon-go/internal/util"
 )
 
@@ -22,8 +23,9 @@ type Service struct {
 func NewService(
 	dbtx db.DBTX,
 	baseURL string,
+	limiter *transport.Limiter,
 ) (*Service, error) {
-	pricesClient, err := client.NewClient(baseURL)
+	pricesClient, err := client.NewClient(baseURL, limiter)
 	if err != nil {
 		return nil, fmt.Errorf("create prices client: %w", err)
 	}
@@ -0,0 +1,98 @@
+package prices
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"koditon-go/internal/prices/client"
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+const (
+	SourceName = "prices"
+
+	EntityTypeCities = "prices_cities"
+	EntityTypeCity   = "prices_city"
+
+	TaskTypeCitiesInit = "prices_cities_init"
+	TaskTypeSync       = "prices_sync"
+)
+
+// Source runs the city discovery and per city transaction syncs for the task
+// queue.
+type Source struct {
+	service         *Service
+	taskQueueClient *taskqueue.Client
+}
+
+func NewSource(service *Service, taskQueueClient *taskqueue.Client) *Source {
+	return &Source{
+		service:         service,
+		taskQueueClient: taskQueueClient,
+	}
+}
+
+func (s *Source) Name() string {
+	return SourceName
+}
+
+func (s *Source) TaskTypes() []sources.TaskType {
+	return []sources.TaskType{
+		{
+			Name:        TaskTypeCitiesInit,
+			EntityTypes: []string{EntityTypeCities},
+			Handle:      s.handleCitiesInit,
+		},
+		{
+			Name:        TaskTypeSync,
+			EntityTypes: []string{EntityTypeCity},
+			Handle:      s.handleSync,
+		},
+	}
+}
+
+func (s *Source) ClassifyError(err error) error {
+	if httpErr, ok := client.IsHTTPStatusError(err); ok {
+		return sources.ClassifyHTTPStatus(err, httpErr.StatusCode, httpErr.RetryAfter)
+	}
+	return err
+}
+
+func (s *Source) handleCitiesInit(ctx context.Context, logger *slog.Logger, _ taskqueuedb.TaskQueueTask) error {
+	logger.InfoContext(ctx, "processing prices cities initialization task")
+	cities, err := s.service.FetchCities(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch cities: %w", err)
+	}
+	if len(cities) > 0 {
+		cityEntityIDs := make([]string, 0, len(cities))
+		for _, city := range cities {
+			cityEntityIDs = append(cityEntityIDs, taskqueue.EntityPrefixCity+city)
+		}
+		count, regErr := s.taskQueueClient.RegisterEntities(ctx, cityEntityIDs, EntityTypeCity, "daily")
+		if regErr != nil {
+			logger.WarnContext(ctx, "failed to register city entities", "error", regErr)
+		} else {
+			logger.InfoContext(ctx, "city entities registered", "count", count)
+		}
+	}
+	return nil
+}
+
+func (s *Source) handleSync(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error {
+	entityType, cityName, err := sources.ParseEntityID(task.EntityID)
+	if err != nil {
+		return err
+	}
+	if entityType != "city" {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   fmt.Sprintf("expected city entity type for prices sync, got: %s", entityType),
+		}
+	}
+	logger.InfoContext(ctx, "syncing prices for city", "city", cityName)
+	return s.service.SyncCity(ctx, cityName)
+}
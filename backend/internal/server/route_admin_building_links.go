@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	buildingsdb "koditon-go/internal/buildings/db"
+	"koditon-go/internal/util"
+)
+
+// pgForeignKeyViolation is the SQLSTATE of a foreign key violation.
+const pgForeignKeyViolation = "23503"
+
+type BuildingLink struct {
+	ID                  string    `json:"id"`
+	FrontdoorBuildingID string    `json:"frontdoor_building_id"`
+	ShortcutBuildingID  string    `json:"shortcut_building_id"`
+	Status              string    `json:"status" enum:"linked,rejected"`
+	Method              string    `json:"method" enum:"auto,manual"`
+	Confidence          float64   `json:"confidence" doc:"Match score between 0 and 1"`
+	DistanceMeters      *float64  `json:"distance_meters,omitempty"`
+	AddressMatch        bool      `json:"address_match"`
+	PostcodeMatch       bool      `json:"postcode_match"`
+	BuildYearMatch      bool      `json:"build_year_match"`
+	Note                *string   `json:"note,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type SetBuildingLinkRequest struct {
+	FrontdoorBuildingID string `json:"frontdoor_building_id" format:"uuid"`
+	ShortcutBuildingID  string `json:"shortcut_building_id" format:"uuid"`
+	Status              string `json:"status" enum:"linked,rejected" doc:"linked pairs the buildings and releases their other links, rejected keeps the matcher from pairing them"`
+	Note                string `json:"note,omitempty" maxLength:"1000"`
+}
+
+type listBuildingLinksInput struct {
+	Status        string  `query:"status" enum:"linked,rejected"`
+	Method        string  `query:"method" enum:"auto,manual"`
+	MaxConfidence float64 `query:"max_confidence" minimum:"0" maximum:"1" default:"1" doc:"Only links scoring at most this, useful for reviewing weak matches"`
+	Limit         int32   `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset        int32   `query:"offset" minimum:"0"`
+}
+
+type listBuildingLinksOutput struct {
+	Body []BuildingLink
+}
+
+type setBuildingLinkInput struct {
+	Body SetBuildingLinkRequest
+}
+
+type buildingLinkOutput struct {
+	Body BuildingLink
+}
+
+type buildingLinkIDInput struct {
+	ID string `path:"id" format:"uuid"`
+}
+
+func (s *Server) listBuildingLinksHandler(ctx context.Context, input *listBuildingLinksInput) (*listBuildingLinksOutput, error) {
+	links, err := s.buildingsQueries.ListBuildingLinks(ctx, &buildingsdb.ListBuildingLinksParams{
+		Status:        util.ToStringPtr(input.Status),
+		Method:        util.ToStringPtr(input.Method),
+		MaxConfidence: &input.MaxConfidence,
+		PageSize:      input.Limit,
+		PageOffset:    input.Offset,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list building links", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list building links")
+	}
+	out := make([]BuildingLink, 0, len(links))
+	for _, link := range links {
+		out = append(out, mapBuildingLink(link))
+	}
+	return &listBuildingLinksOutput{Body: out}, nil
+}
+
+func (s *Server) setBuildingLinkHandler(ctx context.Context, input *setBuildingLinkInput) (*buildingLinkOutput, error) {
+	req := input.Body
+	frontdoorID, err := uuid.Parse(req.FrontdoorBuildingID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid frontdoor building id: %s", req.FrontdoorBuildingID))
+	}
+	shortcutID, err := uuid.Parse(req.ShortcutBuildingID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid shortcut building id: %s", req.ShortcutBuildingID))
+	}
+	linkID, err := s.buildingsQueries.SetBuildingLink(ctx, &buildingsdb.SetBuildingLinkParams{
+		FrontdoorBuildingID: util.ToUUID(frontdoorID),
+		ShortcutBuildingID:  util.ToUUID(shortcutID),
+		Status:              req.Status,
+		Note:                util.ToStringPtr(req.Note),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return nil, huma.Error404NotFound("frontdoor or shortcut building not found")
+		}
+		s.logger.ErrorContext(ctx, "failed to set building link", "frontdoor_building_id", req.FrontdoorBuildingID, "shortcut_building_id", req.ShortcutBuildingID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to set building link")
+	}
+	s.logger.InfoContext(ctx, "building link set", "link_id", util.FromUUID(linkID), "status", req.Status)
+	return s.getBuildingLink(ctx, linkID)
+}
+
+func (s *Server) deleteBuildingLinkHandler(ctx context.Context, input *buildingLinkIDInput) (*struct{}, error) {
+	id, err := uuid.Parse(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid building link id: %s", input.ID))
+	}
+	deleted, err := s.buildingsQueries.DeleteBuildingLink(ctx, util.ToUUID(id))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete building link", "link_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to delete building link")
+	}
+	if deleted == 0 {
+		return nil, huma.Error404NotFound(fmt.Sprintf("building link %s not found", input.ID))
+	}
+	s.logger.InfoContext(ctx, "building link deleted", "link_id", input.ID)
+	return nil, nil
+}
+
+func (s *Server) getBuildingLink(ctx context.Context, id pgtype.UUID) (*buildingLinkOutput, error) {
+	link, err := s.buildingsQueries.GetBuildingLinkByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("building link %s not found", util.FromUUID(id)))
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get building link", "link_id", util.FromUUID(id), "error", err)
+		return nil, huma.Error500InternalServerError("failed to get building link")
+	}
+	return &buildingLinkOutput{Body: mapBuildingLink(link)}, nil
+}
+
+func mapBuildingLink(link buildingsdb.BuildingLink) BuildingLink {
+	return BuildingLink{
+		ID:                  util.FromUUID(link.BuildingLinksID),
+		FrontdoorBuildingID: util.FromUUID(link.BuildingLinksFrontdoorBuildingID),
+		ShortcutBuildingID:  util.FromUUID(link.BuildingLinksShortcutBuildingID),
+		Status:              link.BuildingLinksStatus,
+		Method:              link.BuildingLinksMethod,
+		Confidence:          link.BuildingLinksConfidence,
+		DistanceMeters:      link.BuildingLinksDistanceMeters,
+		AddressMatch:        link.BuildingLinksAddressMatch,
+		PostcodeMatch:       link.BuildingLinksPostcodeMatch,
+		BuildYearMatch:      link.BuildingLinksBuildYearMatch,
+		Note:                link.BuildingLinksNote,
+		CreatedAt:           link.BuildingLinksCreatedAt,
+		UpdatedAt:           link.BuildingLinksUpdatedAt,
+	}
+}
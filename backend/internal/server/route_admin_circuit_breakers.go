@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"koditon-go/internal/taskqueue"
+)
+
+type CircuitBreaker struct {
+	Source       string     `json:"source"`
+	State        string     `json:"state" enum:"closed,open,half_open"`
+	Tasks        int        `json:"tasks" doc:"Tasks in the failure rate window of a closed breaker"`
+	Failures     int        `json:"failures" doc:"Failed tasks in the window"`
+	Trips        int        `json:"trips" doc:"Times the breaker opened since the process started"`
+	OpenedAt     *time.Time `json:"opened_at,omitempty"`
+	OpenUntil    *time.Time `json:"open_until,omitempty" doc:"When the next probe task is let through"`
+	LastFailure  string     `json:"last_failure,omitempty"`
+	LastChangeAt *time.Time `json:"last_change_at,omitempty"`
+}
+
+type listCircuitBreakersOutput struct {
+	Body []CircuitBreaker
+}
+
+// listCircuitBreakersHandler reports the breakers of the workers of this
+// process; they are kept in memory.
+func (s *Server) listCircuitBreakersHandler(_ context.Context, _ *struct{}) (*listCircuitBreakersOutput, error) {
+	out := []CircuitBreaker{}
+	if s.workers != nil {
+		for _, b := range s.workers.Breakers() {
+			out = append(out, mapCircuitBreaker(b))
+		}
+	}
+	return &listCircuitBreakersOutput{Body: out}, nil
+}
+
+func mapCircuitBreaker(b taskqueue.BreakerStatus) CircuitBreaker {
+	return CircuitBreaker{
+		Source:       b.Name,
+		State:        string(b.State),
+		Tasks:        b.Tasks,
+		Failures:     b.Failures,
+		Trips:        b.Trips,
+		OpenedAt:     b.OpenedAt,
+		OpenUntil:    b.OpenUntil,
+		LastFailure:  b.LastFailure,
+		LastChangeAt: b.LastChangeAt,
+	}
+}
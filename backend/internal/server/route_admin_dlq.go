@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"koditon-go/internal/taskqueue"
+)
+
+type DLQEntry struct {
+	ID                int64           `json:"id"`
+	OriginalTaskID    int64           `json:"original_task_id"`
+	EntityID          string          `json:"entity_id"`
+	TaskType          string          `json:"task_type"`
+	Priority          int32           `json:"priority"`
+	TotalAttempts     int32           `json:"total_attempts"`
+	FirstError        *string         `json:"first_error,omitempty"`
+	LastError         string          `json:"last_error"`
+	ErrorHistory      json.RawMessage `json:"error_history"`
+	OriginalCreatedAt time.Time       `json:"original_created_at"`
+	FirstAttemptedAt  *time.Time      `json:"first_attempted_at,omitempty"`
+	LastAttemptedAt   time.Time       `json:"last_attempted_at"`
+	MovedToDLQAt      time.Time       `json:"moved_to_dlq_at"`
+	RequeuedAt        *time.Time      `json:"requeued_at,omitempty"`
+	RequeueCount      int32           `json:"requeue_count"`
+}
+
+type DLQStats struct {
+	Total      int64            `json:"total"`
+	Pending    int64            `json:"pending"`
+	Requeued   int64            `json:"requeued"`
+	ByTaskType map[string]int64 `json:"by_task_type" doc:"Pending entries per task type"`
+}
+
+type RequeueDLQEntryRequest struct {
+	Priority    *int `json:"priority,omitempty" doc:"Defaults to the priority of the original task"`
+	MaxAttempts int  `json:"max_attempts,omitempty" minimum:"1" maximum:"10" default:"3"`
+}
+
+type RequeueDLQEntryResponse struct {
+	TaskID int64 `json:"task_id"`
+}
+
+type DLQBulkRequest struct {
+	TaskType       string `json:"task_type,omitempty"`
+	EntityPrefix   string `json:"entity_prefix,omitempty" doc:"Match entity IDs starting with this prefix, e.g. ad:"`
+	ErrorSubstring string `json:"error_substring,omitempty" doc:"Case-insensitive match against the last error"`
+	DryRun         bool   `json:"dry_run,omitempty" doc:"Only count the matching entries"`
+	Priority       *int   `json:"priority,omitempty" doc:"Requeue only, defaults to the priority of the original task"`
+	MaxAttempts    int    `json:"max_attempts,omitempty" minimum:"1" maximum:"10" default:"3" doc:"Requeue only"`
+	Limit          int    `json:"limit,omitempty" minimum:"1" maximum:"500" default:"100" doc:"Requeue only, the most entries requeued per request"`
+}
+
+type DLQBulkResponse struct {
+	Matched  int64 `json:"matched"`
+	Affected int64 `json:"affected"`
+	DryRun   bool  `json:"dry_run"`
+}
+
+type listDLQEntriesInput struct {
+	TaskType       string `query:"task_type"`
+	EntityPrefix   string `query:"entity_prefix"`
+	ErrorSubstring string `query:"error_substring"`
+	PendingOnly    bool   `query:"pending_only" doc:"Hide entries that have already been requeued"`
+	Limit          int    `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset         int    `query:"offset" minimum:"0"`
+}
+
+type listDLQEntriesOutput struct {
+	Body []DLQEntry
+}
+
+type dlqIDInput struct {
+	ID int64 `path:"id"`
+}
+
+type dlqEntryOutput struct {
+	Body DLQEntry
+}
+
+type dlqStatsOutput struct {
+	Body DLQStats
+}
+
+type requeueDLQEntryInput struct {
+	ID   int64 `path:"id"`
+	Body RequeueDLQEntryRequest
+}
+
+type requeueDLQEntryOutput struct {
+	Body RequeueDLQEntryResponse
+}
+
+type dlqBulkInput struct {
+	Body DLQBulkRequest
+}
+
+type dlqBulkOutput struct {
+	Body DLQBulkResponse
+}
+
+func (s *Server) listDLQEntriesHandler(ctx context.Context, input *listDLQEntriesInput) (*listDLQEntriesOutput, error) {
+	filter := taskqueue.DLQFilter{
+		TaskType:       input.TaskType,
+		EntityPrefix:   input.EntityPrefix,
+		ErrorSubstring: input.ErrorSubstring,
+		PendingOnly:    input.PendingOnly,
+	}
+	entries, err := s.taskQueue.ListDLQEntriesMatching(ctx, filter, input.Limit, input.Offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list DLQ entries", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list DLQ entries")
+	}
+	out := make([]DLQEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, mapDLQEntry(entry))
+	}
+	return &listDLQEntriesOutput{Body: out}, nil
+}
+
+func (s *Server) getDLQEntryHandler(ctx context.Context, input *dlqIDInput) (*dlqEntryOutput, error) {
+	entry, err := s.taskQueue.GetDLQEntry(ctx, input.ID)
+	if err != nil {
+		return nil, s.dlqError(ctx, input.ID, err)
+	}
+	return &dlqEntryOutput{Body: mapDLQEntry(*entry)}, nil
+}
+
+func (s *Server) dlqStatsHandler(ctx context.Context, _ *struct{}) (*dlqStatsOutput, error) {
+	stats, err := s.taskQueue.GetDLQStats(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get DLQ stats", "error", err)
+		return nil, huma.Error500InternalServerError("failed to get DLQ stats")
+	}
+	byTaskType, err := s.taskQueue.GetDLQStatsByTaskType(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get DLQ stats by task type", "error", err)
+		return nil, huma.Error500InternalServerError("failed to get DLQ stats")
+	}
+	out := DLQStats{
+		Total:      stats.Total,
+		Pending:    stats.Pending,
+		Requeued:   stats.Requeued,
+		ByTaskType: make(map[string]int64, len(byTaskType)),
+	}
+	for _, row := range byTaskType {
+		out.ByTaskType[row.TaskType] = row.Count
+	}
+	return &dlqStatsOutput{Body: out}, nil
+}
+
+func (s *Server) requeueDLQEntryHandler(ctx context.Context, input *requeueDLQEntryInput) (*requeueDLQEntryOutput, error) {
+	taskID, err := s.taskQueue.RequeueFromDLQ(ctx, input.ID, input.Body.Priority, input.Body.MaxAttempts)
+	if err != nil {
+		return nil, s.dlqError(ctx, input.ID, err)
+	}
+	s.logger.InfoContext(ctx, "DLQ entry requeued", "dlq_id", input.ID, "task_id", taskID)
+	return &requeueDLQEntryOutput{Body: RequeueDLQEntryResponse{TaskID: taskID}}, nil
+}
+
+func (s *Server) deleteDLQEntryHandler(ctx context.Context, input *dlqIDInput) (*struct{}, error) {
+	if _, err := s.taskQueue.GetDLQEntry(ctx, input.ID); err != nil {
+		return nil, s.dlqError(ctx, input.ID, err)
+	}
+	if err := s.taskQueue.DeleteDLQEntry(ctx, input.ID); err != nil {
+		return nil, s.dlqError(ctx, input.ID, err)
+	}
+	s.logger.InfoContext(ctx, "DLQ entry deleted", "dlq_id", input.ID)
+	return nil, nil
+}
+
+func (s *Server) bulkRequeueDLQHandler(ctx context.Context, input *dlqBulkInput) (*dlqBulkOutput, error) {
+	filter := mapDLQBulkFilter(input.Body)
+	if filter == (taskqueue.DLQFilter{}) {
+		return nil, huma.Error400BadRequest("at least one of task_type, entity_prefix or error_substring is required")
+	}
+	// only entries that have not been requeued yet are eligible
+	filter.PendingOnly = true
+	matched, err := s.taskQueue.CountDLQEntriesMatching(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to count DLQ entries", "error", err)
+		return nil, huma.Error500InternalServerError("failed to count DLQ entries")
+	}
+	out := DLQBulkResponse{Matched: matched, DryRun: input.Body.DryRun}
+	if input.Body.DryRun {
+		return &dlqBulkOutput{Body: out}, nil
+	}
+	requeued, err := s.taskQueue.BulkRequeueFromDLQ(ctx, filter, input.Body.Limit, input.Body.Priority, input.Body.MaxAttempts)
+	s.logger.InfoContext(ctx, "bulk DLQ requeue finished", "matched", matched, "requeued", requeued, "filter", filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "bulk DLQ requeue failed", "requeued", requeued, "error", err)
+		return nil, huma.Error500InternalServerError(fmt.Sprintf("bulk requeue failed after %d entries", requeued))
+	}
+	out.Affected = int64(requeued)
+	return &dlqBulkOutput{Body: out}, nil
+}
+
+func (s *Server) bulkDeleteDLQHandler(ctx context.Context, input *dlqBulkInput) (*dlqBulkOutput, error) {
+	filter := mapDLQBulkFilter(input.Body)
+	if filter == (taskqueue.DLQFilter{}) {
+		return nil, huma.Error400BadRequest("at least one of task_type, entity_prefix or error_substring is required")
+	}
+	matched, err := s.taskQueue.CountDLQEntriesMatching(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to count DLQ entries", "error", err)
+		return nil, huma.Error500InternalServerError("failed to count DLQ entries")
+	}
+	out := DLQBulkResponse{Matched: matched, DryRun: input.Body.DryRun}
+	if input.Body.DryRun {
+		return &dlqBulkOutput{Body: out}, nil
+	}
+	deleted, err := s.taskQueue.BulkDeleteDLQEntries(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "bulk DLQ delete failed", "error", err)
+		return nil, huma.Error500InternalServerError("bulk delete failed")
+	}
+	s.logger.InfoContext(ctx, "bulk DLQ delete finished", "deleted", deleted, "filter", filter)
+	out.Affected = deleted
+	return &dlqBulkOutput{Body: out}, nil
+}
+
+func (s *Server) dlqError(ctx context.Context, dlqID int64, err error) error {
+	if errors.Is(err, taskqueue.ErrDLQEntryNotFound) {
+		return huma.Error404NotFound(fmt.Sprintf("DLQ entry %d not found", dlqID))
+	}
+	s.logger.ErrorContext(ctx, "DLQ operation failed", "dlq_id", dlqID, "error", err)
+	return huma.Error500InternalServerError("DLQ operation failed")
+}
+
+func mapDLQBulkFilter(req DLQBulkRequest) taskqueue.DLQFilter {
+	return taskqueue.DLQFilter{
+		TaskType:       req.TaskType,
+		EntityPrefix:   req.EntityPrefix,
+		ErrorSubstring: req.ErrorSubstring,
+	}
+}
+
+func mapDLQEntry(e taskqueue.DLQEntry) DLQEntry {
+	history := e.ErrorHistory
+	if len(history) == 0 {
+		history = json.RawMessage("[]")
+	}
+	return DLQEntry{
+		ID:                e.DLQID,
+		OriginalTaskID:    e.OriginalTaskID,
+		EntityID:          e.EntityID,
+		TaskType:          e.TaskType,
+		Priority:          e.Priority,
+		TotalAttempts:     e.TotalAttempts,
+		FirstError:        e.FirstError,
+		LastError:         e.LastError,
+		ErrorHistory:      history,
+		OriginalCreatedAt: e.OriginalCreatedAt,
+		FirstAttemptedAt:  e.FirstAttemptedAt,
+		LastAttemptedAt:   e.LastAttemptedAt,
+		MovedToDLQAt:      e.MovedToDLQAt,
+		RequeuedAt:        e.RequeuedAt,
+		RequeueCount:      e.RequeueCount,
+	}
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+// entityTypePrefixes lists the entity ID prefix expected for each entity type
+// by the sync handlers.
+var entityTypePrefixes = map[string]string{
+	"frontdoor_ad":       taskqueue.EntityPrefixAd,
+	"frontdoor_building": taskqueue.EntityPrefixBuilding,
+	"shortcut_ad":        taskqueue.EntityPrefixAd,
+	"shortcut_building":  taskqueue.EntityPrefixBuilding,
+	"prices_city":        taskqueue.EntityPrefixCity,
+}
+
+type Entity struct {
+	ID                 string     `json:"id"`
+	EntityType         string     `json:"entity_type"`
+	Status             string     `json:"status"`
+	SchedulingStrategy string     `json:"scheduling_strategy"`
+	ManualOverride     bool       `json:"manual_override" doc:"Whether the status or strategy was set by hand and is kept by the sitemap crawls"`
+	CreatedAt          *time.Time `json:"created_at,omitempty"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+}
+
+type EntitySyncStatus struct {
+	LastTaskID          *int64     `json:"last_task_id,omitempty"`
+	LastTaskStatus      *string    `json:"last_task_status,omitempty"`
+	LastTaskCompletedAt *time.Time `json:"last_task_completed_at,omitempty"`
+	LastTaskError       *string    `json:"last_task_error,omitempty"`
+	TotalCompletedCount int64      `json:"total_completed_count"`
+	TotalFailedCount    int64      `json:"total_failed_count"`
+	SuccessRate         float64    `json:"success_rate" doc:"Percentage of the entity's tasks that completed"`
+}
+
+type EntityHistory struct {
+	Entity     Entity           `json:"entity"`
+	SyncStatus EntitySyncStatus `json:"sync_status"`
+	Tasks      []Task           `json:"tasks" doc:"Ordered by priority, then newest first"`
+}
+
+type RegisterEntityRequest struct {
+	EntityID           string `json:"entity_id" minLength:"1" doc:"Entity to track, e.g. ad:123456"`
+	EntityType         string `json:"entity_type" enum:"frontdoor_ad,frontdoor_building,shortcut_ad,shortcut_building,prices_city"`
+	SchedulingStrategy string `json:"scheduling_strategy,omitempty" enum:"daily,manual,on_demand,cron" default:"daily"`
+}
+
+type UpdateEntityStrategyRequest struct {
+	SchedulingStrategy string `json:"scheduling_strategy" enum:"daily,manual,on_demand,cron"`
+}
+
+type listEntitiesInput struct {
+	EntityType         string `query:"entity_type"`
+	Status             string `query:"status" enum:"active,stopped,removed"`
+	SchedulingStrategy string `query:"scheduling_strategy" enum:"daily,manual,on_demand,cron"`
+	Limit              int    `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset             int    `query:"offset" minimum:"0"`
+}
+
+type listEntitiesOutput struct {
+	Body []Entity
+}
+
+type entityIDInput struct {
+	ID string `path:"id" doc:"Entity ID, e.g. ad:123456"`
+}
+
+type entityOutput struct {
+	Body Entity
+}
+
+type registerEntityInput struct {
+	Body RegisterEntityRequest
+}
+
+type updateEntityStrategyInput struct {
+	ID   string `path:"id"`
+	Body UpdateEntityStrategyRequest
+}
+
+type entityHistoryInput struct {
+	ID    string `path:"id"`
+	Limit int    `query:"limit" minimum:"1" maximum:"500" default:"20" doc:"Number of recent tasks to include"`
+}
+
+type entityHistoryOutput struct {
+	Body EntityHistory
+}
+
+func (s *Server) listEntitiesHandler(ctx context.Context, input *listEntitiesInput) (*listEntitiesOutput, error) {
+	filter := taskqueue.EntityFilter{
+		EntityType:         input.EntityType,
+		Status:             input.Status,
+		SchedulingStrategy: input.SchedulingStrategy,
+	}
+	entities, err := s.taskQueue.ListEntities(ctx, filter, input.Limit, input.Offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list entities", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list entities")
+	}
+	out := make([]Entity, 0, len(entities))
+	for _, entity := range entities {
+		out = append(out, mapEntity(entity))
+	}
+	return &listEntitiesOutput{Body: out}, nil
+}
+
+func (s *Server) getEntityHandler(ctx context.Context, input *entityIDInput) (*entityOutput, error) {
+	entity, err := s.taskQueue.GetEntity(ctx, input.ID)
+	if err != nil {
+		return nil, s.entityError(ctx, input.ID, err)
+	}
+	return &entityOutput{Body: mapEntity(*entity)}, nil
+}
+
+func (s *Server) registerEntityHandler(ctx context.Context, input *registerEntityInput) (*entityOutput, error) {
+	req := input.Body
+	prefix := entityTypePrefixes[req.EntityType]
+	if !strings.HasPrefix(req.EntityID, prefix) || len(req.EntityID) == len(prefix) {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("entity id for %s must look like %s<id>", req.EntityType, prefix))
+	}
+	err := s.taskQueue.RegisterEntity(ctx, req.EntityID, req.EntityType, string(taskqueue.EntityStatusActive), req.SchedulingStrategy)
+	if err != nil {
+		return nil, s.entityError(ctx, req.EntityID, err)
+	}
+	s.logger.InfoContext(ctx, "entity registered", "entity_id", req.EntityID, "entity_type", req.EntityType, "scheduling_strategy", req.SchedulingStrategy)
+	return s.getEntityHandler(ctx, &entityIDInput{ID: req.EntityID})
+}
+
+func (s *Server) pauseEntityHandler(ctx context.Context, input *entityIDInput) (*entityOutput, error) {
+	return s.setEntityStatus(ctx, input.ID, taskqueue.EntityStatusStopped)
+}
+
+func (s *Server) resumeEntityHandler(ctx context.Context, input *entityIDInput) (*entityOutput, error) {
+	return s.setEntityStatus(ctx, input.ID, taskqueue.EntityStatusActive)
+}
+
+func (s *Server) setEntityStatus(ctx context.Context, entityID string, status taskqueue.EntityStatus) (*entityOutput, error) {
+	if err := s.taskQueue.SetEntityStatus(ctx, entityID, status); err != nil {
+		return nil, s.entityError(ctx, entityID, err)
+	}
+	s.logger.InfoContext(ctx, "entity status updated", "entity_id", entityID, "status", status)
+	return s.getEntityHandler(ctx, &entityIDInput{ID: entityID})
+}
+
+func (s *Server) updateEntityStrategyHandler(ctx context.Context, input *updateEntityStrategyInput) (*entityOutput, error) {
+	if err := s.taskQueue.SetEntitySchedulingStrategy(ctx, input.ID, input.Body.SchedulingStrategy); err != nil {
+		return nil, s.entityError(ctx, input.ID, err)
+	}
+	s.logger.InfoContext(ctx, "entity scheduling strategy updated", "entity_id", input.ID, "scheduling_strategy", input.Body.SchedulingStrategy)
+	return s.getEntityHandler(ctx, &entityIDInput{ID: input.ID})
+}
+
+func (s *Server) entityHistoryHandler(ctx context.Context, input *entityHistoryInput) (*entityHistoryOutput, error) {
+	entity, err := s.taskQueue.GetEntity(ctx, input.ID)
+	if err != nil {
+		return nil, s.entityError(ctx, input.ID, err)
+	}
+	status, err := s.taskQueue.GetEntitySyncStatus(ctx, input.ID)
+	if err != nil {
+		return nil, s.entityError(ctx, input.ID, err)
+	}
+	tasks, err := s.taskQueue.ListTasks(ctx, taskqueue.TaskFilter{EntityID: input.ID}, input.Limit, 0)
+	if err != nil {
+		return nil, s.entityError(ctx, input.ID, err)
+	}
+	out := EntityHistory{
+		Entity: mapEntity(*entity),
+		SyncStatus: EntitySyncStatus{
+			LastTaskID:          status.LastTaskID,
+			LastTaskStatus:      status.LastTaskStatus,
+			LastTaskCompletedAt: status.LastTaskCompletedAt,
+			LastTaskError:       status.LastTaskError,
+			TotalCompletedCount: status.TotalCompletedCount,
+			TotalFailedCount:    status.TotalFailedCount,
+			SuccessRate:         status.SuccessRate,
+		},
+		Tasks: make([]Task, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		out.Tasks = append(out.Tasks, mapTask(task))
+	}
+	return &entityHistoryOutput{Body: out}, nil
+}
+
+func (s *Server) entityError(ctx context.Context, entityID string, err error) error {
+	if errors.Is(err, taskqueue.ErrEntityNotFound) {
+		return huma.Error404NotFound(fmt.Sprintf("entity %s not found", entityID))
+	}
+	s.logger.ErrorContext(ctx, "entity operation failed", "entity_id", entityID, "error", err)
+	return huma.Error500InternalServerError("entity operation failed")
+}
+
+func mapEntity(e taskqueuedb.TaskQueueEntityRegistry) Entity {
+	return Entity{
+		ID:                 e.EntityID,
+		EntityType:         e.EntityType,
+		Status:             e.Status,
+		SchedulingStrategy: e.SchedulingStrategy,
+		ManualOverride:     e.ManualOverride,
+		CreatedAt:          taskqueue.PgTimestamptzToTime(e.CreatedAt),
+		UpdatedAt:          taskqueue.PgTimestamptzToTime(e.UpdatedAt),
+	}
+}
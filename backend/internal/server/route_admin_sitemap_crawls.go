@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+type SitemapCrawl struct {
+	ID              int64     `json:"id"`
+	Source          string    `json:"source" enum:"frontdoor,shortcut"`
+	PreviousCrawlID *int64    `json:"previous_crawl_id,omitempty" doc:"Crawl the diff is against, empty for the first crawl of a source"`
+	EntryCount      int64     `json:"entry_count"`
+	AddedCount      int64     `json:"added_count"`
+	RemovedCount    int64     `json:"removed_count"`
+	CrawledAt       time.Time `json:"crawled_at"`
+}
+
+type SitemapCrawlChange struct {
+	EntityID   string `json:"entity_id"`
+	EntityType string `json:"entity_type"`
+	Change     string `json:"change" enum:"added,removed"`
+}
+
+type listSitemapCrawlsInput struct {
+	Source string `query:"source" enum:"frontdoor,shortcut"`
+	Limit  int    `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset int    `query:"offset" minimum:"0"`
+}
+
+type listSitemapCrawlsOutput struct {
+	Body []SitemapCrawl
+}
+
+type listSitemapCrawlChangesInput struct {
+	ID         int64  `path:"id"`
+	Change     string `query:"change" enum:"added,removed"`
+	EntityType string `query:"entity_type" doc:"e.g. frontdoor_ad or shortcut_building"`
+	Limit      int    `query:"limit" minimum:"1" maximum:"1000" default:"100"`
+	Offset     int    `query:"offset" minimum:"0"`
+}
+
+type listSitemapCrawlChangesOutput struct {
+	Body []SitemapCrawlChange
+}
+
+func (s *Server) listSitemapCrawlsHandler(ctx context.Context, input *listSitemapCrawlsInput) (*listSitemapCrawlsOutput, error) {
+	crawls, err := s.taskQueue.ListSitemapCrawls(ctx, input.Source, input.Limit, input.Offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list sitemap crawls", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list sitemap crawls")
+	}
+	out := make([]SitemapCrawl, 0, len(crawls))
+	for _, crawl := range crawls {
+		out = append(out, mapSitemapCrawl(crawl))
+	}
+	return &listSitemapCrawlsOutput{Body: out}, nil
+}
+
+func (s *Server) listSitemapCrawlChangesHandler(ctx context.Context, input *listSitemapCrawlChangesInput) (*listSitemapCrawlChangesOutput, error) {
+	if _, err := s.taskQueue.GetSitemapCrawl(ctx, input.ID); err != nil {
+		if errors.Is(err, taskqueue.ErrSitemapCrawlNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("sitemap crawl %d not found", input.ID))
+		}
+		s.logger.ErrorContext(ctx, "failed to get sitemap crawl", "crawl_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to get sitemap crawl")
+	}
+	changes, err := s.taskQueue.ListSitemapCrawlChanges(ctx, input.ID, taskqueue.SitemapCrawlChangeFilter{
+		Change:     input.Change,
+		EntityType: input.EntityType,
+	}, input.Limit, input.Offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list sitemap crawl changes", "crawl_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to list sitemap crawl changes")
+	}
+	out := make([]SitemapCrawlChange, 0, len(changes))
+	for _, change := range changes {
+		out = append(out, SitemapCrawlChange{
+			EntityID:   change.EntityID,
+			EntityType: change.EntityType,
+			Change:     change.Change,
+		})
+	}
+	return &listSitemapCrawlChangesOutput{Body: out}, nil
+}
+
+func mapSitemapCrawl(crawl taskqueuedb.TaskQueueSitemapCrawl) SitemapCrawl {
+	return SitemapCrawl{
+		ID:              crawl.CrawlID,
+		Source:          crawl.Source,
+		PreviousCrawlID: taskqueue.PgInt8ToInt64(crawl.PreviousCrawlID),
+		EntryCount:      crawl.EntryCount,
+		AddedCount:      crawl.AddedCount,
+		RemovedCount:    crawl.RemovedCount,
+		CrawledAt:       crawl.CrawledAt.Time,
+	}
+}
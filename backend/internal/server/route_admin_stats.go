@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"koditon-go/internal/taskqueue"
+)
+
+type QueueStats struct {
+	Name                 string `json:"name"`
+	Length               int64  `json:"length"`
+	OldestMessageAgeSecs *int32 `json:"oldest_message_age_seconds,omitempty"`
+	NewestMessageAgeSecs *int32 `json:"newest_message_age_seconds,omitempty"`
+	TotalMessages        int64  `json:"total_messages" doc:"Messages ever sent to the queue"`
+}
+
+type TaskStats struct {
+	Pending        int64                       `json:"pending"`
+	Processing     int64                       `json:"processing"`
+	Completed      int64                       `json:"completed"`
+	Failed         int64                       `json:"failed"`
+	Stopped        int64                       `json:"stopped"`
+	Total          int64                       `json:"total"`
+	SuccessRatePct float64                     `json:"success_rate_pct"`
+	ByType         map[string]map[string]int64 `json:"by_type" doc:"Task counts per task type and status"`
+}
+
+type DailyProgress struct {
+	CompletedToday int64 `json:"completed_today"`
+	InProgress     int64 `json:"in_progress"`
+	ReadyToProcess int64 `json:"ready_to_process"`
+	ScheduledLater int64 `json:"scheduled_later"`
+	FailedToday    int64 `json:"failed_today"`
+}
+
+type SyncStats struct {
+	TotalEntities          int32      `json:"total_entities"`
+	ActiveEntities         int32      `json:"active_entities"`
+	StoppedEntities        int32      `json:"stopped_entities"`
+	AvgTaskDurationSeconds float64    `json:"avg_task_duration_seconds" doc:"Average duration of tasks completed today"`
+	SitemapLastSync        *time.Time `json:"sitemap_last_sync,omitempty"`
+	SitemapLastStatus      *string    `json:"sitemap_last_status,omitempty"`
+}
+
+type WorkerTaskStats struct {
+	TaskType               string  `json:"task_type"`
+	Outcome                string  `json:"outcome"`
+	Count                  uint64  `json:"count"`
+	TotalDurationSeconds   float64 `json:"total_duration_seconds"`
+	AverageDurationSeconds float64 `json:"average_duration_seconds"`
+}
+
+type HTTPHostStats struct {
+	Source           string     `json:"source"`
+	Host             string     `json:"host"`
+	Requests         uint64     `json:"requests"`
+	Errors           uint64     `json:"errors" doc:"Requests that failed without a response"`
+	RateLimited      uint64     `json:"rate_limited" doc:"Responses with status 429"`
+	Throttled        uint64     `json:"throttled" doc:"Requests that waited for a rate limit token or a concurrency slot"`
+	WaitSeconds      float64    `json:"wait_seconds" doc:"Total time requests spent throttled"`
+	RetryAfterPauses uint64     `json:"retry_after_pauses" doc:"Times the host asked to be left alone with Retry-After"`
+	InFlight         int        `json:"in_flight"`
+	PausedUntil      *time.Time `json:"paused_until,omitempty"`
+}
+
+type AdminStats struct {
+	Queues        []QueueStats      `json:"queues"`
+	Tasks         TaskStats         `json:"tasks"`
+	DailyProgress DailyProgress     `json:"daily_progress"`
+	Sync          SyncStats         `json:"sync"`
+	DLQ           DLQStats          `json:"dlq"`
+	Workers       []WorkerTaskStats `json:"workers" doc:"Tasks handled by the workers of this process since it started"`
+	HTTP          []HTTPHostStats   `json:"http" doc:"Requests the scraper clients of this process sent to each host since it started"`
+}
+
+type adminStatsOutput struct {
+	Body AdminStats
+}
+
+func (s *Server) adminStatsHandler(ctx context.Context, _ *struct{}) (*adminStatsOutput, error) {
+	queues, err := s.taskQueue.GetAllQueueMetrics(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	summary, err := s.taskQueue.GetTaskStatusSummary(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	counts, err := s.taskQueue.GetTaskCountsByTypeAndStatus(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	progress, err := s.taskQueue.GetDailyProgress(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	syncStats, err := s.taskQueue.GetSyncStatistics(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	dlqStats, err := s.taskQueue.GetDLQStats(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	dlqByTaskType, err := s.taskQueue.GetDLQStatsByTaskType(ctx)
+	if err != nil {
+		return nil, s.statsError(ctx, err)
+	}
+	successRate, _ := summary.SuccessRatePct.Float64Value()
+	out := AdminStats{
+		Queues: make([]QueueStats, 0, len(queues)),
+		Tasks: TaskStats{
+			Pending:        summary.Pending,
+			Processing:     summary.Processing,
+			Completed:      summary.Completed,
+			Failed:         summary.Failed,
+			Stopped:        summary.Stopped,
+			Total:          summary.Total,
+			SuccessRatePct: successRate.Float64,
+			ByType:         make(map[string]map[string]int64),
+		},
+		DailyProgress: DailyProgress{
+			CompletedToday: progress.CompletedToday,
+			InProgress:     progress.InProgress,
+			ReadyToProcess: progress.ReadyToProcess,
+			ScheduledLater: progress.ScheduledLater,
+			FailedToday:    progress.FailedToday,
+		},
+		Sync: SyncStats{
+			TotalEntities:          syncStats.TotalEntities.Int32,
+			ActiveEntities:         syncStats.ActiveEntities.Int32,
+			StoppedEntities:        syncStats.StoppedEntities.Int32,
+			AvgTaskDurationSeconds: syncStats.AvgTaskDurationSeconds,
+			SitemapLastSync:        taskqueue.PgTimestamptzToTime(syncStats.SitemapLastSync),
+			SitemapLastStatus:      taskqueue.PgTextToString(syncStats.SitemapLastStatus),
+		},
+		DLQ: DLQStats{
+			Total:      dlqStats.Total,
+			Pending:    dlqStats.Pending,
+			Requeued:   dlqStats.Requeued,
+			ByTaskType: make(map[string]int64, len(dlqByTaskType)),
+		},
+		Workers: []WorkerTaskStats{},
+		HTTP:    []HTTPHostStats{},
+	}
+	for _, q := range queues {
+		out.Queues = append(out.Queues, QueueStats{
+			Name:                 q.QueueName,
+			Length:               q.QueueLength,
+			OldestMessageAgeSecs: q.OldestMsgAgeSec,
+			NewestMessageAgeSecs: q.NewestMsgAgeSec,
+			TotalMessages:        q.TotalMessages,
+		})
+	}
+	for _, row := range counts {
+		if out.Tasks.ByType[row.TaskType] == nil {
+			out.Tasks.ByType[row.TaskType] = make(map[string]int64)
+		}
+		out.Tasks.ByType[row.TaskType][row.Status] = row.Count
+	}
+	for _, row := range dlqByTaskType {
+		out.DLQ.ByTaskType[row.TaskType] = row.Count
+	}
+	for _, w := range taskqueue.GetWorkerTaskStats() {
+		out.Workers = append(out.Workers, WorkerTaskStats{
+			TaskType:               w.TaskType,
+			Outcome:                w.Outcome,
+			Count:                  w.Count,
+			TotalDurationSeconds:   w.TotalDuration.Seconds(),
+			AverageDurationSeconds: w.AverageDuration.Seconds(),
+		})
+	}
+	for _, h := range s.limiters.stats() {
+		out.HTTP = append(out.HTTP, HTTPHostStats{
+			Source:           h.Source,
+			Host:             h.Host,
+			Requests:         h.Requests,
+			Errors:           h.Errors,
+			RateLimited:      h.RateLimited,
+			Throttled:        h.Throttled,
+			WaitSeconds:      h.WaitTime.Seconds(),
+			RetryAfterPauses: h.RetryAfterPauses,
+			InFlight:         h.InFlight,
+			PausedUntil:      h.PausedUntil,
+		})
+	}
+	return &adminStatsOutput{Body: out}, nil
+}
+
+func (s *Server) statsError(ctx context.Context, err error) error {
+	s.logger.ErrorContext(ctx, "failed to collect stats", "error", err)
+	return huma.Error500InternalServerError("failed to collect stats")
+}
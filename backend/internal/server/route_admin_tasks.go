@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+type Task struct {
+	ID             int64      `json:"id"`
+	EntityID       string     `json:"entity_id"`
+	TaskType       string     `json:"task_type"`
+	Status         string     `json:"status"`
+	Priority       int64      `json:"priority"`
+	Attempt        int64      `json:"attempt"`
+	MaxAttempts    int64      `json:"max_attempts"`
+	LastError      *string    `json:"last_error,omitempty"`
+	WorkerID       *string    `json:"worker_id,omitempty"`
+	ScheduledFor   *time.Time `json:"scheduled_for,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	RunOn          *time.Time `json:"run_on,omitempty"`
+	QueueMessageID *int64     `json:"queue_message_id,omitempty"`
+	QueueName      *string    `json:"queue_name,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+type RunTaskRequest struct {
+	EntityID    string `json:"entity_id" minLength:"1" doc:"Entity to sync, e.g. ad:123456"`
+	TaskType    string `json:"task_type" minLength:"1" doc:"Task type of a registered source, e.g. frontdoor_sync"`
+	MaxAttempts int    `json:"max_attempts,omitempty" minimum:"1" maximum:"10" default:"3"`
+}
+
+type listTasksInput struct {
+	Status   string `query:"status" enum:"pending,processing,completed,failed,stopped"`
+	TaskType string `query:"task_type"`
+	EntityID string `query:"entity_id"`
+	Limit    int    `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset   int    `query:"offset" minimum:"0"`
+}
+
+type listTasksOutput struct {
+	Body []Task
+}
+
+type taskIDInput struct {
+	ID int64 `path:"id"`
+}
+
+type taskOutput struct {
+	Body Task
+}
+
+type runTaskInput struct {
+	Body RunTaskRequest
+}
+
+func (s *Server) listTasksHandler(ctx context.Context, input *listTasksInput) (*listTasksOutput, error) {
+	filter := taskqueue.TaskFilter{
+		Status:   input.Status,
+		TaskType: input.TaskType,
+		EntityID: input.EntityID,
+	}
+	tasks, err := s.taskQueue.ListTasks(ctx, filter, input.Limit, input.Offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list tasks", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list tasks")
+	}
+	out := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		out = append(out, mapTask(task))
+	}
+	return &listTasksOutput{Body: out}, nil
+}
+
+func (s *Server) getTaskHandler(ctx context.Context, input *taskIDInput) (*taskOutput, error) {
+	task, err := s.taskQueue.GetTask(ctx, input.ID)
+	if err != nil {
+		return nil, s.taskError(ctx, input.ID, err)
+	}
+	return &taskOutput{Body: mapTask(*task)}, nil
+}
+
+func (s *Server) cancelTaskHandler(ctx context.Context, input *taskIDInput) (*taskOutput, error) {
+	if err := s.taskQueue.CancelTask(ctx, input.ID); err != nil {
+		return nil, s.taskError(ctx, input.ID, err)
+	}
+	s.logger.InfoContext(ctx, "task cancelled", "task_id", input.ID)
+	return s.getTaskHandler(ctx, &taskIDInput{ID: input.ID})
+}
+
+func (s *Server) runTaskHandler(ctx context.Context, input *runTaskInput) (*taskOutput, error) {
+	if _, _, ok := s.registry.Lookup(input.Body.TaskType); !ok {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("unknown task type %s", input.Body.TaskType))
+	}
+	taskID, err := s.taskQueue.RunTaskNow(ctx, input.Body.EntityID, input.Body.TaskType, input.Body.MaxAttempts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to run task", "entity_id", input.Body.EntityID, "task_type", input.Body.TaskType, "error", err)
+		return nil, huma.Error500InternalServerError("failed to run task")
+	}
+	s.logger.InfoContext(ctx, "task force-run enqueued", "task_id", taskID, "entity_id", input.Body.EntityID, "task_type", input.Body.TaskType)
+	return s.getTaskHandler(ctx, &taskIDInput{ID: taskID})
+}
+
+func (s *Server) taskError(ctx context.Context, taskID int64, err error) error {
+	switch {
+	case errors.Is(err, taskqueue.ErrTaskNotFound):
+		return huma.Error404NotFound(fmt.Sprintf("task %d not found", taskID))
+	case errors.Is(err, taskqueue.ErrTaskNotCancelable):
+		return huma.Error409Conflict(fmt.Sprintf("task %d is not pending", taskID))
+	}
+	s.logger.ErrorContext(ctx, "task operation failed", "task_id", taskID, "error", err)
+	return huma.Error500InternalServerError("task operation failed")
+}
+
+func mapTask(t taskqueuedb.TaskQueueTask) Task {
+	return Task{
+		ID:             t.TaskID,
+		EntityID:       t.EntityID,
+		TaskType:       t.TaskType,
+		Status:         t.Status,
+		Priority:       t.Priority,
+		Attempt:        t.Attempt,
+		MaxAttempts:    t.MaxAttempts,
+		LastError:      taskqueue.PgTextToString(t.LastError),
+		WorkerID:       taskqueue.PgTextToString(t.WorkerID),
+		ScheduledFor:   taskqueue.PgTimestamptzToTime(t.ScheduledFor),
+		StartedAt:      taskqueue.PgTimestamptzToTime(t.StartedAt),
+		CompletedAt:    taskqueue.PgTimestamptzToTime(t.CompletedAt),
+		RunOn:          taskqueue.PgDateToTime(t.RunOn),
+		QueueMessageID: taskqueue.PgInt8ToInt64(t.QueueMessageID),
+		QueueName:      taskqueue.PgTextToString(t.QueueName),
+		CreatedAt:      taskqueue.PgTimestamptzToTime(t.CreatedAt),
+		UpdatedAt:      taskqueue.PgTimestamptzToTime(t.UpdatedAt),
+	}
+}
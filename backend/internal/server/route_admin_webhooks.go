@@ -0,0 +1,305 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"koditon-go/internal/util"
+	"koditon-go/internal/webhooks"
+	webhooksdb "koditon-go/internal/webhooks/db"
+)
+
+type WebhookSubscription struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty" doc:"Signing secret, only returned when the subscription is created"`
+	EventTypes  []string  `json:"event_types" doc:"Empty when subscribed to all events"`
+	Description *string   `json:"description,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookSubscriptionRequest struct {
+	URL         string   `json:"url" maxLength:"2000" doc:"http or https URL that resolves to a public address"`
+	EventTypes  []string `json:"event_types,omitempty" enum:"ad.created,ad.price_changed,building.synced,task.dead_lettered" doc:"Events to deliver, all when empty"`
+	Description string   `json:"description,omitempty" maxLength:"1000"`
+	Enabled     *bool    `json:"enabled,omitempty" doc:"Defaults to true"`
+}
+
+type WebhookDelivery struct {
+	ID             string                   `json:"id"`
+	SubscriptionID string                   `json:"subscription_id"`
+	EventID        string                   `json:"event_id"`
+	EventType      string                   `json:"event_type"`
+	Payload        json.RawMessage          `json:"payload" doc:"The signed body posted to the subscriber"`
+	Status         string                   `json:"status" enum:"pending,delivered,failed"`
+	Attempts       int32                    `json:"attempts"`
+	ResponseStatus *int32                   `json:"response_status,omitempty" doc:"HTTP status of the last response"`
+	LastError      *string                  `json:"last_error,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	DeliveredAt    *time.Time               `json:"delivered_at,omitempty"`
+	AttemptLog     []WebhookDeliveryAttempt `json:"attempt_log,omitempty" doc:"Only included when getting a single delivery"`
+}
+
+type WebhookDeliveryAttempt struct {
+	Attempt        int32     `json:"attempt"`
+	ResponseStatus *int32    `json:"response_status,omitempty"`
+	Error          *string   `json:"error,omitempty"`
+	DurationMs     int32     `json:"duration_ms"`
+	AttemptedAt    time.Time `json:"attempted_at"`
+}
+
+type listWebhookSubscriptionsInput struct {
+	Limit  int32 `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset int32 `query:"offset" minimum:"0"`
+}
+
+type listWebhookSubscriptionsOutput struct {
+	Body []WebhookSubscription
+}
+
+type webhookSubscriptionIDInput struct {
+	ID string `path:"id" format:"uuid"`
+}
+
+type createWebhookSubscriptionInput struct {
+	Body WebhookSubscriptionRequest
+}
+
+type updateWebhookSubscriptionInput struct {
+	ID   string `path:"id" format:"uuid"`
+	Body WebhookSubscriptionRequest
+}
+
+type webhookSubscriptionOutput struct {
+	Body WebhookSubscription
+}
+
+type listWebhookDeliveriesInput struct {
+	ID        string `path:"id" format:"uuid"`
+	Status    string `query:"status" enum:"pending,delivered,failed"`
+	EventType string `query:"event_type" enum:"ad.created,ad.price_changed,building.synced,task.dead_lettered"`
+	Limit     int32  `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset    int32  `query:"offset" minimum:"0"`
+}
+
+type listWebhookDeliveriesOutput struct {
+	Body []WebhookDelivery
+}
+
+type webhookDeliveryIDInput struct {
+	ID string `path:"id" format:"uuid"`
+}
+
+type webhookDeliveryOutput struct {
+	Body WebhookDelivery
+}
+
+func (s *Server) listWebhookSubscriptionsHandler(ctx context.Context, input *listWebhookSubscriptionsInput) (*listWebhookSubscriptionsOutput, error) {
+	subscriptions, err := s.webhooksQueries.ListWebhookSubscriptions(ctx, &webhooksdb.ListWebhookSubscriptionsParams{
+		PageSize:   input.Limit,
+		PageOffset: input.Offset,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list webhook subscriptions", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list webhook subscriptions")
+	}
+	out := make([]WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		out = append(out, mapWebhookSubscription(subscription))
+	}
+	return &listWebhookSubscriptionsOutput{Body: out}, nil
+}
+
+func (s *Server) createWebhookSubscriptionHandler(ctx context.Context, input *createWebhookSubscriptionInput) (*webhookSubscriptionOutput, error) {
+	req := input.Body
+	if err := webhooks.ValidateURL(ctx, req.URL); err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to generate webhook secret", "error", err)
+		return nil, huma.Error500InternalServerError("failed to create webhook subscription")
+	}
+	subscription, err := s.webhooksQueries.CreateWebhookSubscription(ctx, &webhooksdb.CreateWebhookSubscriptionParams{
+		WebhookSubscriptionsUrl:         req.URL,
+		WebhookSubscriptionsSecret:      secret,
+		WebhookSubscriptionsEventTypes:  util.UniqueStrings(req.EventTypes),
+		WebhookSubscriptionsDescription: util.ToStringPtr(util.NormalizeString(req.Description)),
+		WebhookSubscriptionsEnabled:     req.Enabled == nil || *req.Enabled,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to create webhook subscription", "error", err)
+		return nil, huma.Error500InternalServerError("failed to create webhook subscription")
+	}
+	s.logger.InfoContext(ctx, "webhook subscription created", "subscription_id", util.FromUUID(subscription.WebhookSubscriptionsID))
+	out := mapWebhookSubscription(subscription)
+	out.Secret = subscription.WebhookSubscriptionsSecret
+	return &webhookSubscriptionOutput{Body: out}, nil
+}
+
+func (s *Server) getWebhookSubscriptionHandler(ctx context.Context, input *webhookSubscriptionIDInput) (*webhookSubscriptionOutput, error) {
+	id, err := parseWebhookID("webhook subscription", input.ID)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := s.webhooksQueries.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, s.webhookSubscriptionError(ctx, input.ID, err)
+	}
+	return &webhookSubscriptionOutput{Body: mapWebhookSubscription(subscription)}, nil
+}
+
+func (s *Server) updateWebhookSubscriptionHandler(ctx context.Context, input *updateWebhookSubscriptionInput) (*webhookSubscriptionOutput, error) {
+	id, err := parseWebhookID("webhook subscription", input.ID)
+	if err != nil {
+		return nil, err
+	}
+	req := input.Body
+	if err := webhooks.ValidateURL(ctx, req.URL); err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	subscription, err := s.webhooksQueries.UpdateWebhookSubscription(ctx, &webhooksdb.UpdateWebhookSubscriptionParams{
+		WebhookSubscriptionsID:          id,
+		WebhookSubscriptionsUrl:         req.URL,
+		WebhookSubscriptionsEventTypes:  util.UniqueStrings(req.EventTypes),
+		WebhookSubscriptionsDescription: util.ToStringPtr(util.NormalizeString(req.Description)),
+		WebhookSubscriptionsEnabled:     req.Enabled == nil || *req.Enabled,
+	})
+	if err != nil {
+		return nil, s.webhookSubscriptionError(ctx, input.ID, err)
+	}
+	s.logger.InfoContext(ctx, "webhook subscription updated", "subscription_id", input.ID)
+	return &webhookSubscriptionOutput{Body: mapWebhookSubscription(subscription)}, nil
+}
+
+func (s *Server) deleteWebhookSubscriptionHandler(ctx context.Context, input *webhookSubscriptionIDInput) (*struct{}, error) {
+	id, err := parseWebhookID("webhook subscription", input.ID)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.webhooksQueries.DeleteWebhookSubscription(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete webhook subscription", "subscription_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to delete webhook subscription")
+	}
+	if deleted == 0 {
+		return nil, huma.Error404NotFound(fmt.Sprintf("webhook subscription %s not found", input.ID))
+	}
+	s.logger.InfoContext(ctx, "webhook subscription deleted", "subscription_id", input.ID)
+	return nil, nil
+}
+
+func (s *Server) listWebhookDeliveriesHandler(ctx context.Context, input *listWebhookDeliveriesInput) (*listWebhookDeliveriesOutput, error) {
+	id, err := parseWebhookID("webhook subscription", input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.webhooksQueries.GetWebhookSubscription(ctx, id); err != nil {
+		return nil, s.webhookSubscriptionError(ctx, input.ID, err)
+	}
+	deliveries, err := s.webhooksQueries.ListWebhookDeliveries(ctx, &webhooksdb.ListWebhookDeliveriesParams{
+		SubscriptionID: id,
+		Status:         util.ToStringPtr(input.Status),
+		EventType:      util.ToStringPtr(input.EventType),
+		PageSize:       input.Limit,
+		PageOffset:     input.Offset,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list webhook deliveries", "subscription_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to list webhook deliveries")
+	}
+	out := make([]WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		out = append(out, mapWebhookDelivery(delivery))
+	}
+	return &listWebhookDeliveriesOutput{Body: out}, nil
+}
+
+func (s *Server) getWebhookDeliveryHandler(ctx context.Context, input *webhookDeliveryIDInput) (*webhookDeliveryOutput, error) {
+	id, err := parseWebhookID("webhook delivery", input.ID)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhooksQueries.GetWebhookDelivery(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("webhook delivery %s not found", input.ID))
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get webhook delivery", "delivery_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to get webhook delivery")
+	}
+	attempts, err := s.webhooksQueries.ListWebhookDeliveryAttempts(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list webhook delivery attempts", "delivery_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to get webhook delivery")
+	}
+	out := mapWebhookDelivery(delivery)
+	out.AttemptLog = make([]WebhookDeliveryAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		out.AttemptLog = append(out.AttemptLog, WebhookDeliveryAttempt{
+			Attempt:        attempt.WebhookDeliveryAttemptsAttempt,
+			ResponseStatus: attempt.WebhookDeliveryAttemptsResponseStatus,
+			Error:          attempt.WebhookDeliveryAttemptsError,
+			DurationMs:     attempt.WebhookDeliveryAttemptsDurationMs,
+			AttemptedAt:    attempt.WebhookDeliveryAttemptsAttemptedAt,
+		})
+	}
+	return &webhookDeliveryOutput{Body: out}, nil
+}
+
+func (s *Server) webhookSubscriptionError(ctx context.Context, id string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return huma.Error404NotFound(fmt.Sprintf("webhook subscription %s not found", id))
+	}
+	s.logger.ErrorContext(ctx, "webhook subscription query failed", "subscription_id", id, "error", err)
+	return huma.Error500InternalServerError("failed to get webhook subscription")
+}
+
+func parseWebhookID(kind, raw string) (pgtype.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return pgtype.UUID{}, huma.Error400BadRequest(fmt.Sprintf("invalid %s id: %s", kind, raw))
+	}
+	return util.ToUUID(id), nil
+}
+
+func mapWebhookSubscription(subscription webhooksdb.WebhookSubscription) WebhookSubscription {
+	return WebhookSubscription{
+		ID:          util.FromUUID(subscription.WebhookSubscriptionsID),
+		URL:         subscription.WebhookSubscriptionsUrl,
+		EventTypes:  subscription.WebhookSubscriptionsEventTypes,
+		Description: subscription.WebhookSubscriptionsDescription,
+		Enabled:     subscription.WebhookSubscriptionsEnabled,
+		CreatedAt:   subscription.WebhookSubscriptionsCreatedAt,
+		UpdatedAt:   subscription.WebhookSubscriptionsUpdatedAt,
+	}
+}
+
+func mapWebhookDelivery(delivery webhooksdb.WebhookDelivery) WebhookDelivery {
+	out := WebhookDelivery{
+		ID:             util.FromUUID(delivery.WebhookDeliveriesID),
+		SubscriptionID: util.FromUUID(delivery.WebhookDeliveriesSubscriptionID),
+		EventID:        util.FromUUID(delivery.WebhookDeliveriesEventID),
+		EventType:      delivery.WebhookDeliveriesEventType,
+		Payload:        delivery.WebhookDeliveriesPayload,
+		Status:         delivery.WebhookDeliveriesStatus,
+		Attempts:       delivery.WebhookDeliveriesAttempts,
+		ResponseStatus: delivery.WebhookDeliveriesResponseStatus,
+		LastError:      delivery.WebhookDeliveriesLastError,
+		CreatedAt:      delivery.WebhookDeliveriesCreatedAt,
+	}
+	if delivery.WebhookDeliveriesDeliveredAt.Valid {
+		out.DeliveredAt = &delivery.WebhookDeliveriesDeliveredAt.Time
+	}
+	return out
+}
@@ -0,0 +1,363 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	frontdoordb "koditon-go/internal/frontdoor/db"
+	shortcutdb "koditon-go/internal/shortcut/db"
+	"koditon-go/internal/util"
+)
+
+const (
+	buildingSourceFrontdoor = "frontdoor"
+	buildingSourceShortcut  = "shortcut"
+)
+
+// BuildingFact is a building attribute together with the source it was taken
+// from. Frontdoor is preferred when both sources have a value.
+type BuildingFact[T any] struct {
+	Value  T      `json:"value"`
+	Source string `json:"source" enum:"frontdoor,shortcut"`
+}
+
+type BuildingRenovation struct {
+	Kind   string `json:"kind" enum:"elevator,facade,window,roof,pipe,balcony,electricity"`
+	Year   *int32 `json:"year,omitempty"`
+	Source string `json:"source" enum:"frontdoor,shortcut"`
+}
+
+type BuildingFacts struct {
+	BuildYear   *BuildingFact[int32]  `json:"build_year,omitempty"`
+	Floors      *BuildingFact[int32]  `json:"floors,omitempty"`
+	Elevator    *BuildingFact[bool]   `json:"elevator,omitempty"`
+	Sauna       *BuildingFact[bool]   `json:"sauna,omitempty"`
+	Heating     *BuildingFact[string] `json:"heating,omitempty"`
+	EnergyClass *BuildingFact[string] `json:"energy_class,omitempty"`
+	Renovations []BuildingRenovation  `json:"renovations"`
+}
+
+type BuildingListing struct {
+	ID            string    `json:"id" format:"uuid"`
+	Layout        *string   `json:"layout,omitempty"`
+	Size          *float64  `json:"size,omitempty"`
+	Price         *float64  `json:"price,omitempty"`
+	PricePerSqm   *float64  `json:"price_per_sqm,omitempty"`
+	MarketingTime *string   `json:"marketing_time,omitempty"`
+	FirstSeenAt   time.Time `json:"first_seen_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+}
+
+type BuildingAnnouncement struct {
+	ID             string     `json:"id" format:"uuid"`
+	FriendlyID     *string    `json:"friendly_id,omitempty"`
+	Address        *string    `json:"address,omitempty"`
+	PropertyType   *string    `json:"property_type,omitempty"`
+	RoomStructure  *string    `json:"room_structure,omitempty"`
+	Area           *float64   `json:"area,omitempty"`
+	SearchPrice    *float64   `json:"search_price,omitempty"`
+	PricePerSquare *float64   `json:"price_per_square,omitempty"`
+	Published      bool       `json:"published"`
+	FirstSeenAt    time.Time  `json:"first_seen_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	UnpublishedOn  *time.Time `json:"unpublished_on,omitempty" format:"date"`
+}
+
+type Building struct {
+	ID                  string                 `json:"id" format:"uuid"`
+	FrontdoorBuildingID *string                `json:"frontdoor_building_id,omitempty" format:"uuid"`
+	ShortcutBuildingID  *string                `json:"shortcut_building_id,omitempty" format:"uuid"`
+	VrkID               *string                `json:"vrk_id,omitempty" doc:"Permanent building identifier of the population register"`
+	Address             *string                `json:"address,omitempty"`
+	Postcode            *string                `json:"postcode,omitempty"`
+	Municipality        *string                `json:"municipality,omitempty"`
+	Latitude            *float64               `json:"latitude,omitempty"`
+	Longitude           *float64               `json:"longitude,omitempty"`
+	Facts               BuildingFacts          `json:"facts"`
+	Listings            []BuildingListing      `json:"listings" doc:"Current shortcut sale listings"`
+	Rentals             []BuildingListing      `json:"rentals" doc:"Current shortcut rental listings"`
+	Announcements       []BuildingAnnouncement `json:"announcements" doc:"Frontdoor announcements, including unpublished ones"`
+}
+
+type getBuildingInput struct {
+	ID string `path:"id" format:"uuid" doc:"Frontdoor or shortcut building ID"`
+}
+
+type getBuildingOutput struct {
+	Body Building
+}
+
+func (s *Server) getBuildingHandler(ctx context.Context, input *getBuildingInput) (*getBuildingOutput, error) {
+	id, err := uuid.Parse(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid building id: %s", input.ID))
+	}
+	frontdoor, shortcut, err := s.resolveBuilding(ctx, util.ToUUID(id))
+	if err != nil {
+		return nil, s.buildingError(ctx, err)
+	}
+	if frontdoor == nil && shortcut == nil {
+		return nil, huma.Error404NotFound(fmt.Sprintf("building %s not found", input.ID))
+	}
+	out := mergeBuilding(input.ID, frontdoor, shortcut)
+	if frontdoor != nil {
+		announcements, err := s.frontdoorQueries.ListFrontdoorBuildingAnnouncements(ctx, frontdoor.FrontdoorBuildingsID)
+		if err != nil {
+			return nil, s.buildingError(ctx, err)
+		}
+		for _, a := range announcements {
+			out.Announcements = append(out.Announcements, mapBuildingAnnouncement(a))
+		}
+	}
+	if shortcut != nil {
+		listings, err := s.shortcutQueries.GetShortcutBuildingListingsByBuildingID(ctx, shortcut.ShortcutBuildingsID)
+		if err != nil {
+			return nil, s.buildingError(ctx, err)
+		}
+		for _, l := range listings {
+			if l.ShortcutBuildingListingsDeletedAt.Valid {
+				continue
+			}
+			out.Listings = append(out.Listings, BuildingListing{
+				ID:            util.FromUUID(l.ShortcutBuildingListingsID),
+				Layout:        l.ShortcutBuildingListingsLayout,
+				Size:          l.ShortcutBuildingListingsSize,
+				Price:         l.ShortcutBuildingListingsPrice,
+				PricePerSqm:   l.ShortcutBuildingListingsPricePerSqm,
+				MarketingTime: l.ShortcutBuildingListingsMarketingTime,
+				FirstSeenAt:   l.ShortcutBuildingListingsCreatedAt.Time,
+				LastSeenAt:    l.ShortcutBuildingListingsUpdatedAt.Time,
+			})
+		}
+		rentals, err := s.shortcutQueries.GetShortcutBuildingRentalsByBuildingID(ctx, shortcut.ShortcutBuildingsID)
+		if err != nil {
+			return nil, s.buildingError(ctx, err)
+		}
+		for _, r := range rentals {
+			if r.ShortcutBuildingRentalsDeletedAt.Valid {
+				continue
+			}
+			out.Rentals = append(out.Rentals, BuildingListing{
+				ID:            util.FromUUID(r.ShortcutBuildingRentalsID),
+				Layout:        r.ShortcutBuildingRentalsLayout,
+				Size:          r.ShortcutBuildingRentalsSize,
+				Price:         r.ShortcutBuildingRentalsPrice,
+				MarketingTime: r.ShortcutBuildingRentalsMarketingTime,
+				FirstSeenAt:   r.ShortcutBuildingRentalsCreatedAt.Time,
+				LastSeenAt:    r.ShortcutBuildingRentalsUpdatedAt.Time,
+			})
+		}
+	}
+	return &getBuildingOutput{Body: out}, nil
+}
+
+// resolveBuilding looks the ID up in both building tables and pairs the hit
+// with the building of the other source it is linked to in building_links.
+// Either result may be nil.
+func (s *Server) resolveBuilding(ctx context.Context, id pgtype.UUID) (*frontdoordb.FrontdoorBuilding, *shortcutdb.ShortcutBuilding, error) {
+	frontdoor, err := s.getFrontdoorBuilding(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if frontdoor != nil {
+		matchID, err := s.buildingsQueries.GetLinkedShortcutBuildingID(ctx, frontdoor.FrontdoorBuildingsID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, fmt.Errorf("failed to get linked shortcut building: %w", err)
+		}
+		shortcut, err := s.getShortcutBuilding(ctx, matchID)
+		return frontdoor, shortcut, err
+	}
+	shortcut, err := s.getShortcutBuilding(ctx, id)
+	if err != nil || shortcut == nil {
+		return nil, shortcut, err
+	}
+	matchID, err := s.buildingsQueries.GetLinkedFrontdoorBuildingID(ctx, shortcut.ShortcutBuildingsID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("failed to get linked frontdoor building: %w", err)
+	}
+	frontdoor, err = s.getFrontdoorBuilding(ctx, matchID)
+	return frontdoor, shortcut, err
+}
+
+func (s *Server) getFrontdoorBuilding(ctx context.Context, id pgtype.UUID) (*frontdoordb.FrontdoorBuilding, error) {
+	if !id.Valid {
+		return nil, nil
+	}
+	building, err := s.frontdoorQueries.GetFrontdoorBuildingByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frontdoor building: %w", err)
+	}
+	return &building, nil
+}
+
+func (s *Server) getShortcutBuilding(ctx context.Context, id pgtype.UUID) (*shortcutdb.ShortcutBuilding, error) {
+	if !id.Valid {
+		return nil, nil
+	}
+	building, err := s.shortcutQueries.GetShortcutBuildingByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shortcut building: %w", err)
+	}
+	return &building, nil
+}
+
+func (s *Server) buildingError(ctx context.Context, err error) error {
+	s.logger.ErrorContext(ctx, "failed to get building", "error", err)
+	return huma.Error500InternalServerError("failed to get building")
+}
+
+func mergeBuilding(id string, frontdoor *frontdoordb.FrontdoorBuilding, shortcut *shortcutdb.ShortcutBuilding) Building {
+	out := Building{
+		ID:            id,
+		Facts:         BuildingFacts{Renovations: []BuildingRenovation{}},
+		Listings:      []BuildingListing{},
+		Rentals:       []BuildingListing{},
+		Announcements: []BuildingAnnouncement{},
+	}
+	var fd, sc BuildingFacts
+	if shortcut != nil {
+		out.ShortcutBuildingID = util.ToStringPtr(util.FromUUID(shortcut.ShortcutBuildingsID))
+		out.VrkID = shortcut.ShortcutBuildingsVrkID
+		out.Address = shortcut.ShortcutBuildingsAddress
+		out.Latitude = shortcut.ShortcutBuildingsLatitude
+		out.Longitude = shortcut.ShortcutBuildingsLongitude
+		sc = BuildingFacts{
+			BuildYear: newBuildingFact(buildingSourceShortcut, shortcut.ShortcutBuildingsConstructionYear),
+			Floors:    newBuildingFact(buildingSourceShortcut, shortcut.ShortcutBuildingsFloorCount),
+			Elevator:  newBuildingFact(buildingSourceShortcut, parseShortcutYesNo(shortcut.ShortcutBuildingsHasElevator)),
+			Sauna:     newBuildingFact(buildingSourceShortcut, parseShortcutYesNo(shortcut.ShortcutBuildingsHasSauna)),
+			Heating:   newBuildingFact(buildingSourceShortcut, shortcut.ShortcutBuildingsHeatingSystem),
+		}
+	}
+	if frontdoor != nil {
+		out.FrontdoorBuildingID = util.ToStringPtr(util.FromUUID(frontdoor.FrontdoorBuildingsID))
+		if address := frontdoorStreetAddress(frontdoor); address != nil {
+			out.Address = address
+		}
+		out.Postcode = frontdoor.FrontdoorBuildingsPostcode
+		out.Municipality = frontdoor.FrontdoorBuildingsMunicipality
+		if frontdoor.FrontdoorBuildingsLatitude != nil && frontdoor.FrontdoorBuildingsLongitude != nil {
+			out.Latitude = frontdoor.FrontdoorBuildingsLatitude
+			out.Longitude = frontdoor.FrontdoorBuildingsLongitude
+		}
+		fd = BuildingFacts{
+			BuildYear: firstBuildingFact(
+				newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsBuildYear),
+				newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsConstructionEndYear),
+			),
+			Floors:      newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsFloorCount),
+			Elevator:    newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsHasElevator),
+			Sauna:       newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsHasSauna),
+			Heating:     newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsHeating),
+			EnergyClass: newBuildingFact(buildingSourceFrontdoor, frontdoor.FrontdoorBuildingsEnergyCertificateCode),
+		}
+		out.Facts.Renovations = frontdoorRenovations(frontdoor)
+	}
+	out.Facts.BuildYear = firstBuildingFact(fd.BuildYear, sc.BuildYear)
+	out.Facts.Floors = firstBuildingFact(fd.Floors, sc.Floors)
+	out.Facts.Elevator = firstBuildingFact(fd.Elevator, sc.Elevator)
+	out.Facts.Sauna = firstBuildingFact(fd.Sauna, sc.Sauna)
+	out.Facts.Heating = firstBuildingFact(fd.Heating, sc.Heating)
+	out.Facts.EnergyClass = firstBuildingFact(fd.EnergyClass, sc.EnergyClass)
+	return out
+}
+
+func newBuildingFact[T any](source string, value *T) *BuildingFact[T] {
+	if value == nil {
+		return nil
+	}
+	return &BuildingFact[T]{Value: *value, Source: source}
+}
+
+func firstBuildingFact[T any](facts ...*BuildingFact[T]) *BuildingFact[T] {
+	for _, f := range facts {
+		if f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// parseShortcutYesNo reads the Finnish yes/no values of the scraped building
+// page. Anything else is treated as unknown.
+func parseShortcutYesNo(value *string) *bool {
+	if value == nil {
+		return nil
+	}
+	var b bool
+	switch strings.ToLower(strings.TrimSpace(*value)) {
+	case "kyllä", "on":
+		b = true
+	case "ei":
+		b = false
+	default:
+		return nil
+	}
+	return &b
+}
+
+func frontdoorStreetAddress(b *frontdoordb.FrontdoorBuilding) *string {
+	if b.FrontdoorBuildingsStreetAddress == nil {
+		return nil
+	}
+	address := *b.FrontdoorBuildingsStreetAddress
+	if b.FrontdoorBuildingsHouseNumber != nil && *b.FrontdoorBuildingsHouseNumber != "" {
+		address += " " + *b.FrontdoorBuildingsHouseNumber
+	}
+	return &address
+}
+
+func frontdoorRenovations(b *frontdoordb.FrontdoorBuilding) []BuildingRenovation {
+	candidates := []struct {
+		kind      string
+		renovated *bool
+		year      *int32
+	}{
+		{"elevator", b.FrontdoorBuildingsElevatorRenovated, b.FrontdoorBuildingsElevatorRenovatedYear},
+		{"facade", b.FrontdoorBuildingsFacadeRenovated, b.FrontdoorBuildingsFacadeRenovatedYear},
+		{"window", b.FrontdoorBuildingsWindowRenovated, b.FrontdoorBuildingsWindowRenovatedYear},
+		{"roof", b.FrontdoorBuildingsRoofRenovated, b.FrontdoorBuildingsRoofRenovatedYear},
+		{"pipe", b.FrontdoorBuildingsPipeRenovated, b.FrontdoorBuildingsPipeRenovatedYear},
+		{"balcony", b.FrontdoorBuildingsBalconyRenovated, b.FrontdoorBuildingsBalconyRenovatedYear},
+		{"electricity", b.FrontdoorBuildingsElectricityRenovated, b.FrontdoorBuildingsElectricityRenovatedYear},
+	}
+	renovations := []BuildingRenovation{}
+	for _, c := range candidates {
+		if (c.renovated == nil || !*c.renovated) && c.year == nil {
+			continue
+		}
+		renovations = append(renovations, BuildingRenovation{Kind: c.kind, Year: c.year, Source: buildingSourceFrontdoor})
+	}
+	return renovations
+}
+
+func mapBuildingAnnouncement(a frontdoordb.FrontdoorBuildingAnnouncement) BuildingAnnouncement {
+	return BuildingAnnouncement{
+		ID:             util.FromUUID(a.FrontdoorBuildingAnnouncementsID),
+		FriendlyID:     a.FrontdoorBuildingAnnouncementsFriendlyID,
+		Address:        a.FrontdoorBuildingAnnouncementsAddressLine1,
+		PropertyType:   a.FrontdoorBuildingAnnouncementsPropertyType,
+		RoomStructure:  a.FrontdoorBuildingAnnouncementsRoomStructure,
+		Area:           a.FrontdoorBuildingAnnouncementsArea,
+		SearchPrice:    a.FrontdoorBuildingAnnouncementsSearchPrice,
+		PricePerSquare: a.FrontdoorBuildingAnnouncementsPricePerSquare,
+		Published:      a.FrontdoorBuildingAnnouncementsPublished != nil && *a.FrontdoorBuildingAnnouncementsPublished,
+		FirstSeenAt:    a.FrontdoorBuildingAnnouncementsFirstSeenAt.Time,
+		LastSeenAt:     a.FrontdoorBuildingAnnouncementsLastSeenAt.Time,
+		UnpublishedOn:  a.FrontdoorBuildingAnnouncementsUnpublishingTimeDate,
+	}
+}
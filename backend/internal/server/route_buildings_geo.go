@@ -0,0 +1,270 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	buildingsdb "koditon-go/internal/buildings/db"
+	"koditon-go/internal/util"
+)
+
+type GeoJSONPoint struct {
+	Type        string    `json:"type" enum:"Point"`
+	Coordinates []float64 `json:"coordinates" doc:"Longitude and latitude"`
+}
+
+type GeoJSONPolygon struct {
+	Type        string        `json:"type" enum:"Polygon"`
+	Coordinates [][][]float64 `json:"coordinates" doc:"Linear rings of longitude and latitude positions, the first one being the exterior ring"`
+}
+
+type BuildingFeatureProperties struct {
+	Source     *string `json:"source,omitempty" enum:"frontdoor,shortcut"`
+	Address    *string `json:"address,omitempty"`
+	BuildYear  *int32  `json:"build_year,omitempty"`
+	Cluster    bool    `json:"cluster" doc:"Whether the feature stands for several buildings"`
+	PointCount int64   `json:"point_count" doc:"Number of buildings the feature stands for"`
+}
+
+type BuildingFeature struct {
+	Type       string                    `json:"type" enum:"Feature"`
+	ID         string                    `json:"id,omitempty" doc:"Building ID, empty for clusters"`
+	Geometry   GeoJSONPoint              `json:"geometry"`
+	Properties BuildingFeatureProperties `json:"properties"`
+}
+
+type BuildingFeatureCollection struct {
+	Type      string            `json:"type" enum:"FeatureCollection"`
+	Features  []BuildingFeature `json:"features"`
+	Truncated bool              `json:"truncated" doc:"Whether more buildings matched than the limit allowed"`
+}
+
+type searchBuildingsInput struct {
+	BBox      []float64 `query:"bbox" doc:"Bounding box as min_longitude,min_latitude,max_longitude,max_latitude"`
+	Latitude  float64   `query:"lat" minimum:"-90" maximum:"90" doc:"Latitude of the radius search center"`
+	Longitude float64   `query:"lon" minimum:"-180" maximum:"180" doc:"Longitude of the radius search center"`
+	Radius    float64   `query:"radius" minimum:"0" maximum:"50000" doc:"Radius in metres around lat and lon"`
+	Cluster   bool      `query:"cluster" doc:"Group nearby buildings into clusters, at most 50000 buildings are grouped"`
+	Zoom      int32     `query:"zoom" minimum:"0" maximum:"22" default:"12" doc:"Map zoom level the clusters are sized for"`
+	Limit     int32     `query:"limit" minimum:"1" maximum:"10000" default:"2000" doc:"Maximum number of buildings, ignored when clustering"`
+
+	hasCenter bool
+}
+
+// Resolve records whether the radius search center was given, the zero
+// values of lat and lon are a valid point and cannot tell it apart.
+func (i *searchBuildingsInput) Resolve(ctx huma.Context) []error {
+	u := ctx.URL()
+	query := u.Query()
+	i.hasCenter = query.Has("lat") && query.Has("lon")
+	return nil
+}
+
+type searchBuildingsByPolygonInput struct {
+	Cluster bool  `query:"cluster" doc:"Group nearby buildings into clusters, at most 50000 buildings are grouped"`
+	Zoom    int32 `query:"zoom" minimum:"0" maximum:"22" default:"12" doc:"Map zoom level the clusters are sized for"`
+	Limit   int32 `query:"limit" minimum:"1" maximum:"10000" default:"2000" doc:"Maximum number of buildings, ignored when clustering"`
+	Body    GeoJSONPolygon
+}
+
+type searchBuildingsOutput struct {
+	Body BuildingFeatureCollection
+}
+
+// maxClusterPoints bounds how many buildings a single clustering request
+// groups, regardless of how large the area is.
+const maxClusterPoints = 50000
+
+// buildingArea is the search area in the shape the database functions take it.
+// Exactly one of the bounding box, the radius or the polygon is set.
+type buildingArea struct {
+	MinLongitude *float64
+	MinLatitude  *float64
+	MaxLongitude *float64
+	MaxLatitude  *float64
+	Latitude     *float64
+	Longitude    *float64
+	RadiusMeters *float64
+	Polygon      *string
+}
+
+func (s *Server) searchBuildingsHandler(ctx context.Context, input *searchBuildingsInput) (*searchBuildingsOutput, error) {
+	area, err := mapBuildingArea(input)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	return s.searchBuildings(ctx, area, input.Cluster, input.Zoom, input.Limit)
+}
+
+func (s *Server) searchBuildingsByPolygonHandler(ctx context.Context, input *searchBuildingsByPolygonInput) (*searchBuildingsOutput, error) {
+	if err := validatePolygon(input.Body); err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	polygon, err := json.Marshal(input.Body)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid polygon: %v", err))
+	}
+	area := buildingArea{Polygon: util.ToStringPtr(string(polygon))}
+	return s.searchBuildings(ctx, area, input.Cluster, input.Zoom, input.Limit)
+}
+
+func (s *Server) searchBuildings(ctx context.Context, area buildingArea, cluster bool, zoom, limit int32) (*searchBuildingsOutput, error) {
+	out := BuildingFeatureCollection{Type: "FeatureCollection", Features: []BuildingFeature{}}
+	if cluster {
+		rows, err := s.buildingsQueries.ClusterBuildings(ctx, &buildingsdb.ClusterBuildingsParams{
+			MinLongitude: area.MinLongitude,
+			MinLatitude:  area.MinLatitude,
+			MaxLongitude: area.MaxLongitude,
+			MaxLatitude:  area.MaxLatitude,
+			Latitude:     area.Latitude,
+			Longitude:    area.Longitude,
+			RadiusMeters: area.RadiusMeters,
+			Polygon:      area.Polygon,
+			GridSize:     clusterGridSize(zoom),
+			MaxPoints:    maxClusterPoints,
+		})
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to cluster buildings", "error", err)
+			return nil, huma.Error500InternalServerError("failed to search buildings")
+		}
+		var grouped int64
+		for _, row := range rows {
+			feature := mapBuildingCluster(row)
+			grouped += feature.Properties.PointCount
+			out.Features = append(out.Features, feature)
+		}
+		out.Truncated = grouped >= maxClusterPoints
+		return &searchBuildingsOutput{Body: out}, nil
+	}
+	rows, err := s.buildingsQueries.SearchBuildings(ctx, &buildingsdb.SearchBuildingsParams{
+		MinLongitude: area.MinLongitude,
+		MinLatitude:  area.MinLatitude,
+		MaxLongitude: area.MaxLongitude,
+		MaxLatitude:  area.MaxLatitude,
+		Latitude:     area.Latitude,
+		Longitude:    area.Longitude,
+		RadiusMeters: area.RadiusMeters,
+		Polygon:      area.Polygon,
+		// One extra row tells whether the result was truncated.
+		PageSize: limit + 1,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to search buildings", "error", err)
+		return nil, huma.Error500InternalServerError("failed to search buildings")
+	}
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		out.Truncated = true
+	}
+	for _, row := range rows {
+		out.Features = append(out.Features, mapBuildingFeature(row))
+	}
+	return &searchBuildingsOutput{Body: out}, nil
+}
+
+func mapBuildingArea(input *searchBuildingsInput) (buildingArea, error) {
+	hasBBox := len(input.BBox) > 0
+	hasRadius := input.Radius > 0
+	switch {
+	case hasBBox && hasRadius:
+		return buildingArea{}, errors.New("bbox and radius cannot be combined")
+	case hasBBox:
+		if len(input.BBox) != 4 {
+			return buildingArea{}, errors.New("bbox must have four values: min_longitude,min_latitude,max_longitude,max_latitude")
+		}
+		minLon, minLat, maxLon, maxLat := input.BBox[0], input.BBox[1], input.BBox[2], input.BBox[3]
+		if !validLongitude(minLon) || !validLongitude(maxLon) || !validLatitude(minLat) || !validLatitude(maxLat) {
+			return buildingArea{}, errors.New("bbox is out of range")
+		}
+		if minLon >= maxLon || minLat >= maxLat {
+			return buildingArea{}, errors.New("bbox minimums must be less than maximums")
+		}
+		return buildingArea{MinLongitude: &minLon, MinLatitude: &minLat, MaxLongitude: &maxLon, MaxLatitude: &maxLat}, nil
+	case hasRadius:
+		if !input.hasCenter {
+			return buildingArea{}, errors.New("radius needs lat and lon")
+		}
+		return buildingArea{Latitude: &input.Latitude, Longitude: &input.Longitude, RadiusMeters: &input.Radius}, nil
+	default:
+		return buildingArea{}, errors.New("either bbox or lat, lon and radius are required")
+	}
+}
+
+func validatePolygon(p GeoJSONPolygon) error {
+	if len(p.Coordinates) == 0 {
+		return errors.New("polygon has no rings")
+	}
+	for i, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return fmt.Errorf("polygon ring %d needs at least four positions", i)
+		}
+		for _, position := range ring {
+			if len(position) < 2 || !validLongitude(position[0]) || !validLatitude(position[1]) {
+				return fmt.Errorf("polygon ring %d has an invalid position", i)
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return fmt.Errorf("polygon ring %d is not closed", i)
+		}
+	}
+	return nil
+}
+
+func validLatitude(v float64) bool {
+	return v >= -90 && v <= 90
+}
+
+func validLongitude(v float64) bool {
+	return v >= -180 && v <= 180
+}
+
+// clusterGridSize returns the cluster cell size in degrees for a zoom level,
+// roughly 64 pixels on the usual 256 pixel web map tiles.
+func clusterGridSize(zoom int32) float64 {
+	return 90 / math.Pow(2, float64(zoom))
+}
+
+func mapBuildingFeature(row buildingsdb.SearchBuildingsRow) BuildingFeature {
+	return BuildingFeature{
+		Type:     "Feature",
+		ID:       util.FromUUID(row.BuildingID),
+		Geometry: newGeoJSONPoint(row.Latitude, row.Longitude),
+		Properties: BuildingFeatureProperties{
+			Source:     row.Source,
+			Address:    row.Address,
+			BuildYear:  row.BuildYear,
+			PointCount: 1,
+		},
+	}
+}
+
+func mapBuildingCluster(row buildingsdb.ClusterBuildingsRow) BuildingFeature {
+	var count int64
+	if row.PointCount != nil {
+		count = *row.PointCount
+	}
+	return BuildingFeature{
+		Type:     "Feature",
+		ID:       util.FromUUID(row.BuildingID),
+		Geometry: newGeoJSONPoint(row.Latitude, row.Longitude),
+		Properties: BuildingFeatureProperties{
+			Source:     row.Source,
+			Cluster:    count > 1,
+			PointCount: count,
+		},
+	}
+}
+
+func newGeoJSONPoint(latitude, longitude *float64) GeoJSONPoint {
+	point := GeoJSONPoint{Type: "Point", Coordinates: []float64{}}
+	if latitude != nil && longitude != nil {
+		point.Coordinates = []float64{*longitude, *latitude}
+	}
+	return point
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	listingsdb "koditon-go/internal/listings/db"
+	"koditon-go/internal/util"
+)
+
+type Listing struct {
+	ID            string    `json:"id" doc:"Source-prefixed listing ID, e.g. frontdoor:ad:123"`
+	Source        string    `json:"source" enum:"frontdoor,shortcut"`
+	ExternalID    string    `json:"external_id" doc:"ID of the listing in the source"`
+	URL           *string   `json:"url,omitempty"`
+	Price         *float64  `json:"price,omitempty"`
+	DebtFreePrice *float64  `json:"debt_free_price,omitempty"`
+	Area          *float64  `json:"area,omitempty"`
+	Rooms         *int32    `json:"rooms,omitempty"`
+	Postcode      *string   `json:"postcode,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	FirstSeenAt   time.Time `json:"first_seen_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	Status        string    `json:"status" enum:"active,removed"`
+}
+
+type ListingPage struct {
+	Items      []Listing `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty" doc:"Opaque cursor for fetching the next page"`
+}
+
+type listListingsInput struct {
+	Source    string   `query:"source" enum:"frontdoor,shortcut"`
+	Status    string   `query:"status" enum:"active,removed"`
+	MinPrice  float64  `query:"min_price" minimum:"0"`
+	MaxPrice  float64  `query:"max_price" minimum:"0"`
+	MinArea   float64  `query:"min_area" minimum:"0"`
+	MaxArea   float64  `query:"max_area" minimum:"0"`
+	MinRooms  int32    `query:"min_rooms" minimum:"0"`
+	MaxRooms  int32    `query:"max_rooms" minimum:"0"`
+	Postcodes []string `query:"postcode" doc:"Comma-separated postcodes"`
+	Limit     int32    `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Cursor    string   `query:"cursor" doc:"Cursor returned by the previous page"`
+}
+
+type listListingsOutput struct {
+	Body ListingPage
+}
+
+// listListingsHandler searches listings from all sources, newest first.
+func (s *Server) listListingsHandler(ctx context.Context, input *listListingsInput) (*listListingsOutput, error) {
+	params, err := mapSearchListingsParams(input)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	rows, err := s.listingsQueries.SearchListings(ctx, params)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to search listings", "error", err)
+		return nil, huma.Error500InternalServerError("failed to search listings")
+	}
+	page := ListingPage{Items: []Listing{}}
+	if len(rows) > int(input.Limit) {
+		rows = rows[:input.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeListingCursor(last.FirstSeenAt, last.ListingID)
+	}
+	for _, row := range rows {
+		page.Items = append(page.Items, mapListing(row))
+	}
+	return &listListingsOutput{Body: page}, nil
+}
+
+func mapSearchListingsParams(input *listListingsInput) (*listingsdb.SearchListingsParams, error) {
+	params := &listingsdb.SearchListingsParams{
+		Source:    util.ToStringPtr(input.Source),
+		Status:    util.ToStringPtr(input.Status),
+		Postcodes: []string{},
+		// One extra row tells whether another page exists.
+		PageSize: input.Limit + 1,
+	}
+	for _, raw := range input.Postcodes {
+		if postcode := strings.TrimSpace(raw); postcode != "" {
+			params.Postcodes = append(params.Postcodes, postcode)
+		}
+	}
+	if input.MinPrice > 0 {
+		params.MinPrice = &input.MinPrice
+	}
+	if input.MaxPrice > 0 {
+		params.MaxPrice = &input.MaxPrice
+	}
+	if input.MinArea > 0 {
+		params.MinArea = &input.MinArea
+	}
+	if input.MaxArea > 0 {
+		params.MaxArea = &input.MaxArea
+	}
+	if input.MinRooms > 0 {
+		params.MinRooms = &input.MinRooms
+	}
+	if input.MaxRooms > 0 {
+		params.MaxRooms = &input.MaxRooms
+	}
+	if input.Cursor != "" {
+		firstSeenAt, id, err := decodeListingCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.CursorFirstSeenAt = pgtype.Timestamptz{Time: firstSeenAt, Valid: true}
+		params.CursorListingID = &id
+	}
+	return params, nil
+}
+
+func mapListing(row listingsdb.VwListing) Listing {
+	return Listing{
+		ID:            row.ListingID,
+		Source:        row.Source,
+		ExternalID:    row.ExternalID,
+		URL:           row.Url,
+		Price:         row.Price,
+		DebtFreePrice: row.DebtFreePrice,
+		Area:          row.Area,
+		Rooms:         row.Rooms,
+		Postcode:      row.Postcode,
+		Latitude:      row.Latitude,
+		Longitude:     row.Longitude,
+		FirstSeenAt:   row.FirstSeenAt,
+		LastSeenAt:    row.LastSeenAt,
+		Status:        row.Status,
+	}
+}
+
+// The cursor is the first seen time and ID of the last listing on the page.
+func encodeListingCursor(firstSeenAt time.Time, id string) string {
+	raw := firstSeenAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeListingCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	timePart, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", errInvalidCursor
+	}
+	firstSeenAt, err := time.Parse(time.RFC3339Nano, timePart)
+	if err != nil {
+		return time.Time{}, "", errInvalidCursor
+	}
+	return firstSeenAt, id, nil
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	frontdoordb "koditon-go/internal/frontdoor/db"
+	shortcutdb "koditon-go/internal/shortcut/db"
+)
+
+const (
+	listingIDPrefixFrontdoorAd = "frontdoor:ad:"
+	listingIDPrefixShortcutAd  = "shortcut:ad:"
+)
+
+type ListingTimelineEvent struct {
+	Event                 string    `json:"event" enum:"listed,price_changed,status_changed,delisted,relisted"`
+	Price                 *float64  `json:"price,omitempty"`
+	DebtFreePrice         *float64  `json:"debt_free_price,omitempty"`
+	PreviousPrice         *float64  `json:"previous_price,omitempty"`
+	PreviousDebtFreePrice *float64  `json:"previous_debt_free_price,omitempty"`
+	Status                *string   `json:"status,omitempty" doc:"Ad status in the source, the ad type for shortcut"`
+	RecordedAt            time.Time `json:"recorded_at"`
+}
+
+type ListingTimeline struct {
+	ID           string                 `json:"id"`
+	Source       string                 `json:"source" enum:"frontdoor,shortcut"`
+	ExternalID   string                 `json:"external_id"`
+	URL          string                 `json:"url"`
+	ListedAt     time.Time              `json:"listed_at" doc:"Publishing time when the source reports one, otherwise when the ad was first seen"`
+	LastSeenAt   time.Time              `json:"last_seen_at"`
+	DelistedAt   *time.Time             `json:"delisted_at,omitempty"`
+	DaysOnMarket int                    `json:"days_on_market" doc:"Whole days from listed_at to delisted_at, or to now for listed ads"`
+	Events       []ListingTimelineEvent `json:"events" doc:"Oldest first"`
+}
+
+type listingTimelineInput struct {
+	ID string `path:"id" doc:"Listing ID of an ad, e.g. frontdoor:ad:abc123 or shortcut:ad:123456"`
+}
+
+type listingTimelineOutput struct {
+	Body ListingTimeline
+}
+
+func (s *Server) listingTimelineHandler(ctx context.Context, input *listingTimelineInput) (*listingTimelineOutput, error) {
+	var timeline *ListingTimeline
+	var err error
+	switch {
+	case strings.HasPrefix(input.ID, listingIDPrefixFrontdoorAd):
+		timeline, err = s.frontdoorAdTimeline(ctx, strings.TrimPrefix(input.ID, listingIDPrefixFrontdoorAd))
+	case strings.HasPrefix(input.ID, listingIDPrefixShortcutAd):
+		adID, parseErr := strconv.ParseInt(strings.TrimPrefix(input.ID, listingIDPrefixShortcutAd), 10, 64)
+		if parseErr != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid shortcut ad id: %s", input.ID))
+		}
+		timeline, err = s.shortcutAdTimeline(ctx, adID)
+	default:
+		return nil, huma.Error400BadRequest("timelines are only kept for frontdoor:ad: and shortcut:ad: listings")
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("listing %s not found", input.ID))
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get listing timeline", "listing_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to get listing timeline")
+	}
+	timeline.ID = input.ID
+	return &listingTimelineOutput{Body: *timeline}, nil
+}
+
+func (s *Server) frontdoorAdTimeline(ctx context.Context, friendlyID string) (*ListingTimeline, error) {
+	ad, err := s.frontdoorQueries.GetFrontdoorAdByExternalID(ctx, friendlyID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.frontdoorQueries.ListFrontdoorAdHistory(ctx, ad.FrontdoorAdsID)
+	if err != nil {
+		return nil, fmt.Errorf("list frontdoor ad history: %w", err)
+	}
+	listedAt := ad.FrontdoorAdsFirstSeenAt.Time
+	if ad.FrontdoorAdsPublishingTime.Valid {
+		listedAt = ad.FrontdoorAdsPublishingTime.Time
+	}
+	timeline := newListingTimeline("frontdoor", ad.FrontdoorAdsExternalID, ad.FrontdoorAdsUrl, listedAt, ad.FrontdoorAdsLastSeenAt, ad.FrontdoorAdsDelistedAt)
+	for _, h := range history {
+		timeline.Events = append(timeline.Events, mapFrontdoorAdHistory(h))
+	}
+	return timeline, nil
+}
+
+func (s *Server) shortcutAdTimeline(ctx context.Context, adID int64) (*ListingTimeline, error) {
+	ad, err := s.shortcutQueries.GetShortcutAdByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.shortcutQueries.ListShortcutAdHistory(ctx, adID)
+	if err != nil {
+		return nil, fmt.Errorf("list shortcut ad history: %w", err)
+	}
+	timeline := newListingTimeline("shortcut", strconv.FormatInt(ad.ShortcutAdsID, 10), ad.ShortcutAdsUrl, ad.ShortcutAdsFirstSeenAt.Time, ad.ShortcutAdsLastSeenAt, ad.ShortcutAdsDelistedAt)
+	for _, h := range history {
+		timeline.Events = append(timeline.Events, mapShortcutAdHistory(h))
+	}
+	return timeline, nil
+}
+
+func newListingTimeline(source, externalID, url string, listedAt time.Time, lastSeenAt, delistedAt pgtype.Timestamptz) *ListingTimeline {
+	timeline := &ListingTimeline{
+		Source:     source,
+		ExternalID: externalID,
+		URL:        url,
+		ListedAt:   listedAt,
+		LastSeenAt: lastSeenAt.Time,
+		Events:     []ListingTimelineEvent{},
+	}
+	end := time.Now()
+	if delistedAt.Valid {
+		timeline.DelistedAt = &delistedAt.Time
+		end = delistedAt.Time
+	}
+	if end.After(listedAt) {
+		timeline.DaysOnMarket = int(end.Sub(listedAt).Hours() / 24)
+	}
+	return timeline
+}
+
+func mapFrontdoorAdHistory(h frontdoordb.FrontdoorAdHistory) ListingTimelineEvent {
+	return ListingTimelineEvent{
+		Event:                 h.FrontdoorAdHistoryEvent,
+		Price:                 h.FrontdoorAdHistoryPrice,
+		DebtFreePrice:         h.FrontdoorAdHistoryDebtFreePrice,
+		PreviousPrice:         h.FrontdoorAdHistoryPreviousPrice,
+		PreviousDebtFreePrice: h.FrontdoorAdHistoryPreviousDebtFreePrice,
+		Status:                h.FrontdoorAdHistoryStatus,
+		RecordedAt:            h.FrontdoorAdHistoryRecordedAt.Time,
+	}
+}
+
+func mapShortcutAdHistory(h shortcutdb.ShortcutAdHistory) ListingTimelineEvent {
+	return ListingTimelineEvent{
+		Event:                 h.ShortcutAdHistoryEvent,
+		Price:                 h.ShortcutAdHistoryPrice,
+		DebtFreePrice:         h.ShortcutAdHistoryDebtFreePrice,
+		PreviousPrice:         h.ShortcutAdHistoryPreviousPrice,
+		PreviousDebtFreePrice: h.ShortcutAdHistoryPreviousDebtFreePrice,
+		Status:                h.ShortcutAdHistoryStatus,
+		RecordedAt:            h.ShortcutAdHistoryRecordedAt.Time,
+	}
+}
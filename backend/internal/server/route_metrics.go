@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"koditon-go/internal/metrics"
+	"koditon-go/internal/taskqueue"
+)
+
+// metricsHandler serves Prometheus metrics. Queue, task and DLQ gauges are read
+// from the database on every scrape; worker and HTTP client counters and
+// histograms live in process and are reset on restart.
+func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	scrape := prometheus.NewRegistry()
+	if err := s.collectDatabaseMetrics(ctx, scrape); err != nil {
+		s.logger.ErrorContext(ctx, "failed to collect metrics", "error", err)
+		http.Error(w, "failed to collect metrics", http.StatusInternalServerError)
+		return
+	}
+	s.collectBreakerMetrics(scrape)
+	s.collectTransportMetrics(scrape)
+	promhttp.HandlerFor(prometheus.Gatherers{scrape, metrics.Registry}, promhttp.HandlerOpts{
+		ErrorLog:      slog.NewLogLogger(s.logger.Handler(), slog.LevelError),
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	}).ServeHTTP(w, r)
+}
+
+// newScrapeGauge registers a gauge whose values are set on every scrape.
+func newScrapeGauge(reg prometheus.Registerer, name, help string, labels ...string) *prometheus.GaugeVec {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+	reg.MustRegister(gauge)
+	return gauge
+}
+
+func (s *Server) collectBreakerMetrics(reg prometheus.Registerer) {
+	gauge := newScrapeGauge(reg, "koditon_circuit_breaker_state", "Whether the circuit breaker of a source is in a state, by source and state.", "source", "state")
+	if s.workers == nil {
+		return
+	}
+	for _, b := range s.workers.Breakers() {
+		for _, state := range []taskqueue.BreakerState{taskqueue.BreakerClosed, taskqueue.BreakerOpen, taskqueue.BreakerHalfOpen} {
+			value := 0.0
+			if b.State == state {
+				value = 1
+			}
+			gauge.WithLabelValues(b.Name, string(state)).Set(value)
+		}
+	}
+}
+
+func (s *Server) collectTransportMetrics(reg prometheus.Registerer) {
+	inFlight := newScrapeGauge(reg, "koditon_http_in_flight_requests", "Outbound requests of the scraper clients in flight, by source and host.", "source", "host")
+	paused := newScrapeGauge(reg, "koditon_http_host_paused", "Whether a host is paused after answering with Retry-After, by source and host.", "source", "host")
+	for _, h := range s.limiters.stats() {
+		inFlight.WithLabelValues(h.Source, h.Host).Set(float64(h.InFlight))
+		value := 0.0
+		if h.PausedUntil != nil {
+			value = 1
+		}
+		paused.WithLabelValues(h.Source, h.Host).Set(value)
+	}
+}
+
+func (s *Server) collectDatabaseMetrics(ctx context.Context, reg prometheus.Registerer) error {
+	queues, err := s.taskQueue.GetAllQueueMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	length := newScrapeGauge(reg, "koditon_queue_length", "Messages currently in the queue.", "queue")
+	oldestAge := newScrapeGauge(reg, "koditon_queue_oldest_message_age_seconds", "Age of the oldest message in the queue.", "queue")
+	for _, q := range queues {
+		length.WithLabelValues(q.QueueName).Set(float64(q.QueueLength))
+		if q.OldestMsgAgeSec != nil {
+			oldestAge.WithLabelValues(q.QueueName).Set(float64(*q.OldestMsgAgeSec))
+		}
+	}
+	counts, err := s.taskQueue.GetTaskCountsByTypeAndStatus(ctx)
+	if err != nil {
+		return err
+	}
+	tasks := newScrapeGauge(reg, "koditon_tasks", "Tasks by task type and status.", "task_type", "status")
+	for _, row := range counts {
+		tasks.WithLabelValues(row.TaskType, row.Status).Set(float64(row.Count))
+	}
+	dlq, err := s.taskQueue.GetDLQStats(ctx)
+	if err != nil {
+		return err
+	}
+	dlqEntries := newScrapeGauge(reg, "koditon_dlq_entries", "Dead letter queue entries by state.", "state")
+	dlqEntries.WithLabelValues("pending").Set(float64(dlq.Pending))
+	dlqEntries.WithLabelValues("requeued").Set(float64(dlq.Requeued))
+	syncStats, err := s.taskQueue.GetSyncStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	entities := newScrapeGauge(reg, "koditon_entities", "Scheduled entities by status.", "status")
+	entities.WithLabelValues("active").Set(float64(syncStats.ActiveEntities.Int32))
+	entities.WithLabelValues("stopped").Set(float64(syncStats.StoppedEntities.Int32))
+	return nil
+}
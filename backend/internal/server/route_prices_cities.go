@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+
+	pricesdb "koditon-go/internal/prices/db"
+	"koditon-go/internal/util"
+)
+
+type City struct {
+	ID            string         `json:"id" format:"uuid"`
+	Name          string         `json:"name"`
+	Neighborhoods []Neighborhood `json:"neighborhoods"`
+}
+
+type Neighborhood struct {
+	ID         string      `json:"id" format:"uuid"`
+	Name       string      `json:"name"`
+	PostalCode *PostalCode `json:"postal_code,omitempty"`
+}
+
+type PostalCode struct {
+	ID   string `json:"id" format:"uuid"`
+	Code string `json:"code"`
+}
+
+type listCitiesOutput struct {
+	Body []City
+}
+
+type listCityNeighborhoodsInput struct {
+	ID string `path:"id" format:"uuid" doc:"City ID"`
+}
+
+type listCityNeighborhoodsOutput struct {
+	Body []Neighborhood
+}
+
+func (s *Server) listCitiesHandler(ctx context.Context, _ *struct{}) (*listCitiesOutput, error) {
+	rows, err := s.pricesQueries.ListCitiesWithNeighborhoods(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list cities", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list cities")
+	}
+	return &listCitiesOutput{Body: groupCities(rows)}, nil
+}
+
+func (s *Server) listCityNeighborhoodsHandler(ctx context.Context, input *listCityNeighborhoodsInput) (*listCityNeighborhoodsOutput, error) {
+	cityID, err := uuid.Parse(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid city id: %s", input.ID))
+	}
+	rows, err := s.pricesQueries.ListCitiesWithNeighborhoods(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list cities", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list neighborhoods")
+	}
+	for _, city := range groupCities(rows) {
+		if city.ID == cityID.String() {
+			return &listCityNeighborhoodsOutput{Body: city.Neighborhoods}, nil
+		}
+	}
+	return nil, huma.Error404NotFound(fmt.Sprintf("city %s not found", input.ID))
+}
+
+// groupCities folds the flat city/neighborhood/postal code join rows into
+// nested objects, preserving the name ordering of the query.
+func groupCities(rows []pricesdb.ListCitiesWithNeighborhoodsRow) []City {
+	cities := []City{}
+	index := make(map[string]int)
+	for _, row := range rows {
+		cityID := util.FromUUID(row.PricesCitiesID)
+		i, ok := index[cityID]
+		if !ok {
+			cities = append(cities, City{
+				ID:            cityID,
+				Name:          row.PricesCitiesName,
+				Neighborhoods: []Neighborhood{},
+			})
+			i = len(cities) - 1
+			index[cityID] = i
+		}
+		if !row.PricesNeighborhoodsID.Valid || row.PricesNeighborhoodsName == nil {
+			continue
+		}
+		neighborhood := Neighborhood{
+			ID:   util.FromUUID(row.PricesNeighborhoodsID),
+			Name: *row.PricesNeighborhoodsName,
+		}
+		if row.PricesPostalCodesID.Valid && row.PricesPostalCodesCode != nil {
+			neighborhood.PostalCode = &PostalCode{
+				ID:   util.FromUUID(row.PricesPostalCodesID),
+				Code: *row.PricesPostalCodesCode,
+			}
+		}
+		cities[i].Neighborhoods = append(cities[i].Neighborhoods, neighborhood)
+	}
+	return cities
+}
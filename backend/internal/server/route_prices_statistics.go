@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+
+	pricesdb "koditon-go/internal/prices/db"
+	"koditon-go/internal/util"
+)
+
+type PriceStatistics struct {
+	NeighborhoodID            string  `json:"neighborhood_id,omitempty"`
+	NeighborhoodName          *string `json:"neighborhood_name,omitempty"`
+	PeriodIdentifier          string  `json:"period_identifier"`
+	Category                  *string `json:"category,omitempty" doc:"Set when split_by_category is enabled"`
+	BuildYearFrom             *int32  `json:"build_year_from,omitempty" doc:"Set when build_year_bucket is given"`
+	BuildYearTo               *int32  `json:"build_year_to,omitempty" doc:"Inclusive upper bound of the build year bucket"`
+	Count                     int64   `json:"count"`
+	MeanPricePerSquareMeter   float64 `json:"mean_price_per_square_meter"`
+	MedianPricePerSquareMeter float64 `json:"median_price_per_square_meter"`
+	P10PricePerSquareMeter    float64 `json:"p10_price_per_square_meter"`
+	P90PricePerSquareMeter    float64 `json:"p90_price_per_square_meter"`
+	AverageArea               float64 `json:"average_area"`
+}
+
+type priceStatisticsInput struct {
+	NeighborhoodIDs []string `query:"neighborhood_id" doc:"Comma-separated neighborhood IDs"`
+	PeriodFrom      string   `query:"period_from" doc:"First period to include, e.g. 2024-01"`
+	PeriodTo        string   `query:"period_to" doc:"Last period to include, e.g. 2025-12"`
+	BuildingType    string   `query:"building_type"`
+	SplitByCategory bool     `query:"split_by_category" doc:"Group additionally by room category"`
+	BuildYearBucket int32    `query:"build_year_bucket" minimum:"0" maximum:"100" doc:"Group additionally by build year buckets of this many years"`
+}
+
+type priceStatisticsOutput struct {
+	Body []PriceStatistics
+}
+
+func (s *Server) priceStatisticsHandler(ctx context.Context, input *priceStatisticsInput) (*priceStatisticsOutput, error) {
+	params := &pricesdb.GetPricesTransactionStatisticsParams{
+		SplitByCategory: input.SplitByCategory,
+		BuildYearBucket: input.BuildYearBucket,
+		PeriodFrom:      util.ToStringPtr(input.PeriodFrom),
+		PeriodTo:        util.ToStringPtr(input.PeriodTo),
+		BuildingType:    util.ToStringPtr(input.BuildingType),
+	}
+	for _, raw := range input.NeighborhoodIDs {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid neighborhood id: %s", raw))
+		}
+		params.NeighborhoodIds = append(params.NeighborhoodIds, util.ToUUID(id))
+	}
+	rows, err := s.pricesQueries.GetPricesTransactionStatistics(ctx, params)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to compute price statistics", "error", err)
+		return nil, huma.Error500InternalServerError("failed to compute price statistics")
+	}
+	stats := make([]PriceStatistics, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, mapPriceStatistics(row, input.BuildYearBucket))
+	}
+	return &priceStatisticsOutput{Body: stats}, nil
+}
+
+func mapPriceStatistics(row pricesdb.GetPricesTransactionStatisticsRow, bucket int32) PriceStatistics {
+	stats := PriceStatistics{
+		NeighborhoodID:            util.FromUUID(row.PricesNeighborhoodsID),
+		NeighborhoodName:          row.PricesNeighborhoodsName,
+		PeriodIdentifier:          row.PricesTransactionsPeriodIdentifier,
+		Category:                  row.Category,
+		Count:                     row.TransactionCount,
+		MeanPricePerSquareMeter:   row.MeanPricePerSquareMeter,
+		MedianPricePerSquareMeter: row.MedianPricePerSquareMeter,
+		P10PricePerSquareMeter:    row.P10PricePerSquareMeter,
+		P90PricePerSquareMeter:    row.P90PricePerSquareMeter,
+		AverageArea:               row.AverageArea,
+	}
+	if row.BuildYearBucketStart != nil && bucket > 0 {
+		to := *row.BuildYearBucketStart + bucket - 1
+		stats.BuildYearFrom = row.BuildYearBucketStart
+		stats.BuildYearTo = &to
+	}
+	return stats
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	pricesdb "koditon-go/internal/prices/db"
+	"koditon-go/internal/util"
+)
+
+var (
+	errInvalidCursor  = errors.New("invalid cursor")
+	errCursorMismatch = errors.New("cursor was issued for another sort or order")
+)
+
+type Transaction struct {
+	ID                  string  `json:"id" format:"uuid"`
+	Description         string  `json:"description"`
+	BuildingType        string  `json:"building_type"`
+	Category            string  `json:"category"`
+	Area                float64 `json:"area"`
+	Price               int32   `json:"price"`
+	PricePerSquareMeter int32   `json:"price_per_square_meter"`
+	BuildYear           int32   `json:"build_year"`
+	Floor               *string `json:"floor,omitempty"`
+	Elevator            bool    `json:"elevator"`
+	Condition           *string `json:"condition,omitempty"`
+	Plot                *string `json:"plot,omitempty"`
+	EnergyClass         *string `json:"energy_class,omitempty"`
+	PeriodIdentifier    string  `json:"period_identifier"`
+	NeighborhoodID      string  `json:"neighborhood_id,omitempty"`
+	NeighborhoodName    *string `json:"neighborhood_name,omitempty"`
+	PostalCode          *string `json:"postal_code,omitempty"`
+	CityName            *string `json:"city_name,omitempty"`
+}
+
+type TransactionPage struct {
+	Items      []Transaction `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty" doc:"Opaque cursor for fetching the next page"`
+}
+
+type listTransactionsInput struct {
+	NeighborhoodIDs  []string `query:"neighborhood_id" doc:"Comma-separated neighborhood IDs"`
+	PostalCode       string   `query:"postal_code"`
+	BuildingType     string   `query:"building_type" doc:"Building type, e.g. kt, rt or ok"`
+	Category         string   `query:"category" doc:"Room category"`
+	MinArea          float64  `query:"min_area" minimum:"0"`
+	MaxArea          float64  `query:"max_area" minimum:"0"`
+	MinBuildYear     int32    `query:"min_build_year" minimum:"0"`
+	MaxBuildYear     int32    `query:"max_build_year" minimum:"0"`
+	Elevator         string   `query:"elevator" enum:"true,false"`
+	Condition        string   `query:"condition"`
+	EnergyClass      string   `query:"energy_class"`
+	PeriodIdentifier string   `query:"period_identifier" doc:"Transaction period, e.g. 2025-01"`
+	Sort             string   `query:"sort" enum:"price,price_per_square_meter,build_year" default:"price_per_square_meter"`
+	Order            string   `query:"order" enum:"asc,desc" default:"desc"`
+	Limit            int32    `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Cursor           string   `query:"cursor" doc:"Cursor returned by the previous page"`
+}
+
+type listTransactionsOutput struct {
+	Body TransactionPage
+}
+
+func (s *Server) listTransactionsHandler(ctx context.Context, input *listTransactionsInput) (*listTransactionsOutput, error) {
+	params, err := mapSearchTransactionsParams(input)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	rows, err := s.searchTransactions(ctx, input.Sort, input.Order == "desc", params)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to search transactions", "error", err)
+		return nil, huma.Error500InternalServerError("failed to search transactions")
+	}
+	page := TransactionPage{Items: []Transaction{}}
+	if len(rows) > int(input.Limit) {
+		rows = rows[:input.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeTransactionCursor(transactionCursor{
+			Sort:  input.Sort,
+			Order: input.Order,
+			Value: transactionSortValue(last, input.Sort),
+			ID:    last.PricesTransactionsID,
+		})
+	}
+	for _, row := range rows {
+		page.Items = append(page.Items, mapTransaction(row))
+	}
+	return &listTransactionsOutput{Body: page}, nil
+}
+
+// The search queries only differ in the column and direction they sort by,
+// their parameters are the same.
+type transactionSearchParams = pricesdb.SearchPricesTransactionsByPricePerSquareMeterDescParams
+
+// searchTransactions runs the keyset query of the sort column and direction.
+func (s *Server) searchTransactions(ctx context.Context, sort string, desc bool, params *transactionSearchParams) ([]pricesdb.VwPricesTransaction, error) {
+	q := s.pricesQueries
+	switch {
+	case sort == "price" && desc:
+		return q.SearchPricesTransactionsByPriceDesc(ctx, (*pricesdb.SearchPricesTransactionsByPriceDescParams)(params))
+	case sort == "price":
+		return q.SearchPricesTransactionsByPriceAsc(ctx, (*pricesdb.SearchPricesTransactionsByPriceAscParams)(params))
+	case sort == "build_year" && desc:
+		return q.SearchPricesTransactionsByBuildYearDesc(ctx, (*pricesdb.SearchPricesTransactionsByBuildYearDescParams)(params))
+	case sort == "build_year":
+		return q.SearchPricesTransactionsByBuildYearAsc(ctx, (*pricesdb.SearchPricesTransactionsByBuildYearAscParams)(params))
+	case desc:
+		return q.SearchPricesTransactionsByPricePerSquareMeterDesc(ctx, params)
+	default:
+		return q.SearchPricesTransactionsByPricePerSquareMeterAsc(ctx, (*pricesdb.SearchPricesTransactionsByPricePerSquareMeterAscParams)(params))
+	}
+}
+
+func transactionSortValue(row pricesdb.VwPricesTransaction, sort string) int32 {
+	switch sort {
+	case "price":
+		return row.PricesTransactionsPrice
+	case "build_year":
+		return row.PricesTransactionsBuildYear
+	default:
+		return row.PricesTransactionsPricePerSquareMeter
+	}
+}
+
+func mapSearchTransactionsParams(input *listTransactionsInput) (*transactionSearchParams, error) {
+	params := &transactionSearchParams{
+		PostalCode:       util.ToStringPtr(input.PostalCode),
+		BuildingType:     util.ToStringPtr(input.BuildingType),
+		Category:         util.ToStringPtr(input.Category),
+		Condition:        util.ToStringPtr(input.Condition),
+		EnergyClass:      util.ToStringPtr(input.EnergyClass),
+		PeriodIdentifier: util.ToStringPtr(input.PeriodIdentifier),
+		// One extra row tells whether another page exists.
+		PageSize: input.Limit + 1,
+	}
+	// The first page starts from before the first row in the sort order.
+	if input.Order == "desc" {
+		params.CursorValue = math.MaxInt32
+		params.CursorID = util.ToUUID(uuid.Max)
+	} else {
+		params.CursorValue = math.MinInt32
+		params.CursorID = util.ToUUID(uuid.Nil)
+	}
+	for _, raw := range input.NeighborhoodIDs {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid neighborhood id: %s", raw)
+		}
+		params.NeighborhoodIds = append(params.NeighborhoodIds, util.ToUUID(id))
+	}
+	if input.MinArea > 0 {
+		params.MinArea = &input.MinArea
+	}
+	if input.MaxArea > 0 {
+		params.MaxArea = &input.MaxArea
+	}
+	if input.MinBuildYear > 0 {
+		params.MinBuildYear = &input.MinBuildYear
+	}
+	if input.MaxBuildYear > 0 {
+		params.MaxBuildYear = &input.MaxBuildYear
+	}
+	if input.Elevator != "" {
+		elevator := input.Elevator == "true"
+		params.Elevator = &elevator
+	}
+	if input.Cursor != "" {
+		cursor, err := decodeTransactionCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != input.Sort || cursor.Order != input.Order {
+			return nil, errCursorMismatch
+		}
+		params.CursorValue = cursor.Value
+		params.CursorID = cursor.ID
+	}
+	return params, nil
+}
+
+func mapTransaction(row pricesdb.VwPricesTransaction) Transaction {
+	return Transaction{
+		ID:                  util.FromUUID(row.PricesTransactionsID),
+		Description:         row.PricesTransactionsDescription,
+		BuildingType:        row.PricesTransactionsType,
+		Category:            row.PricesTransactionsCategory,
+		Area:                row.PricesTransactionsArea,
+		Price:               row.PricesTransactionsPrice,
+		PricePerSquareMeter: row.PricesTransactionsPricePerSquareMeter,
+		BuildYear:           row.PricesTransactionsBuildYear,
+		Floor:               row.PricesTransactionsFloor,
+		Elevator:            row.PricesTransactionsElevator,
+		Condition:           row.PricesTransactionsCondition,
+		Plot:                row.PricesTransactionsPlot,
+		EnergyClass:         row.PricesTransactionsEnergyClass,
+		PeriodIdentifier:    row.PricesTransactionsPeriodIdentifier,
+		NeighborhoodID:      util.FromUUID(row.PricesNeighborhoodsID),
+		NeighborhoodName:    row.PricesNeighborhoodsName,
+		PostalCode:          row.PricesPostalCodesCode,
+		CityName:            row.PricesCitiesName,
+	}
+}
+
+// transactionCursor is the sort value and ID of the last row on a page, with
+// the sort and order the page was listed in.
+type transactionCursor struct {
+	Sort  string
+	Order string
+	Value int32
+	ID    pgtype.UUID
+}
+
+func encodeTransactionCursor(cursor transactionCursor) string {
+	raw := fmt.Sprintf("%s:%s:%d:%s", cursor.Sort, cursor.Order, cursor.Value, util.FromUUID(cursor.ID))
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTransactionCursor(encoded string) (transactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return transactionCursor{}, errInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 {
+		return transactionCursor{}, errInvalidCursor
+	}
+	value, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return transactionCursor{}, errInvalidCursor
+	}
+	id, err := uuid.Parse(parts[3])
+	if err != nil {
+		return transactionCursor{}, errInvalidCursor
+	}
+	return transactionCursor{
+		Sort:  parts[0],
+		Order: parts[1],
+		Value: int32(value),
+		ID:    util.ToUUID(id),
+	}, nil
+}
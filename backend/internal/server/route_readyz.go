@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"koditon-go/internal/taskqueue"
+)
+
+const readinessTimeout = 5 * time.Second
+
+const (
+	componentStatusOK    = "ok"
+	componentStatusError = "error"
+)
+
+type ComponentStatus struct {
+	Name    string `json:"name"`
+	Status  string `json:"status" enum:"ok,error"`
+	Message string `json:"message,omitempty"`
+}
+
+type WorkerReadiness struct {
+	ID         string     `json:"id"`
+	Queue      string     `json:"queue"`
+	Status     string     `json:"status" enum:"ok,error"`
+	LastPollAt *time.Time `json:"last_poll_at,omitempty"`
+	BusySince  *time.Time `json:"busy_since,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status     string            `json:"status" enum:"ready,not_ready"`
+	Components []ComponentStatus `json:"components"`
+	Workers    []WorkerReadiness `json:"workers"`
+}
+
+type readyzOutput struct {
+	Status int
+	Body   ReadinessResponse
+}
+
+// readyzHandler reports whether the instance can serve traffic and process
+// tasks. Unlike healthz it fails when any dependency or worker is down.
+func (s *Server) readyzHandler(ctx context.Context, _ *struct{}) (*readyzOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	var statuses []taskqueue.WorkerStatus
+	if s.workers != nil {
+		statuses = s.workers.WorkerStatuses()
+	}
+	out := ReadinessResponse{
+		Components: []ComponentStatus{
+			s.checkDatabase(ctx),
+			s.checkTaskQueues(ctx, statuses),
+			s.checkCronJobs(ctx),
+		},
+		Workers: []WorkerReadiness{},
+	}
+	workers := ComponentStatus{Name: "workers", Status: componentStatusOK}
+	stalled := 0
+	for _, w := range statuses {
+		status := componentStatusOK
+		if !w.Live {
+			status = componentStatusError
+			stalled++
+		}
+		out.Workers = append(out.Workers, WorkerReadiness{
+			ID:         w.WorkerID,
+			Queue:      w.Queue,
+			Status:     status,
+			LastPollAt: w.LastPollAt,
+			BusySince:  w.BusySince,
+		})
+	}
+	switch {
+	case len(statuses) == 0:
+		workers.Status = componentStatusError
+		workers.Message = "no workers running"
+	case stalled > 0:
+		workers.Status = componentStatusError
+		workers.Message = fmt.Sprintf("%d of %d workers have not polled recently", stalled, len(statuses))
+	}
+	out.Components = append(out.Components, workers)
+	out.Status = "ready"
+	status := http.StatusOK
+	for _, c := range out.Components {
+		if c.Status != componentStatusOK {
+			out.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			s.logger.WarnContext(ctx, "readiness check failed", "component", c.Name, "message", c.Message)
+		}
+	}
+	return &readyzOutput{Status: status, Body: out}, nil
+}
+
+func (s *Server) checkDatabase(ctx context.Context) ComponentStatus {
+	c := ComponentStatus{Name: "database", Status: componentStatusOK}
+	if s.pool == nil {
+		c.Status = componentStatusError
+		c.Message = "no database pool"
+		return c
+	}
+	if err := s.pool.Ping(ctx); err != nil {
+		c.Status = componentStatusError
+		c.Message = err.Error()
+	}
+	return c
+}
+
+// checkTaskQueues checks that the shared task queue and the queues the
+// workers read exist.
+func (s *Server) checkTaskQueues(ctx context.Context, statuses []taskqueue.WorkerStatus) ComponentStatus {
+	c := ComponentStatus{Name: "pgmq", Status: componentStatusOK}
+	queues := []string{taskqueue.QueueName}
+	for _, w := range statuses {
+		if w.Queue != "" && !slices.Contains(queues, w.Queue) {
+			queues = append(queues, w.Queue)
+		}
+	}
+	var missing []string
+	for _, queue := range queues {
+		exists, err := s.taskQueue.QueueExists(ctx, queue)
+		if err != nil {
+			c.Status = componentStatusError
+			c.Message = err.Error()
+			return c
+		}
+		if !exists {
+			missing = append(missing, queue)
+		}
+	}
+	if len(missing) > 0 {
+		c.Status = componentStatusError
+		c.Message = fmt.Sprintf("queues do not exist: %s", strings.Join(missing, ", "))
+	}
+	return c
+}
+
+func (s *Server) checkCronJobs(ctx context.Context) ComponentStatus {
+	c := ComponentStatus{Name: "pg_cron", Status: componentStatusOK}
+	jobs, err := s.taskQueue.ListCronJobs(ctx)
+	if err != nil {
+		c.Status = componentStatusError
+		c.Message = err.Error()
+		return c
+	}
+	var missing, inactive []string
+	for _, name := range taskqueue.CronJobNames {
+		active, ok := jobs[name]
+		switch {
+		case !ok:
+			missing = append(missing, name)
+		case !active:
+			inactive = append(inactive, name)
+		}
+	}
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing jobs: "+strings.Join(missing, ", "))
+	}
+	if len(inactive) > 0 {
+		problems = append(problems, "inactive jobs: "+strings.Join(inactive, ", "))
+	}
+	if len(problems) > 0 {
+		c.Status = componentStatusError
+		c.Message = strings.Join(problems, "; ")
+	}
+	return c
+}
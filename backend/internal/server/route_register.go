@@ -7,9 +7,201 @@ func (s *Server) addRoutes(api huma.API) {
 		op.OperationID = "healthz"
 		op.Summary = "Health check"
 	})
+	huma.Get(api, "/readyz", s.readyzHandler, func(op *huma.Operation) {
+		op.OperationID = "readyz"
+		op.Summary = "Readiness check of the database, queue, cron jobs and workers"
+	})
 	huma.Post(api, "/api/v1/ping", s.pingHandler, func(op *huma.Operation) {
 		op.OperationID = "ping"
 		op.Summary = "Echo a message"
 	})
+	huma.Get(api, "/api/v1/prices/cities", s.listCitiesHandler, func(op *huma.Operation) {
+		op.OperationID = "listCities"
+		op.Summary = "List cities with their neighborhoods and postal codes"
+	})
+	huma.Get(api, "/api/v1/prices/cities/{id}/neighborhoods", s.listCityNeighborhoodsHandler, func(op *huma.Operation) {
+		op.OperationID = "listCityNeighborhoods"
+		op.Summary = "List neighborhoods of a city"
+	})
+	huma.Get(api, "/api/v1/prices/transactions", s.listTransactionsHandler, func(op *huma.Operation) {
+		op.OperationID = "listTransactions"
+		op.Summary = "Search price transactions"
+	})
+	huma.Get(api, "/api/v1/prices/statistics", s.priceStatisticsHandler, func(op *huma.Operation) {
+		op.OperationID = "getPriceStatistics"
+		op.Summary = "Price per square meter statistics by neighborhood and period"
+	})
+	huma.Get(api, "/api/v1/listings", s.listListingsHandler, func(op *huma.Operation) {
+		op.OperationID = "listListings"
+		op.Summary = "Search frontdoor and shortcut listings"
+	})
+	huma.Get(api, "/api/v1/listings/{id}/timeline", s.listingTimelineHandler, func(op *huma.Operation) {
+		op.OperationID = "getListingTimeline"
+		op.Summary = "Price and status history of an ad with its time on market"
+	})
+	huma.Get(api, "/api/v1/buildings/geo", s.searchBuildingsHandler, func(op *huma.Operation) {
+		op.OperationID = "searchBuildings"
+		op.Summary = "Buildings within a bounding box or radius as GeoJSON"
+	})
+	huma.Post(api, "/api/v1/buildings/geo/polygon", s.searchBuildingsByPolygonHandler, func(op *huma.Operation) {
+		op.OperationID = "searchBuildingsByPolygon"
+		op.Summary = "Buildings inside a GeoJSON polygon as GeoJSON"
+	})
+	huma.Get(api, "/api/v1/buildings/{id}", s.getBuildingHandler, func(op *huma.Operation) {
+		op.OperationID = "getBuilding"
+		op.Summary = "Building facts merged from frontdoor and shortcut, with listings and announcements"
+	})
+	huma.Get(api, "/api/v1/saved-searches", s.listSavedSearchesHandler, func(op *huma.Operation) {
+		op.OperationID = "listSavedSearches"
+		op.Summary = "List saved searches, newest first"
+	})
+	huma.Post(api, "/api/v1/saved-searches", s.createSavedSearchHandler, func(op *huma.Operation) {
+		op.OperationID = "createSavedSearch"
+		op.Summary = "Save a search to be alerted of new matching listings"
+	})
+	huma.Get(api, "/api/v1/saved-searches/{id}", s.getSavedSearchHandler, func(op *huma.Operation) {
+		op.OperationID = "getSavedSearch"
+		op.Summary = "Get a saved search"
+	})
+	huma.Put(api, "/api/v1/saved-searches/{id}", s.updateSavedSearchHandler, func(op *huma.Operation) {
+		op.OperationID = "updateSavedSearch"
+		op.Summary = "Replace a saved search"
+	})
+	huma.Delete(api, "/api/v1/saved-searches/{id}", s.deleteSavedSearchHandler, func(op *huma.Operation) {
+		op.OperationID = "deleteSavedSearch"
+		op.Summary = "Delete a saved search and its alerts"
+	})
+	huma.Get(api, "/api/v1/saved-searches/{id}/alerts", s.listSavedSearchAlertsHandler, func(op *huma.Operation) {
+		op.OperationID = "listSavedSearchAlerts"
+		op.Summary = "List the alerts of a saved search, newest first"
+	})
+	huma.Get(api, "/api/v1/admin/stats", s.adminStatsHandler, func(op *huma.Operation) {
+		op.OperationID = "getAdminStats"
+		op.Summary = "Queue, task and sync statistics"
+	})
+	huma.Get(api, "/api/v1/admin/circuit-breakers", s.listCircuitBreakersHandler, func(op *huma.Operation) {
+		op.OperationID = "listCircuitBreakers"
+		op.Summary = "State of the circuit breaker of every source"
+	})
+	huma.Get(api, "/api/v1/admin/tasks", s.listTasksHandler, func(op *huma.Operation) {
+		op.OperationID = "listTasks"
+		op.Summary = "List tasks"
+	})
+	huma.Post(api, "/api/v1/admin/tasks", s.runTaskHandler, func(op *huma.Operation) {
+		op.OperationID = "runTask"
+		op.Summary = "Create a task and run it immediately"
+	})
+	huma.Get(api, "/api/v1/admin/tasks/{id}", s.getTaskHandler, func(op *huma.Operation) {
+		op.OperationID = "getTask"
+		op.Summary = "Get a task"
+	})
+	huma.Post(api, "/api/v1/admin/tasks/{id}/cancel", s.cancelTaskHandler, func(op *huma.Operation) {
+		op.OperationID = "cancelTask"
+		op.Summary = "Cancel a pending task"
+	})
+	huma.Get(api, "/api/v1/admin/dlq", s.listDLQEntriesHandler, func(op *huma.Operation) {
+		op.OperationID = "listDLQEntries"
+		op.Summary = "List dead letter queue entries"
+	})
+	huma.Get(api, "/api/v1/admin/dlq/stats", s.dlqStatsHandler, func(op *huma.Operation) {
+		op.OperationID = "getDLQStats"
+		op.Summary = "Dead letter queue statistics"
+	})
+	huma.Post(api, "/api/v1/admin/dlq/requeue", s.bulkRequeueDLQHandler, func(op *huma.Operation) {
+		op.OperationID = "bulkRequeueDLQEntries"
+		op.Summary = "Requeue a batch of matching dead letter queue entries"
+	})
+	huma.Post(api, "/api/v1/admin/dlq/delete", s.bulkDeleteDLQHandler, func(op *huma.Operation) {
+		op.OperationID = "bulkDeleteDLQEntries"
+		op.Summary = "Delete matching dead letter queue entries"
+	})
+	huma.Get(api, "/api/v1/admin/dlq/{id}", s.getDLQEntryHandler, func(op *huma.Operation) {
+		op.OperationID = "getDLQEntry"
+		op.Summary = "Get a dead letter queue entry"
+	})
+	huma.Post(api, "/api/v1/admin/dlq/{id}/requeue", s.requeueDLQEntryHandler, func(op *huma.Operation) {
+		op.OperationID = "requeueDLQEntry"
+		op.Summary = "Requeue a dead letter queue entry"
+	})
+	huma.Delete(api, "/api/v1/admin/dlq/{id}", s.deleteDLQEntryHandler, func(op *huma.Operation) {
+		op.OperationID = "deleteDLQEntry"
+		op.Summary = "Delete a dead letter queue entry"
+	})
+	huma.Get(api, "/api/v1/admin/entities", s.listEntitiesHandler, func(op *huma.Operation) {
+		op.OperationID = "listEntities"
+		op.Summary = "List registered entities"
+	})
+	huma.Post(api, "/api/v1/admin/entities", s.registerEntityHandler, func(op *huma.Operation) {
+		op.OperationID = "registerEntity"
+		op.Summary = "Register an entity for syncing"
+	})
+	huma.Get(api, "/api/v1/admin/entities/{id}", s.getEntityHandler, func(op *huma.Operation) {
+		op.OperationID = "getEntity"
+		op.Summary = "Get an entity"
+	})
+	huma.Get(api, "/api/v1/admin/entities/{id}/history", s.entityHistoryHandler, func(op *huma.Operation) {
+		op.OperationID = "getEntityHistory"
+		op.Summary = "Sync status and recent tasks of an entity"
+	})
+	huma.Post(api, "/api/v1/admin/entities/{id}/pause", s.pauseEntityHandler, func(op *huma.Operation) {
+		op.OperationID = "pauseEntity"
+		op.Summary = "Stop scheduling syncs for an entity"
+	})
+	huma.Post(api, "/api/v1/admin/entities/{id}/resume", s.resumeEntityHandler, func(op *huma.Operation) {
+		op.OperationID = "resumeEntity"
+		op.Summary = "Resume scheduling syncs for an entity"
+	})
+	huma.Put(api, "/api/v1/admin/entities/{id}/scheduling-strategy", s.updateEntityStrategyHandler, func(op *huma.Operation) {
+		op.OperationID = "updateEntitySchedulingStrategy"
+		op.Summary = "Change the scheduling strategy of an entity"
+	})
+	huma.Get(api, "/api/v1/admin/building-links", s.listBuildingLinksHandler, func(op *huma.Operation) {
+		op.OperationID = "listBuildingLinks"
+		op.Summary = "List frontdoor and shortcut building links, weakest first"
+	})
+	huma.Put(api, "/api/v1/admin/building-links", s.setBuildingLinkHandler, func(op *huma.Operation) {
+		op.OperationID = "setBuildingLink"
+		op.Summary = "Manually link or reject a frontdoor and shortcut building pair"
+	})
+	huma.Delete(api, "/api/v1/admin/building-links/{id}", s.deleteBuildingLinkHandler, func(op *huma.Operation) {
+		op.OperationID = "deleteBuildingLink"
+		op.Summary = "Delete a building link, letting the matcher decide the pair again"
+	})
+	huma.Get(api, "/api/v1/admin/sitemap-crawls", s.listSitemapCrawlsHandler, func(op *huma.Operation) {
+		op.OperationID = "listSitemapCrawls"
+		op.Summary = "List sitemap crawls with their added and removed counts, newest first"
+	})
+	huma.Get(api, "/api/v1/admin/sitemap-crawls/{id}/changes", s.listSitemapCrawlChangesHandler, func(op *huma.Operation) {
+		op.OperationID = "listSitemapCrawlChanges"
+		op.Summary = "List the entities a sitemap crawl found added or removed"
+	})
+	huma.Get(api, "/api/v1/admin/webhooks", s.listWebhookSubscriptionsHandler, func(op *huma.Operation) {
+		op.OperationID = "listWebhookSubscriptions"
+		op.Summary = "List webhook subscriptions, newest first"
+	})
+	huma.Post(api, "/api/v1/admin/webhooks", s.createWebhookSubscriptionHandler, func(op *huma.Operation) {
+		op.OperationID = "createWebhookSubscription"
+		op.Summary = "Subscribe a URL to events, returning its signing secret"
+	})
+	huma.Get(api, "/api/v1/admin/webhooks/{id}", s.getWebhookSubscriptionHandler, func(op *huma.Operation) {
+		op.OperationID = "getWebhookSubscription"
+		op.Summary = "Get a webhook subscription"
+	})
+	huma.Put(api, "/api/v1/admin/webhooks/{id}", s.updateWebhookSubscriptionHandler, func(op *huma.Operation) {
+		op.OperationID = "updateWebhookSubscription"
+		op.Summary = "Replace a webhook subscription, keeping its secret"
+	})
+	huma.Delete(api, "/api/v1/admin/webhooks/{id}", s.deleteWebhookSubscriptionHandler, func(op *huma.Operation) {
+		op.OperationID = "deleteWebhookSubscription"
+		op.Summary = "Delete a webhook subscription and its deliveries"
+	})
+	huma.Get(api, "/api/v1/admin/webhooks/{id}/deliveries", s.listWebhookDeliveriesHandler, func(op *huma.Operation) {
+		op.OperationID = "listWebhookDeliveries"
+		op.Summary = "List the deliveries of a webhook subscription, newest first"
+	})
+	huma.Get(api, "/api/v1/admin/webhook-deliveries/{id}", s.getWebhookDeliveryHandler, func(op *huma.Operation) {
+		op.OperationID = "getWebhookDelivery"
+		op.Summary = "Get a webhook delivery with its attempt log"
+	})
 
 }
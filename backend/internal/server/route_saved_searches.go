@@ -0,0 +1,336 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"koditon-go/internal/alerts"
+	alertsdb "koditon-go/internal/alerts/db"
+	"koditon-go/internal/util"
+)
+
+type SavedSearch struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Source          *string    `json:"source,omitempty" enum:"frontdoor,shortcut"`
+	City            *string    `json:"city,omitempty"`
+	Postcodes       []string   `json:"postcodes"`
+	MinPrice        *float64   `json:"min_price,omitempty"`
+	MaxPrice        *float64   `json:"max_price,omitempty"`
+	MinRooms        *int32     `json:"min_rooms,omitempty"`
+	MaxRooms        *int32     `json:"max_rooms,omitempty"`
+	MinArea         *float64   `json:"min_area,omitempty"`
+	MaxArea         *float64   `json:"max_area,omitempty"`
+	Notifier        string     `json:"notifier" enum:"webhook"`
+	Target          string     `json:"target"`
+	Enabled         bool       `json:"enabled"`
+	LastEvaluatedAt *time.Time `json:"last_evaluated_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type SavedSearchRequest struct {
+	Name      string   `json:"name" minLength:"1" maxLength:"200"`
+	Source    string   `json:"source,omitempty" enum:"frontdoor,shortcut"`
+	City      string   `json:"city,omitempty" maxLength:"100" doc:"Matched against the postcodes of the city"`
+	Postcodes []string `json:"postcodes,omitempty" maxItems:"100"`
+	MinPrice  *float64 `json:"min_price,omitempty" minimum:"0"`
+	MaxPrice  *float64 `json:"max_price,omitempty" minimum:"0"`
+	MinRooms  *int32   `json:"min_rooms,omitempty" minimum:"0"`
+	MaxRooms  *int32   `json:"max_rooms,omitempty" minimum:"0"`
+	MinArea   *float64 `json:"min_area,omitempty" minimum:"0"`
+	MaxArea   *float64 `json:"max_area,omitempty" minimum:"0"`
+	Notifier  string   `json:"notifier,omitempty" enum:"webhook" default:"webhook"`
+	Target    string   `json:"target" maxLength:"2000" doc:"Where alerts are delivered, the ID of a webhook subscription for webhooks"`
+	Enabled   *bool    `json:"enabled,omitempty" doc:"Defaults to true"`
+}
+
+type SavedSearchAlert struct {
+	ID          string          `json:"id"`
+	ListingID   string          `json:"listing_id"`
+	Listing     json.RawMessage `json:"listing" doc:"The listing as it was when it matched"`
+	Status      string          `json:"status" enum:"pending,delivered,failed"`
+	Attempts    int32           `json:"attempts"`
+	LastError   *string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	DeliveredAt *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type listSavedSearchesInput struct {
+	Limit  int32 `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset int32 `query:"offset" minimum:"0"`
+}
+
+type listSavedSearchesOutput struct {
+	Body []SavedSearch
+}
+
+type savedSearchIDInput struct {
+	ID string `path:"id" format:"uuid"`
+}
+
+type createSavedSearchInput struct {
+	Body SavedSearchRequest
+}
+
+type updateSavedSearchInput struct {
+	ID   string `path:"id" format:"uuid"`
+	Body SavedSearchRequest
+}
+
+type savedSearchOutput struct {
+	Body SavedSearch
+}
+
+type listSavedSearchAlertsInput struct {
+	ID     string `path:"id" format:"uuid"`
+	Status string `query:"status" enum:"pending,delivered,failed"`
+	Limit  int32  `query:"limit" minimum:"1" maximum:"500" default:"50"`
+	Offset int32  `query:"offset" minimum:"0"`
+}
+
+type listSavedSearchAlertsOutput struct {
+	Body []SavedSearchAlert
+}
+
+func (s *Server) listSavedSearchesHandler(ctx context.Context, input *listSavedSearchesInput) (*listSavedSearchesOutput, error) {
+	searches, err := s.alertsQueries.ListSavedSearches(ctx, &alertsdb.ListSavedSearchesParams{
+		PageSize:   input.Limit,
+		PageOffset: input.Offset,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list saved searches", "error", err)
+		return nil, huma.Error500InternalServerError("failed to list saved searches")
+	}
+	out := make([]SavedSearch, 0, len(searches))
+	for _, search := range searches {
+		out = append(out, mapSavedSearch(search))
+	}
+	return &listSavedSearchesOutput{Body: out}, nil
+}
+
+func (s *Server) createSavedSearchHandler(ctx context.Context, input *createSavedSearchInput) (*savedSearchOutput, error) {
+	params, err := mapSavedSearchParams(input.Body)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err := s.checkSavedSearchTarget(ctx, params.SavedSearchesNotifier, params.SavedSearchesTarget); err != nil {
+		return nil, err
+	}
+	search, err := s.alertsQueries.CreateSavedSearch(ctx, params)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to create saved search", "error", err)
+		return nil, huma.Error500InternalServerError("failed to create saved search")
+	}
+	s.logger.InfoContext(ctx, "saved search created", "saved_search_id", util.FromUUID(search.SavedSearchesID))
+	return &savedSearchOutput{Body: mapSavedSearch(search)}, nil
+}
+
+func (s *Server) getSavedSearchHandler(ctx context.Context, input *savedSearchIDInput) (*savedSearchOutput, error) {
+	id, err := parseSavedSearchID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	search, err := s.alertsQueries.GetSavedSearch(ctx, id)
+	if err != nil {
+		return nil, s.savedSearchError(ctx, input.ID, err)
+	}
+	return &savedSearchOutput{Body: mapSavedSearch(search)}, nil
+}
+
+func (s *Server) updateSavedSearchHandler(ctx context.Context, input *updateSavedSearchInput) (*savedSearchOutput, error) {
+	id, err := parseSavedSearchID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	params, err := mapSavedSearchParams(input.Body)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err := s.checkSavedSearchTarget(ctx, params.SavedSearchesNotifier, params.SavedSearchesTarget); err != nil {
+		return nil, err
+	}
+	search, err := s.alertsQueries.UpdateSavedSearch(ctx, &alertsdb.UpdateSavedSearchParams{
+		SavedSearchesID:        id,
+		SavedSearchesName:      params.SavedSearchesName,
+		SavedSearchesSource:    params.SavedSearchesSource,
+		SavedSearchesCity:      params.SavedSearchesCity,
+		SavedSearchesPostcodes: params.SavedSearchesPostcodes,
+		SavedSearchesMinPrice:  params.SavedSearchesMinPrice,
+		SavedSearchesMaxPrice:  params.SavedSearchesMaxPrice,
+		SavedSearchesMinRooms:  params.SavedSearchesMinRooms,
+		SavedSearchesMaxRooms:  params.SavedSearchesMaxRooms,
+		SavedSearchesMinArea:   params.SavedSearchesMinArea,
+		SavedSearchesMaxArea:   params.SavedSearchesMaxArea,
+		SavedSearchesNotifier:  params.SavedSearchesNotifier,
+		SavedSearchesTarget:    params.SavedSearchesTarget,
+		SavedSearchesEnabled:   params.SavedSearchesEnabled,
+	})
+	if err != nil {
+		return nil, s.savedSearchError(ctx, input.ID, err)
+	}
+	s.logger.InfoContext(ctx, "saved search updated", "saved_search_id", input.ID)
+	return &savedSearchOutput{Body: mapSavedSearch(search)}, nil
+}
+
+func (s *Server) deleteSavedSearchHandler(ctx context.Context, input *savedSearchIDInput) (*struct{}, error) {
+	id, err := parseSavedSearchID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.alertsQueries.DeleteSavedSearch(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete saved search", "saved_search_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to delete saved search")
+	}
+	if deleted == 0 {
+		return nil, huma.Error404NotFound(fmt.Sprintf("saved search %s not found", input.ID))
+	}
+	s.logger.InfoContext(ctx, "saved search deleted", "saved_search_id", input.ID)
+	return nil, nil
+}
+
+func (s *Server) listSavedSearchAlertsHandler(ctx context.Context, input *listSavedSearchAlertsInput) (*listSavedSearchAlertsOutput, error) {
+	id, err := parseSavedSearchID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.alertsQueries.GetSavedSearch(ctx, id); err != nil {
+		return nil, s.savedSearchError(ctx, input.ID, err)
+	}
+	alerts, err := s.alertsQueries.ListSavedSearchAlerts(ctx, &alertsdb.ListSavedSearchAlertsParams{
+		SavedSearchID: id,
+		Status:        util.ToStringPtr(input.Status),
+		PageSize:      input.Limit,
+		PageOffset:    input.Offset,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list saved search alerts", "saved_search_id", input.ID, "error", err)
+		return nil, huma.Error500InternalServerError("failed to list saved search alerts")
+	}
+	out := make([]SavedSearchAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		out = append(out, mapSavedSearchAlert(alert))
+	}
+	return &listSavedSearchAlertsOutput{Body: out}, nil
+}
+
+// checkSavedSearchTarget makes sure the target of the notifier exists, for
+// webhooks the subscription the alerts are queued for.
+func (s *Server) checkSavedSearchTarget(ctx context.Context, notifier, target string) error {
+	if notifier != alerts.NotifierWebhook {
+		return nil
+	}
+	id, err := uuid.Parse(target)
+	if err != nil {
+		return huma.Error400BadRequest("target must be a webhook subscription id")
+	}
+	if _, err := s.webhooksQueries.GetWebhookSubscription(ctx, util.ToUUID(id)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return huma.Error400BadRequest(fmt.Sprintf("webhook subscription %s not found", target))
+		}
+		s.logger.ErrorContext(ctx, "failed to get webhook subscription", "subscription_id", target, "error", err)
+		return huma.Error500InternalServerError("failed to check saved search target")
+	}
+	return nil
+}
+
+func (s *Server) savedSearchError(ctx context.Context, id string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return huma.Error404NotFound(fmt.Sprintf("saved search %s not found", id))
+	}
+	s.logger.ErrorContext(ctx, "saved search query failed", "saved_search_id", id, "error", err)
+	return huma.Error500InternalServerError("failed to get saved search")
+}
+
+func parseSavedSearchID(raw string) (pgtype.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return pgtype.UUID{}, huma.Error400BadRequest(fmt.Sprintf("invalid saved search id: %s", raw))
+	}
+	return util.ToUUID(id), nil
+}
+
+func mapSavedSearchParams(req SavedSearchRequest) (*alertsdb.CreateSavedSearchParams, error) {
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return nil, errors.New("min_price must not exceed max_price")
+	}
+	if req.MinRooms != nil && req.MaxRooms != nil && *req.MinRooms > *req.MaxRooms {
+		return nil, errors.New("min_rooms must not exceed max_rooms")
+	}
+	if req.MinArea != nil && req.MaxArea != nil && *req.MinArea > *req.MaxArea {
+		return nil, errors.New("min_area must not exceed max_area")
+	}
+	notifier := req.Notifier
+	if notifier == "" {
+		notifier = alerts.NotifierWebhook
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return &alertsdb.CreateSavedSearchParams{
+		SavedSearchesName:      util.NormalizeString(req.Name),
+		SavedSearchesSource:    util.ToStringPtr(req.Source),
+		SavedSearchesCity:      util.ToStringPtr(util.NormalizeString(req.City)),
+		SavedSearchesPostcodes: util.UniqueStrings(req.Postcodes),
+		SavedSearchesMinPrice:  req.MinPrice,
+		SavedSearchesMaxPrice:  req.MaxPrice,
+		SavedSearchesMinRooms:  req.MinRooms,
+		SavedSearchesMaxRooms:  req.MaxRooms,
+		SavedSearchesMinArea:   req.MinArea,
+		SavedSearchesMaxArea:   req.MaxArea,
+		SavedSearchesNotifier:  notifier,
+		SavedSearchesTarget:    req.Target,
+		SavedSearchesEnabled:   enabled,
+	}, nil
+}
+
+func mapSavedSearch(search alertsdb.SavedSearch) SavedSearch {
+	out := SavedSearch{
+		ID:        util.FromUUID(search.SavedSearchesID),
+		Name:      search.SavedSearchesName,
+		Source:    search.SavedSearchesSource,
+		City:      search.SavedSearchesCity,
+		Postcodes: search.SavedSearchesPostcodes,
+		MinPrice:  search.SavedSearchesMinPrice,
+		MaxPrice:  search.SavedSearchesMaxPrice,
+		MinRooms:  search.SavedSearchesMinRooms,
+		MaxRooms:  search.SavedSearchesMaxRooms,
+		MinArea:   search.SavedSearchesMinArea,
+		MaxArea:   search.SavedSearchesMaxArea,
+		Notifier:  search.SavedSearchesNotifier,
+		Target:    search.SavedSearchesTarget,
+		Enabled:   search.SavedSearchesEnabled,
+		CreatedAt: search.SavedSearchesCreatedAt,
+		UpdatedAt: search.SavedSearchesUpdatedAt,
+	}
+	if search.SavedSearchesLastEvaluatedAt.Valid {
+		out.LastEvaluatedAt = &search.SavedSearchesLastEvaluatedAt.Time
+	}
+	return out
+}
+
+func mapSavedSearchAlert(alert alertsdb.SavedSearchOutbox) SavedSearchAlert {
+	out := SavedSearchAlert{
+		ID:        util.FromUUID(alert.SavedSearchOutboxID),
+		ListingID: alert.SavedSearchOutboxListingID,
+		Listing:   alert.SavedSearchOutboxPayload,
+		Status:    alert.SavedSearchOutboxStatus,
+		Attempts:  alert.SavedSearchOutboxAttempts,
+		LastError: alert.SavedSearchOutboxLastError,
+		CreatedAt: alert.SavedSearchOutboxCreatedAt,
+	}
+	if alert.SavedSearchOutboxDeliveredAt.Valid {
+		out.DeliveredAt = &alert.SavedSearchOutboxDeliveredAt.Time
+	}
+	return out
+}
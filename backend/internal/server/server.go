@@ -9,45 +9,95 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	alertsdb "koditon-go/internal/alerts/db"
+	buildingsdb "koditon-go/internal/buildings/db"
 	"koditon-go/internal/config"
 	frontdoorclient "koditon-go/internal/frontdoor/client"
+	frontdoordb "koditon-go/internal/frontdoor/db"
+	listingsdb "koditon-go/internal/listings/db"
 	pricesclient "koditon-go/internal/prices/client"
 	pricesdb "koditon-go/internal/prices/db"
 	shortcutclient "koditon-go/internal/shortcut/client"
 	shortcutdb "koditon-go/internal/shortcut/db"
+	"koditon-go/internal/sources"
 	"koditon-go/internal/taskqueue"
+	"koditon-go/internal/transport"
+	webhooksdb "koditon-go/internal/webhooks/db"
 )
 
+// WorkerMonitor reports the liveness of the task queue workers and the
+// circuit breakers guarding them.
+type WorkerMonitor interface {
+	WorkerStatuses() []taskqueue.WorkerStatus
+	Breakers() []taskqueue.BreakerStatus
+}
+
+// Limiters are the rate limiters of the sources. The workers and the server
+// share them, so that the requests of both count against the same limits.
+type Limiters struct {
+	Prices    *transport.Limiter
+	Shortcut  *transport.Limiter
+	Frontdoor *transport.Limiter
+}
+
+// NewLimiters returns a limiter for each source with its configured limits.
+func NewLimiters(cfg config.Config) Limiters {
+	return Limiters{
+		Prices:    transport.NewLimiter(pricesclient.SourceName, cfg.Prices.Limits.TransportLimits()),
+		Shortcut:  transport.NewLimiter(shortcutclient.SourceName, cfg.Shortcut.Limits.TransportLimits()),
+		Frontdoor: transport.NewLimiter(frontdoorclient.SourceName, cfg.Frontdoor.Limits.TransportLimits()),
+	}
+}
+
+func (l Limiters) stats() []transport.HostStats {
+	return transport.Stats(l.Prices, l.Shortcut, l.Frontdoor)
+}
+
 type Server struct {
-	logger        *slog.Logger
-	cfg           config.Config
-	pricesQueries *pricesdb.Queries
-	pricesAPI     *pricesclient.Client
-	taskQueue     *taskqueue.Client
-	shortcutAPI   *shortcutclient.Client
-	frontdoorAPI  *frontdoorclient.Client
+	logger           *slog.Logger
+	cfg              config.Config
+	pool             *pgxpool.Pool
+	workers          WorkerMonitor
+	registry         *sources.Registry
+	limiters         Limiters
+	pricesQueries    *pricesdb.Queries
+	pricesAPI        *pricesclient.Client
+	listingsQueries  *listingsdb.Queries
+	buildingsQueries *buildingsdb.Queries
+	alertsQueries    *alertsdb.Queries
+	webhooksQueries  *webhooksdb.Queries
+	taskQueue        *taskqueue.Client
+	shortcutQueries  *shortcutdb.Queries
+	shortcutAPI      *shortcutclient.Client
+	frontdoorQueries *frontdoordb.Queries
+	frontdoorAPI     *frontdoorclient.Client
 }
 
-func New(logger *slog.Logger, cfg config.Config, pool *pgxpool.Pool, taskQueueClient *taskqueue.Client) *Server {
+func New(logger *slog.Logger, cfg config.Config, pool *pgxpool.Pool, taskQueueClient *taskqueue.Client, workers WorkerMonitor, registry *sources.Registry, limiters Limiters) *Server {
 	pricesQueries := pricesdb.New(pool)
 	shortcutQueries := shortcutdb.New(pool)
 
-	pricesClient, _ := pricesclient.NewClient(cfg.Prices.BaseURL)
+	pricesClient, _ := pricesclient.NewClient(cfg.Prices.BaseURL, limiters.Prices)
 
-	tokenLoad := func(ctx context.Context) (*shortcutclient.Tokens, error) {
-		dbToken, err := shortcutQueries.GetValidShortcutToken(ctx)
+	tokenLoad := func(ctx context.Context, limit int) ([]shortcutclient.PooledTokens, error) {
+		dbTokens, err := shortcutQueries.GetAllValidShortcutTokens(ctx, int32(limit))
 		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return nil, errors.New("no valid token found")
-			}
 			return nil, err
 		}
-		tokens := &shortcutclient.Tokens{
-			CUID:   dbToken.ShortcutTokensCuid,
-			Token:  dbToken.ShortcutTokensToken,
-			Loaded: dbToken.ShortcutTokensLoaded,
+		tokens := make([]shortcutclient.PooledTokens, 0, len(dbTokens))
+		for _, dbToken := range dbTokens {
+			tokens = append(tokens, shortcutclient.PooledTokens{
+				Tokens: shortcutclient.Tokens{
+					CUID:   dbToken.ShortcutTokensCuid,
+					Token:  dbToken.ShortcutTokensToken,
+					Loaded: dbToken.ShortcutTokensLoaded,
+				},
+				Failures:         int(dbToken.ShortcutTokensFailureCount),
+				QuarantinedUntil: dbToken.ShortcutTokensQuarantinedUntil.Time,
+			})
 		}
 		return tokens, nil
 	}
@@ -60,35 +110,84 @@ func New(logger *slog.Logger, cfg config.Config, pool *pgxpool.Pool, taskQueueCl
 		})
 		return err
 	}
+	tokenHealth := func(ctx context.Context, tokens *shortcutclient.PooledTokens) error {
+		return shortcutQueries.UpdateShortcutTokenHealth(ctx, &shortcutdb.UpdateShortcutTokenHealthParams{
+			FailureCount:     int32(tokens.Failures),
+			QuarantinedUntil: pgtype.Timestamptz{Time: tokens.QuarantinedUntil, Valid: !tokens.QuarantinedUntil.IsZero()},
+			Dead:             tokens.Dead,
+			Cuid:             tokens.CUID,
+		})
+	}
 	shortcutClient := shortcutclient.NewClient(
 		logger,
 		tokenLoad,
 		tokenStore,
+		tokenHealth,
+		cfg.Shortcut.TokenPool.ClientConfig(),
 		cfg.Shortcut.BaseURL,
 		cfg.Shortcut.DocsBaseURL,
 		cfg.Shortcut.AdBaseURL,
 		cfg.Shortcut.UserAgent,
 		cfg.Shortcut.SitemapBase,
+		limiters.Shortcut,
 	)
+	frontdoorQueries := frontdoordb.New(pool)
+	sessionLoad := func(ctx context.Context) (*frontdoorclient.Session, error) {
+		dbSession, err := frontdoorQueries.GetFrontdoorSession(ctx)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errors.New("no valid session found")
+			}
+			return nil, err
+		}
+		return &frontdoorclient.Session{
+			Cookie:    dbSession.FrontdoorSessionsCookie,
+			Origin:    frontdoorclient.SessionOrigin(dbSession.FrontdoorSessionsOrigin),
+			ExpiresAt: dbSession.FrontdoorSessionsExpiresAt.Time,
+		}, nil
+	}
+	sessionStore := func(ctx context.Context, session *frontdoorclient.Session) error {
+		_, err := frontdoorQueries.InsertFrontdoorSession(ctx, &frontdoordb.InsertFrontdoorSessionParams{
+			FrontdoorSessionsCookie:    session.Cookie,
+			FrontdoorSessionsOrigin:    string(session.Origin),
+			FrontdoorSessionsExpiresAt: pgtype.Timestamptz{Time: session.ExpiresAt, Valid: true},
+		})
+		return err
+	}
 	frontdoorClient := frontdoorclient.New(
+		logger,
+		sessionLoad,
+		sessionStore,
 		cfg.Frontdoor.BaseURL,
 		cfg.Frontdoor.UserAgent,
 		cfg.Frontdoor.Cookie,
 		cfg.Frontdoor.SitemapBase,
+		limiters.Frontdoor,
 	)
 	return &Server{
-		logger:        logger.With("component", "server"),
-		cfg:           cfg,
-		pricesQueries: pricesQueries,
-		pricesAPI:     pricesClient,
-		taskQueue:     taskQueueClient,
-		shortcutAPI:   shortcutClient,
-		frontdoorAPI:  frontdoorClient,
+		logger:           logger.With("component", "server"),
+		cfg:              cfg,
+		pool:             pool,
+		workers:          workers,
+		registry:         registry,
+		limiters:         limiters,
+		pricesQueries:    pricesQueries,
+		pricesAPI:        pricesClient,
+		listingsQueries:  listingsdb.New(pool),
+		buildingsQueries: buildingsdb.New(pool),
+		alertsQueries:    alertsdb.New(pool),
+		webhooksQueries:  webhooksdb.New(pool),
+		taskQueue:        taskQueueClient,
+		shortcutQueries:  shortcutQueries,
+		shortcutAPI:      shortcutClient,
+		frontdoorQueries: frontdoorQueries,
+		frontdoorAPI:     frontdoorClient,
 	}
 }
 
 func (s *Server) Handler(mux *http.ServeMux, api huma.API) http.Handler {
 	s.addRoutes(api)
+	mux.HandleFunc("GET /metrics", s.metricsHandler)
 	var handler http.Handler = mux
 	handler = s.loggingMiddleware(handler)
 	return handler
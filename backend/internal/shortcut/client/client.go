@@ -14,13 +14,14 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"sync"
 	"time"
 
-	"golang.org/x/sync/singleflight"
+	"koditon-go/internal/transport"
 )
 
 const (
+	// SourceName labels the requests of the client in the transport stats.
+	SourceName            = "shortcut"
 	defaultTokenExpiry    = 365 * 24 * time.Hour // 1 year - rely on 401 for actual expiry
 	defaultRequestTimeout = 30 * time.Second
 )
@@ -34,6 +35,9 @@ var (
 type HTTPStatusError struct {
 	StatusCode int
 	Body       string
+	// RetryAfter is the delay the response asked for, zero when it had no
+	// Retry-After.
+	RetryAfter time.Duration
 }
 
 func (e *HTTPStatusError) Error() string {
@@ -54,18 +58,10 @@ type Tokens struct {
 	Token  string
 }
 
-type TokenStore func(ctx context.Context, tokens *Tokens, expiresAt time.Time) error
-type TokenLoader func(ctx context.Context) (*Tokens, error)
-
 type Client struct {
-	httpClient *http.Client
-	logger     *slog.Logger
-	tokenStore TokenStore
-	tokenLoad  TokenLoader
-
-	mu                 sync.RWMutex
-	cachedTokens       *Tokens
-	tokenFetchGroup    singleflight.Group
+	httpClient         *http.Client
+	logger             *slog.Logger
+	tokens             *tokenPool
 	tokenExpiry        time.Duration
 	requestTimeout     time.Duration
 	userAgent          string
@@ -77,11 +73,11 @@ type Client struct {
 	sitemapBaseURL     string
 }
 
-func NewClient(logger *slog.Logger, tokenLoad TokenLoader, tokenStore TokenStore, baseURL, docsBaseURL, adBaseURL, userAgent, sitemapBaseURL string) *Client {
+func NewClient(logger *slog.Logger, tokenLoad TokenLoader, tokenStore TokenStore, tokenHealth TokenHealthStore, pool TokenPoolConfig, baseURL, docsBaseURL, adBaseURL, userAgent, sitemapBaseURL string, limiter *transport.Limiter) *Client {
 	if logger == nil {
 		logger = slog.Default()
 	}
-	transport := &http.Transport{
+	baseTransport := &http.Transport{
 		MaxIdleConns:          100,
 		MaxIdleConnsPerHost:   20,
 		MaxConnsPerHost:       20,
@@ -98,13 +94,11 @@ func NewClient(logger *slog.Logger, tokenLoad TokenLoader, tokenStore TokenStore
 		}).DialContext,
 	}
 	httpClient := &http.Client{
-		Transport: transport,
+		Transport: transport.New(limiter, baseTransport),
 	}
-	return &Client{
+	c := &Client{
 		httpClient:         httpClient,
 		logger:             logger.With("component", "shortcut-client"),
-		tokenLoad:          tokenLoad,
-		tokenStore:         tokenStore,
 		tokenExpiry:        defaultTokenExpiry,
 		requestTimeout:     defaultRequestTimeout,
 		userAgent:          userAgent,
@@ -115,6 +109,8 @@ func NewClient(logger *slog.Logger, tokenLoad TokenLoader, tokenStore TokenStore
 		refererURL:         joinURL(baseURL, "/myytavat-asunnot"),
 		sitemapBaseURL:     sitemapBaseURL,
 	}
+	c.tokens = newTokenPool(c, pool, tokenLoad, tokenStore, tokenHealth)
+	return c
 }
 
 func defaultTokenRandom(context.Context) (string, error) {
@@ -125,60 +121,6 @@ func defaultTokenRandom(context.Context) (string, error) {
 	return n.String(), nil
 }
 
-func (c *Client) getValidTokens(ctx context.Context) (*Tokens, error) {
-	c.mu.RLock()
-	if c.cachedTokens != nil {
-		cached := c.cachedTokens
-		c.mu.RUnlock()
-		return cached, nil
-	}
-	c.mu.RUnlock()
-	v, err, _ := c.tokenFetchGroup.Do("fetch-token", func() (any, error) {
-		tokenCtx := context.Background()
-		if deadline, ok := ctx.Deadline(); ok {
-			var cancel context.CancelFunc
-			tokenCtx, cancel = context.WithDeadline(context.Background(), deadline)
-			defer cancel()
-		}
-		c.mu.Lock()
-		defer c.mu.Unlock()
-		if c.cachedTokens != nil {
-			return c.cachedTokens, nil
-		}
-		if c.tokenLoad != nil {
-			tokens, err := c.tokenLoad(tokenCtx)
-			if err == nil {
-				c.cachedTokens = tokens
-				return tokens, nil
-			}
-		}
-		return c.refreshTokens(tokenCtx)
-	})
-	if err != nil {
-		return nil, err
-	}
-	return v.(*Tokens), nil
-}
-
-func (c *Client) refreshTokens(ctx context.Context) (*Tokens, error) {
-	tokens, err := c.createAnonymousUser(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("create anonymous user: %w", err)
-	}
-	expiresAt := time.Now().Add(c.tokenExpiry)
-	if c.tokenStore != nil {
-		_ = c.tokenStore(ctx, tokens, expiresAt)
-	}
-	c.cachedTokens = tokens
-	return tokens, nil
-}
-
-func (c *Client) invalidateTokens() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.cachedTokens = nil
-}
-
 func (c *Client) createAnonymousUser(ctx context.Context) (*Tokens, error) {
 	randomValue, err := c.tokenRefreshRandom(ctx)
 	if err != nil {
@@ -213,7 +155,7 @@ func (c *Client) createAnonymousUser(ctx context.Context) (*Tokens, error) {
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
 		_, _ = io.Copy(io.Discard, resp.Body)
-		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: bytesTrim(body)}
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: bytesTrim(body), RetryAfter: transport.RetryAfter(resp)}
 	}
 	var tokenResponse struct {
 		User struct {
@@ -278,13 +220,12 @@ func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Valu
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 		_, _ = io.Copy(io.Discard, resp.Body)
-		c.invalidateTokens()
 		return fmt.Errorf("%w: %d: %s", ErrAuthFailed, resp.StatusCode, bytesTrim(body))
 	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 		_, _ = io.Copy(io.Discard, resp.Body)
-		return &HTTPStatusError{StatusCode: resp.StatusCode, Body: bytesTrim(body)}
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Body: bytesTrim(body), RetryAfter: transport.RetryAfter(resp)}
 	}
 	if target == nil {
 		return nil
@@ -295,22 +236,28 @@ func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Valu
 	return nil
 }
 
+// doRequestWithRetry performs the request with the next tokens of the pool.
+// Tokens the API rejects are quarantined and the request is retried once
+// with the tokens after them.
 func (c *Client) doRequestWithRetry(ctx context.Context, endpoint string, params url.Values, target any) error {
-	tokens, err := c.getValidTokens(ctx)
-	if err != nil {
-		return fmt.Errorf("get valid tokens: %w", err)
-	}
-	err = c.doRequest(ctx, endpoint, params, tokens, target)
-	if err != nil && errors.Is(err, ErrAuthFailed) {
-		c.mu.Lock()
-		newTokens, refreshErr := c.refreshTokens(ctx)
-		c.mu.Unlock()
-		if refreshErr != nil {
-			return fmt.Errorf("refresh tokens after auth failure: %w", refreshErr)
+	for attempt := 0; ; attempt++ {
+		tokens, err := c.tokens.acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("get valid tokens: %w", err)
+		}
+		err = c.doRequest(ctx, endpoint, params, &tokens.Tokens, target)
+		if errors.Is(err, ErrAuthFailed) {
+			c.tokens.reject(ctx, tokens)
+			if attempt == 0 {
+				continue
+			}
+			return err
+		}
+		if err == nil {
+			c.tokens.succeed(ctx, tokens)
 		}
-		err = c.doRequest(ctx, endpoint, params, newTokens, target)
+		return err
 	}
-	return err
 }
 
 func bytesTrim(b []byte) string {
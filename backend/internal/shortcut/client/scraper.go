@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"koditon-go/internal/transport"
 )
 
 var (
@@ -119,10 +121,7 @@ func (c *Client) GetAllAdPages(ctx context.Context) ([]SitemapEntry, error) {
 }
 
 func (c *Client) getAllSitemapPages(ctx context.Context, t SitemapType) ([]SitemapEntry, error) {
-	const (
-		maxFailedAttempts = 3
-		sleepBetween      = time.Second
-	)
+	const maxFailedAttempts = 3
 	base := joinURL(c.sitemapBaseURL, fmt.Sprintf("/sitemaps/sm_%s_", t))
 	var (
 		results []SitemapEntry
@@ -158,7 +157,7 @@ func (c *Client) getAllSitemapPages(ctx context.Context, t SitemapType) ([]Sitem
 			continue
 		}
 		if resp.StatusCode != http.StatusOK {
-			lastErr = &HTTPStatusError{StatusCode: resp.StatusCode, Body: ""}
+			lastErr = &HTTPStatusError{StatusCode: resp.StatusCode, Body: "", RetryAfter: transport.RetryAfter(resp)}
 			failed++
 			continue
 		}
@@ -173,7 +172,6 @@ func (c *Client) getAllSitemapPages(ctx context.Context, t SitemapType) ([]Sitem
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
-		time.Sleep(sleepBetween)
 	}
 	if failed >= maxFailedAttempts && lastErr != nil {
 		return results, lastErr
@@ -201,7 +199,7 @@ func (c *Client) fetchHTML(ctx context.Context, url string) ([]byte, error) {
 		return nil, ErrScraperForbidden
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: ""}
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: "", RetryAfter: transport.RetryAfter(resp)}
 	}
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
 	if err != nil {
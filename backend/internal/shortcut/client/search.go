@@ -50,5 +50,8 @@ func (c *Client) SearchApartments(ctx context.Context, params SearchParams) (*Se
 	if err := c.doRequestWithRetry(ctx, cardsURL, q, &result); err != nil {
 		return nil, fmt.Errorf("fetch cards: %w", err)
 	}
+	for i := range result.Cards {
+		result.Cards[i].URL = joinURL(c.baseURL, result.Cards[i].URL)
+	}
 	return &result, nil
 }
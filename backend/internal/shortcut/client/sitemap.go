@@ -10,7 +10,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
+
+	"koditon-go/internal/transport"
 )
 
 type SitemapURLType string
@@ -28,11 +29,14 @@ type ShortcutSitemapEntry struct {
 }
 
 // GetSitemapEntries fetches and parses sitemap entries for the Shortcut site (ads, rentals, buildings).
-func (c *Client) GetSitemapEntries(ctx context.Context) ([]ShortcutSitemapEntry, error) {
+// The errors of the sitemaps that could not be fetched are returned next to
+// the entries of the others, the call fails only when none of them could be
+// fetched.
+func (c *Client) GetSitemapEntries(ctx context.Context) ([]ShortcutSitemapEntry, []error, error) {
 	indexURL := joinURL(c.sitemapBaseURL, "/sitemaps/index.xml")
 	indexXML, err := c.fetchSitemapXML(ctx, indexURL)
 	if err != nil {
-		return nil, fmt.Errorf("fetch sitemap index: %w", err)
+		return nil, nil, fmt.Errorf("fetch sitemap index: %w", err)
 	}
 	var indexURLs []string
 	for _, loc := range extractLocs(indexXML) {
@@ -53,12 +57,11 @@ func (c *Client) GetSitemapEntries(ctx context.Context) ([]ShortcutSitemapEntry,
 				entries = append(entries, *entry)
 			}
 		}
-		time.Sleep(100 * time.Millisecond)
 	}
 	if len(entries) == 0 && len(fetchErrors) > 0 {
-		return nil, fmt.Errorf("all sitemap fetches failed: %w", errors.Join(fetchErrors...))
+		return nil, nil, fmt.Errorf("all sitemap fetches failed: %w", errors.Join(fetchErrors...))
 	}
-	return entries, nil
+	return entries, fetchErrors, nil
 }
 
 func parseShortcutEntry(raw string) (*ShortcutSitemapEntry, bool) {
@@ -121,7 +124,7 @@ func (c *Client) fetchSitemapXML(ctx context.Context, url string) (string, error
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return "", &HTTPStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+		return "", &HTTPStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body)), RetryAfter: transport.RetryAfter(resp)}
 	}
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
 	if err != nil {
@@ -0,0 +1,286 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// tokenGrowBackoff is how long a pool that could not create a token waits
+// before asking the API for another one.
+const tokenGrowBackoff = time.Minute
+
+var ErrNoTokens = errors.New("shortcut: every token is quarantined")
+
+// TokenPoolConfig sizes the token pool. Requests rotate over Size tokens. A
+// token the API rejects is quarantined for Quarantine times its consecutive
+// failures and given up on after MaxFailures.
+type TokenPoolConfig struct {
+	Size        int
+	MaxFailures int
+	Quarantine  time.Duration
+}
+
+func DefaultTokenPoolConfig() TokenPoolConfig {
+	return TokenPoolConfig{
+		Size:        3,
+		MaxFailures: 3,
+		Quarantine:  5 * time.Minute,
+	}
+}
+
+// PooledTokens are the tokens of an anonymous user in the pool with their
+// health.
+type PooledTokens struct {
+	Tokens
+	Failures         int
+	QuarantinedUntil time.Time
+	Dead             bool
+}
+
+type TokenStore func(ctx context.Context, tokens *Tokens, expiresAt time.Time) error
+type TokenLoader func(ctx context.Context, limit int) ([]PooledTokens, error)
+type TokenHealthStore func(ctx context.Context, tokens *PooledTokens) error
+
+// tokenPool hands out the tokens of the client round-robin. The pool is
+// loaded from the store and filled up with new anonymous users one at a time,
+// and concurrent requests share a single fill.
+type tokenPool struct {
+	client *Client
+	config TokenPoolConfig
+	load   TokenLoader
+	store  TokenStore
+	health TokenHealthStore
+	now    func() time.Time
+
+	mu     sync.Mutex
+	tokens []*PooledTokens
+	next   int
+	loaded bool
+	// growAfter backs off creating tokens after the API refused one
+	growAfter  time.Time
+	fetchGroup singleflight.Group
+}
+
+func newTokenPool(c *Client, config TokenPoolConfig, load TokenLoader, store TokenStore, health TokenHealthStore) *tokenPool {
+	defaults := DefaultTokenPoolConfig()
+	if config.Size < 1 {
+		config.Size = defaults.Size
+	}
+	if config.MaxFailures < 1 {
+		config.MaxFailures = defaults.MaxFailures
+	}
+	if config.Quarantine <= 0 {
+		config.Quarantine = defaults.Quarantine
+	}
+	return &tokenPool{
+		client: c,
+		config: config,
+		load:   load,
+		store:  store,
+		health: health,
+		now:    time.Now,
+	}
+}
+
+// acquire returns the next token of the rotation that is not quarantined,
+// filling the pool first when it holds fewer than Size tokens. When every
+// token is quarantined a new anonymous user is added rather than waiting the
+// quarantine out; the store hands out the newest tokens, so the pool shrinks
+// back to Size with the next reload.
+func (p *tokenPool) acquire(ctx context.Context) (*PooledTokens, error) {
+	p.mu.Lock()
+	short := !p.loaded || (len(p.tokens) < p.config.Size && !p.now().Before(p.growAfter))
+	p.mu.Unlock()
+	var fillErr error
+	if short {
+		if fillErr = p.fill(ctx, false); fillErr != nil {
+			p.client.logger.WarnContext(ctx, "failed to fill shortcut token pool", "error", fillErr)
+		}
+	}
+	if tokens := p.rotate(); tokens != nil {
+		return tokens, nil
+	}
+	p.mu.Lock()
+	canGrow := !p.now().Before(p.growAfter)
+	p.mu.Unlock()
+	if canGrow {
+		if fillErr = p.fill(ctx, true); fillErr != nil {
+			p.client.logger.WarnContext(ctx, "failed to grow shortcut token pool", "error", fillErr)
+		}
+		if tokens := p.rotate(); tokens != nil {
+			return tokens, nil
+		}
+	}
+	if fillErr != nil {
+		return nil, fillErr
+	}
+	return nil, ErrNoTokens
+}
+
+// rotate returns the next token of the rotation that is not quarantined, or
+// nil when there is none.
+func (p *tokenPool) rotate() *PooledTokens {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for range len(p.tokens) {
+		tokens := p.tokens[p.next%len(p.tokens)]
+		p.next = (p.next + 1) % len(p.tokens)
+		if !now.Before(tokens.QuarantinedUntil) {
+			return tokens
+		}
+	}
+	return nil
+}
+
+// fill reloads the pool from the store, picking up the tokens other processes
+// created, and creates a new anonymous user when it is still short. With grow
+// it also creates one when every token is quarantined.
+func (p *tokenPool) fill(ctx context.Context, grow bool) error {
+	key := "fill-pool"
+	if grow {
+		key = "grow-pool"
+	}
+	_, err, _ := p.fetchGroup.Do(key, func() (any, error) {
+		tokenCtx := context.Background()
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			tokenCtx, cancel = context.WithDeadline(context.Background(), deadline)
+			defer cancel()
+		}
+		if p.load != nil {
+			stored, err := p.load(tokenCtx, p.config.Size)
+			if err != nil {
+				p.client.logger.WarnContext(ctx, "failed to load shortcut tokens", "error", err)
+			} else {
+				p.replace(stored)
+			}
+		}
+		p.mu.Lock()
+		p.loaded = true
+		missing := len(p.tokens) < p.config.Size || (grow && !p.usable(p.now()))
+		p.mu.Unlock()
+		if !missing {
+			return nil, nil
+		}
+		tokens, err := p.client.createAnonymousUser(tokenCtx)
+		if err != nil {
+			p.mu.Lock()
+			p.growAfter = p.now().Add(tokenGrowBackoff)
+			p.mu.Unlock()
+			return nil, fmt.Errorf("create anonymous user: %w", err)
+		}
+		if p.store != nil {
+			if err := p.store(tokenCtx, tokens, p.now().Add(p.client.tokenExpiry)); err != nil {
+				p.client.logger.WarnContext(ctx, "failed to store shortcut token", "error", err)
+			}
+		}
+		p.mu.Lock()
+		p.tokens = append(p.tokens, &PooledTokens{Tokens: *tokens})
+		size := len(p.tokens)
+		p.mu.Unlock()
+		p.client.logger.InfoContext(ctx, "shortcut token added to pool", "size", size)
+		return nil, nil
+	})
+	return err
+}
+
+// replace swaps the tokens of the pool for the stored ones. The store holds
+// the health of every token, so nothing known only to this process is lost.
+func (p *tokenPool) replace(stored []PooledTokens) {
+	tokens := make([]*PooledTokens, 0, len(stored))
+	for i := range stored {
+		if !stored[i].Dead {
+			tokens = append(tokens, &stored[i])
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens = tokens
+	if len(tokens) > 0 {
+		p.next %= len(tokens)
+	} else {
+		p.next = 0
+	}
+}
+
+// reject records a request the API refused with the tokens. The tokens are
+// quarantined, for longer with every consecutive rejection, and dropped from
+// the pool once they reach MaxFailures.
+func (p *tokenPool) reject(ctx context.Context, tokens *PooledTokens) {
+	p.mu.Lock()
+	tokens.Failures++
+	tokens.QuarantinedUntil = p.now().Add(time.Duration(tokens.Failures) * p.config.Quarantine)
+	if tokens.Failures >= p.config.MaxFailures {
+		tokens.Dead = true
+		p.remove(tokens.CUID)
+	}
+	health := *tokens
+	p.mu.Unlock()
+	if health.Dead {
+		p.client.logger.WarnContext(ctx, "shortcut token given up", "failures", health.Failures)
+	} else {
+		p.client.logger.WarnContext(ctx, "shortcut token quarantined",
+			"failures", health.Failures,
+			"quarantined_until", health.QuarantinedUntil,
+		)
+	}
+	p.saveHealth(ctx, &health)
+}
+
+// succeed clears the failures of tokens the API accepted again.
+func (p *tokenPool) succeed(ctx context.Context, tokens *PooledTokens) {
+	p.mu.Lock()
+	if tokens.Failures == 0 {
+		p.mu.Unlock()
+		return
+	}
+	tokens.Failures = 0
+	tokens.QuarantinedUntil = time.Time{}
+	health := *tokens
+	p.mu.Unlock()
+	p.saveHealth(ctx, &health)
+}
+
+// usable reports whether any token of the pool is out of quarantine.
+func (p *tokenPool) usable(now time.Time) bool {
+	for _, tokens := range p.tokens {
+		if !now.Before(tokens.QuarantinedUntil) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *tokenPool) remove(cuid string) {
+	for i, tokens := range p.tokens {
+		if tokens.CUID == cuid {
+			p.tokens = append(p.tokens[:i], p.tokens[i+1:]...)
+			if p.next > i {
+				p.next--
+			}
+			if len(p.tokens) > 0 {
+				p.next %= len(p.tokens)
+			} else {
+				p.next = 0
+			}
+			return
+		}
+	}
+}
+
+// saveHealth persists the health of tokens. Health that cannot be stored is
+// still used by this process.
+func (p *tokenPool) saveHealth(ctx context.Context, tokens *PooledTokens) {
+	if p.health == nil {
+		return
+	}
+	if err := p.health(ctx, tokens); err != nil {
+		p.client.logger.WarnContext(ctx, "failed to store shortcut token health", "error", err)
+	}
+}
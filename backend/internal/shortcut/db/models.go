@@ -17,6 +17,19 @@ type ShortcutAd struct {
 	ShortcutAdsData        []byte             `db:"shortcut_ads_data" json:"shortcut_ads_data"`
 	ShortcutAdsUpdatedAt   pgtype.Timestamptz `db:"shortcut_ads_updated_at" json:"shortcut_ads_updated_at"`
 	ShortcutAdsBuildingID  pgtype.UUID        `db:"shortcut_ads_building_id" json:"shortcut_ads_building_id"`
+	ShortcutAdsDelistedAt  pgtype.Timestamptz `db:"shortcut_ads_delisted_at" json:"shortcut_ads_delisted_at"`
+}
+
+type ShortcutAdHistory struct {
+	ShortcutAdHistoryID                    pgtype.UUID        `db:"shortcut_ad_history_id" json:"shortcut_ad_history_id"`
+	ShortcutAdHistoryAdID                  int64              `db:"shortcut_ad_history_ad_id" json:"shortcut_ad_history_ad_id"`
+	ShortcutAdHistoryEvent                 string             `db:"shortcut_ad_history_event" json:"shortcut_ad_history_event"`
+	ShortcutAdHistoryPrice                 *float64           `db:"shortcut_ad_history_price" json:"shortcut_ad_history_price"`
+	ShortcutAdHistoryDebtFreePrice         *float64           `db:"shortcut_ad_history_debt_free_price" json:"shortcut_ad_history_debt_free_price"`
+	ShortcutAdHistoryPreviousPrice         *float64           `db:"shortcut_ad_history_previous_price" json:"shortcut_ad_history_previous_price"`
+	ShortcutAdHistoryPreviousDebtFreePrice *float64           `db:"shortcut_ad_history_previous_debt_free_price" json:"shortcut_ad_history_previous_debt_free_price"`
+	ShortcutAdHistoryStatus                *string            `db:"shortcut_ad_history_status" json:"shortcut_ad_history_status"`
+	ShortcutAdHistoryRecordedAt            pgtype.Timestamptz `db:"shortcut_ad_history_recorded_at" json:"shortcut_ad_history_recorded_at"`
 }
 
 type ShortcutBuilding struct {
@@ -46,7 +59,13 @@ type ShortcutBuilding struct {
 	ShortcutBuildingsPageNotFound            *bool              `db:"shortcut_buildings_page_not_found" json:"shortcut_buildings_page_not_found"`
 	ShortcutBuildingsFrameConstructionMethod *string            `db:"shortcut_buildings_frame_construction_method" json:"shortcut_buildings_frame_construction_method"`
 	ShortcutBuildingsHousingCompany          *string            `db:"shortcut_buildings_housing_company" json:"shortcut_buildings_housing_company"`
+	ShortcutBuildingsPostcode                *string            `db:"shortcut_buildings_postcode" json:"shortcut_buildings_postcode"`
 	ShortcutBuildingsGeom                    interface{}        `db:"shortcut_buildings_geom" json:"shortcut_buildings_geom"`
+	ShortcutBuildingsVrkID                   *string            `db:"shortcut_buildings_vrk_id" json:"shortcut_buildings_vrk_id"`
+	ShortcutBuildingsSizeMin                 *int32             `db:"shortcut_buildings_size_min" json:"shortcut_buildings_size_min"`
+	ShortcutBuildingsSizeMax                 *int32             `db:"shortcut_buildings_size_max" json:"shortcut_buildings_size_max"`
+	ShortcutBuildingsMedia                   []byte             `db:"shortcut_buildings_media" json:"shortcut_buildings_media"`
+	ShortcutBuildingsApiSyncedAt             pgtype.Timestamptz `db:"shortcut_buildings_api_synced_at" json:"shortcut_buildings_api_synced_at"`
 }
 
 type ShortcutBuildingListing struct {
@@ -77,11 +96,14 @@ type ShortcutBuildingRental struct {
 }
 
 type ShortcutToken struct {
-	ShortcutTokensID        pgtype.UUID        `db:"shortcut_tokens_id" json:"shortcut_tokens_id"`
-	ShortcutTokensCuid      string             `db:"shortcut_tokens_cuid" json:"shortcut_tokens_cuid"`
-	ShortcutTokensToken     string             `db:"shortcut_tokens_token" json:"shortcut_tokens_token"`
-	ShortcutTokensLoaded    string             `db:"shortcut_tokens_loaded" json:"shortcut_tokens_loaded"`
-	ShortcutTokensCreatedAt pgtype.Timestamptz `db:"shortcut_tokens_created_at" json:"shortcut_tokens_created_at"`
-	ShortcutTokensUpdatedAt pgtype.Timestamptz `db:"shortcut_tokens_updated_at" json:"shortcut_tokens_updated_at"`
-	ShortcutTokensExpiresAt pgtype.Timestamptz `db:"shortcut_tokens_expires_at" json:"shortcut_tokens_expires_at"`
+	ShortcutTokensID               pgtype.UUID        `db:"shortcut_tokens_id" json:"shortcut_tokens_id"`
+	ShortcutTokensCuid             string             `db:"shortcut_tokens_cuid" json:"shortcut_tokens_cuid"`
+	ShortcutTokensToken            string             `db:"shortcut_tokens_token" json:"shortcut_tokens_token"`
+	ShortcutTokensLoaded           string             `db:"shortcut_tokens_loaded" json:"shortcut_tokens_loaded"`
+	ShortcutTokensCreatedAt        pgtype.Timestamptz `db:"shortcut_tokens_created_at" json:"shortcut_tokens_created_at"`
+	ShortcutTokensUpdatedAt        pgtype.Timestamptz `db:"shortcut_tokens_updated_at" json:"shortcut_tokens_updated_at"`
+	ShortcutTokensExpiresAt        pgtype.Timestamptz `db:"shortcut_tokens_expires_at" json:"shortcut_tokens_expires_at"`
+	ShortcutTokensFailureCount     int32              `db:"shortcut_tokens_failure_count" json:"shortcut_tokens_failure_count"`
+	ShortcutTokensQuarantinedUntil pgtype.Timestamptz `db:"shortcut_tokens_quarantined_until" json:"shortcut_tokens_quarantined_until"`
+	ShortcutTokensDeadAt           pgtype.Timestamptz `db:"shortcut_tokens_dead_at" json:"shortcut_tokens_dead_at"`
 }
@@ -79,6 +79,28 @@ ON CONFLICT (shortcut_buildings_external_id) DO UPDATE SET
     shortcut_buildings_updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
+-- name: UpdateShortcutBuildingFromAPI :exec
+-- Stores the structured data of the building API. Fields the API leaves out
+-- keep the values the scraper found. The elevator and sauna flags are written
+-- in the Kyllä/Ei form the scraper stores them in.
+UPDATE public.shortcut_buildings
+SET shortcut_buildings_vrk_id = COALESCE(sqlc.narg(vrk_id)::text, shortcut_buildings_vrk_id),
+    shortcut_buildings_construction_year = COALESCE(sqlc.narg(construction_year)::int4, shortcut_buildings_construction_year),
+    shortcut_buildings_floor_count = COALESCE(sqlc.narg(floor_count)::int4, shortcut_buildings_floor_count),
+    shortcut_buildings_apartment_count = COALESCE(sqlc.narg(apartment_count)::int4, shortcut_buildings_apartment_count),
+    shortcut_buildings_has_elevator = COALESCE(CASE sqlc.narg(has_elevator)::bool WHEN TRUE THEN 'Kyllä' WHEN FALSE THEN 'Ei' END, shortcut_buildings_has_elevator),
+    shortcut_buildings_has_sauna = COALESCE(CASE sqlc.narg(has_sauna)::bool WHEN TRUE THEN 'Kyllä' WHEN FALSE THEN 'Ei' END, shortcut_buildings_has_sauna),
+    shortcut_buildings_size_min = sqlc.narg(size_min)::int4,
+    shortcut_buildings_size_max = sqlc.narg(size_max)::int4,
+    shortcut_buildings_media = sqlc.narg(media)::jsonb,
+    shortcut_buildings_latitude = COALESCE(sqlc.narg(latitude)::float8, shortcut_buildings_latitude),
+    shortcut_buildings_longitude = COALESCE(sqlc.narg(longitude)::float8, shortcut_buildings_longitude),
+    shortcut_buildings_address = COALESCE(sqlc.narg(address)::text, shortcut_buildings_address),
+    shortcut_buildings_postcode = COALESCE(sqlc.narg(postcode)::text, shortcut_buildings_postcode),
+    shortcut_buildings_api_synced_at = CURRENT_TIMESTAMP,
+    shortcut_buildings_updated_at = CURRENT_TIMESTAMP
+WHERE shortcut_buildings_id = sqlc.arg(id);
+
 -- name: MarkShortcutBuildingProcessed :exec
 UPDATE public.shortcut_buildings
 SET shortcut_buildings_processed_at = CURRENT_TIMESTAMP, shortcut_buildings_updated_at = CURRENT_TIMESTAMP
@@ -113,10 +135,79 @@ ON CONFLICT (shortcut_ads_id) DO UPDATE SET
     shortcut_ads_type = EXCLUDED.shortcut_ads_type,
     shortcut_ads_data = EXCLUDED.shortcut_ads_data,
     shortcut_ads_building_id = EXCLUDED.shortcut_ads_building_id,
+    shortcut_ads_delisted_at = CASE WHEN EXCLUDED.shortcut_ads_data IS NULL THEN shortcut_ads.shortcut_ads_delisted_at END,
     shortcut_ads_last_seen_at = now(),
     shortcut_ads_updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
+-- name: RecordShortcutSearchAd :one
+-- Records an ad found by the area search. The data of the ad is left to the
+-- API sync; inserted tells whether the ad was not known before.
+SELECT public.fnc__record_shortcut_search_ad(
+    sqlc.arg(ad_id)::bigint,
+    sqlc.arg(url)::text,
+    sqlc.arg(ad_type)::text
+)::boolean AS inserted;
+
+-- name: MarkShortcutAdDelisted :exec
+UPDATE public.shortcut_ads
+SET shortcut_ads_delisted_at = COALESCE(shortcut_ads_delisted_at, now()),
+    shortcut_ads_updated_at = CURRENT_TIMESTAMP
+WHERE shortcut_ads_id = $1;
+
+-- name: DelistShortcutAds :execrows
+WITH delisted AS (
+    UPDATE public.shortcut_ads
+    SET shortcut_ads_delisted_at = now(),
+        shortcut_ads_updated_at = CURRENT_TIMESTAMP
+    WHERE shortcut_ads_id = ANY(sqlc.arg('ad_ids')::bigint[])
+      AND shortcut_ads_delisted_at IS NULL
+    RETURNING shortcut_ads_id
+)
+INSERT INTO public.shortcut_ad_history (
+    shortcut_ad_history_ad_id,
+    shortcut_ad_history_event,
+    shortcut_ad_history_price,
+    shortcut_ad_history_debt_free_price,
+    shortcut_ad_history_previous_price,
+    shortcut_ad_history_previous_debt_free_price,
+    shortcut_ad_history_status
+)
+SELECT d.shortcut_ads_id, 'delisted', h.shortcut_ad_history_price, h.shortcut_ad_history_debt_free_price,
+    h.shortcut_ad_history_price, h.shortcut_ad_history_debt_free_price, h.shortcut_ad_history_status
+FROM delisted d
+LEFT JOIN LATERAL (
+    SELECT * FROM public.shortcut_ad_history
+    WHERE shortcut_ad_history_ad_id = d.shortcut_ads_id
+    ORDER BY shortcut_ad_history_recorded_at DESC
+    LIMIT 1
+) h ON true
+WHERE h.shortcut_ad_history_event IS DISTINCT FROM 'delisted';
+
+-- name: GetLatestShortcutAdHistory :one
+SELECT * FROM public.shortcut_ad_history
+WHERE shortcut_ad_history_ad_id = $1
+ORDER BY shortcut_ad_history_recorded_at DESC
+LIMIT 1;
+
+-- name: InsertShortcutAdHistory :exec
+INSERT INTO public.shortcut_ad_history (
+    shortcut_ad_history_ad_id,
+    shortcut_ad_history_event,
+    shortcut_ad_history_price,
+    shortcut_ad_history_debt_free_price,
+    shortcut_ad_history_previous_price,
+    shortcut_ad_history_previous_debt_free_price,
+    shortcut_ad_history_status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ListShortcutAdHistory :many
+SELECT * FROM public.shortcut_ad_history
+WHERE shortcut_ad_history_ad_id = $1
+ORDER BY shortcut_ad_history_recorded_at ASC;
+
 -- name: GetShortcutBuildingListingsByBuildingID :many
 SELECT * FROM public.shortcut_building_listings
 WHERE shortcut_building_listings_building_id = $1
@@ -158,14 +249,12 @@ ON CONFLICT (shortcut_building_rentals_building_id, shortcut_building_rentals_la
     shortcut_building_rentals_updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
--- name: GetValidShortcutToken :one
-SELECT * FROM public.shortcut_tokens
-ORDER BY shortcut_tokens_created_at DESC
-LIMIT 1;
-
 -- name: GetAllValidShortcutTokens :many
 SELECT * FROM public.shortcut_tokens
-ORDER BY shortcut_tokens_created_at DESC;
+WHERE shortcut_tokens_expires_at > NOW()
+  AND shortcut_tokens_dead_at IS NULL
+ORDER BY shortcut_tokens_created_at DESC
+LIMIT $1;
 
 -- name: InsertShortcutToken :one
 INSERT INTO public.shortcut_tokens (
@@ -186,3 +275,11 @@ RETURNING *;
 -- name: DeleteShortcutToken :exec
 DELETE FROM public.shortcut_tokens
 WHERE shortcut_tokens_cuid = $1;
+
+-- name: UpdateShortcutTokenHealth :exec
+UPDATE public.shortcut_tokens
+SET shortcut_tokens_failure_count = sqlc.arg(failure_count),
+    shortcut_tokens_quarantined_until = sqlc.arg(quarantined_until),
+    shortcut_tokens_dead_at = CASE WHEN sqlc.arg(dead)::boolean THEN NOW() END,
+    shortcut_tokens_updated_at = NOW()
+WHERE shortcut_tokens_cuid = sqlc.arg(cuid);
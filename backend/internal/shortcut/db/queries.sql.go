@@ -22,13 +22,54 @@ func (q *Queries) DeleteShortcutToken(ctx context.Context, shortcutTokensCuid st
 	return err
 }
 
+const delistShortcutAds = `-- name: DelistShortcutAds :execrows
+WITH delisted AS (
+    UPDATE public.shortcut_ads
+    SET shortcut_ads_delisted_at = now(),
+        shortcut_ads_updated_at = CURRENT_TIMESTAMP
+    WHERE shortcut_ads_id = ANY($1::bigint[])
+      AND shortcut_ads_delisted_at IS NULL
+    RETURNING shortcut_ads_id
+)
+INSERT INTO public.shortcut_ad_history (
+    shortcut_ad_history_ad_id,
+    shortcut_ad_history_event,
+    shortcut_ad_history_price,
+    shortcut_ad_history_debt_free_price,
+    shortcut_ad_history_previous_price,
+    shortcut_ad_history_previous_debt_free_price,
+    shortcut_ad_history_status
+)
+SELECT d.shortcut_ads_id, 'delisted', h.shortcut_ad_history_price, h.shortcut_ad_history_debt_free_price,
+    h.shortcut_ad_history_price, h.shortcut_ad_history_debt_free_price, h.shortcut_ad_history_status
+FROM delisted d
+LEFT JOIN LATERAL (
+    SELECT * FROM public.shortcut_ad_history
+    WHERE shortcut_ad_history_ad_id = d.shortcut_ads_id
+    ORDER BY shortcut_ad_history_recorded_at DESC
+    LIMIT 1
+) h ON true
+WHERE h.shortcut_ad_history_event IS DISTINCT FROM 'delisted'
+`
+
+func (q *Queries) DelistShortcutAds(ctx context.Context, adIds []int64) (int64, error) {
+	result, err := q.db.Exec(ctx, delistShortcutAds, adIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getAllValidShortcutTokens = `-- name: GetAllValidShortcutTokens :many
-SELECT shortcut_tokens_id, shortcut_tokens_cuid, shortcut_tokens_token, shortcut_tokens_loaded, shortcut_tokens_created_at, shortcut_tokens_updated_at, shortcut_tokens_expires_at FROM public.shortcut_tokens
+SELECT shortcut_tokens_id, shortcut_tokens_cuid, shortcut_tokens_token, shortcut_tokens_loaded, shortcut_tokens_created_at, shortcut_tokens_updated_at, shortcut_tokens_expires_at, shortcut_tokens_failure_count, shortcut_tokens_quarantined_until, shortcut_tokens_dead_at FROM public.shortcut_tokens
+WHERE shortcut_tokens_expires_at > NOW()
+  AND shortcut_tokens_dead_at IS NULL
 ORDER BY shortcut_tokens_created_at DESC
+LIMIT $1
 `
 
-func (q *Queries) GetAllValidShortcutTokens(ctx context.Context) ([]ShortcutToken, error) {
-	rows, err := q.db.Query(ctx, getAllValidShortcutTokens)
+func (q *Queries) GetAllValidShortcutTokens(ctx context.Context, limit int32) ([]ShortcutToken, error) {
+	rows, err := q.db.Query(ctx, getAllValidShortcutTokens, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +85,9 @@ func (q *Queries) GetAllValidShortcutTokens(ctx context.Context) ([]ShortcutToke
 			&i.ShortcutTokensCreatedAt,
 			&i.ShortcutTokensUpdatedAt,
 			&i.ShortcutTokensExpiresAt,
+			&i.ShortcutTokensFailureCount,
+			&i.ShortcutTokensQuarantinedUntil,
+			&i.ShortcutTokensDeadAt,
 		); err != nil {
 			return nil, err
 		}
@@ -55,8 +99,32 @@ func (q *Queries) GetAllValidShortcutTokens(ctx context.Context) ([]ShortcutToke
 	return items, nil
 }
 
+const getLatestShortcutAdHistory = `-- name: GetLatestShortcutAdHistory :one
+SELECT shortcut_ad_history_id, shortcut_ad_history_ad_id, shortcut_ad_history_event, shortcut_ad_history_price, shortcut_ad_history_debt_free_price, shortcut_ad_history_previous_price, shortcut_ad_history_previous_debt_free_price, shortcut_ad_history_status, shortcut_ad_history_recorded_at FROM public.shortcut_ad_history
+WHERE shortcut_ad_history_ad_id = $1
+ORDER BY shortcut_ad_history_recorded_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestShortcutAdHistory(ctx context.Context, shortcutAdHistoryAdID int64) (ShortcutAdHistory, error) {
+	row := q.db.QueryRow(ctx, getLatestShortcutAdHistory, shortcutAdHistoryAdID)
+	var i ShortcutAdHistory
+	err := row.Scan(
+		&i.ShortcutAdHistoryID,
+		&i.ShortcutAdHistoryAdID,
+		&i.ShortcutAdHistoryEvent,
+		&i.ShortcutAdHistoryPrice,
+		&i.ShortcutAdHistoryDebtFreePrice,
+		&i.ShortcutAdHistoryPreviousPrice,
+		&i.ShortcutAdHistoryPreviousDebtFreePrice,
+		&i.ShortcutAdHistoryStatus,
+		&i.ShortcutAdHistoryRecordedAt,
+	)
+	return i, err
+}
+
 const getShortcutAdByID = `-- name: GetShortcutAdByID :one
-SELECT shortcut_ads_id, shortcut_ads_url, shortcut_ads_type, shortcut_ads_first_seen_at, shortcut_ads_last_seen_at, shortcut_ads_data, shortcut_ads_updated_at, shortcut_ads_building_id FROM public.shortcut_ads
+SELECT shortcut_ads_id, shortcut_ads_url, shortcut_ads_type, shortcut_ads_first_seen_at, shortcut_ads_last_seen_at, shortcut_ads_data, shortcut_ads_updated_at, shortcut_ads_building_id, shortcut_ads_delisted_at FROM public.shortcut_ads
 WHERE shortcut_ads_id = $1
 `
 
@@ -72,12 +140,13 @@ func (q *Queries) GetShortcutAdByID(ctx context.Context, shortcutAdsID int64) (S
 		&i.ShortcutAdsData,
 		&i.ShortcutAdsUpdatedAt,
 		&i.ShortcutAdsBuildingID,
+		&i.ShortcutAdsDelistedAt,
 	)
 	return i, err
 }
 
 const getShortcutBuildingByExternalID = `-- name: GetShortcutBuildingByExternalID :one
-SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_geom FROM public.shortcut_buildings
+SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_postcode, shortcut_buildings_geom, shortcut_buildings_vrk_id, shortcut_buildings_size_min, shortcut_buildings_size_max, shortcut_buildings_media, shortcut_buildings_api_synced_at FROM public.shortcut_buildings
 WHERE shortcut_buildings_external_id = $1
 `
 
@@ -111,13 +180,19 @@ func (q *Queries) GetShortcutBuildingByExternalID(ctx context.Context, shortcutB
 		&i.ShortcutBuildingsPageNotFound,
 		&i.ShortcutBuildingsFrameConstructionMethod,
 		&i.ShortcutBuildingsHousingCompany,
+		&i.ShortcutBuildingsPostcode,
 		&i.ShortcutBuildingsGeom,
+		&i.ShortcutBuildingsVrkID,
+		&i.ShortcutBuildingsSizeMin,
+		&i.ShortcutBuildingsSizeMax,
+		&i.ShortcutBuildingsMedia,
+		&i.ShortcutBuildingsApiSyncedAt,
 	)
 	return i, err
 }
 
 const getShortcutBuildingByID = `-- name: GetShortcutBuildingByID :one
-SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_geom FROM public.shortcut_buildings
+SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_postcode, shortcut_buildings_geom, shortcut_buildings_vrk_id, shortcut_buildings_size_min, shortcut_buildings_size_max, shortcut_buildings_media, shortcut_buildings_api_synced_at FROM public.shortcut_buildings
 WHERE shortcut_buildings_id = $1
 `
 
@@ -151,7 +226,13 @@ func (q *Queries) GetShortcutBuildingByID(ctx context.Context, shortcutBuildings
 		&i.ShortcutBuildingsPageNotFound,
 		&i.ShortcutBuildingsFrameConstructionMethod,
 		&i.ShortcutBuildingsHousingCompany,
+		&i.ShortcutBuildingsPostcode,
 		&i.ShortcutBuildingsGeom,
+		&i.ShortcutBuildingsVrkID,
+		&i.ShortcutBuildingsSizeMin,
+		&i.ShortcutBuildingsSizeMax,
+		&i.ShortcutBuildingsMedia,
+		&i.ShortcutBuildingsApiSyncedAt,
 	)
 	return i, err
 }
@@ -231,25 +312,41 @@ func (q *Queries) GetShortcutBuildingRentalsByBuildingID(ctx context.Context, sh
 	return items, nil
 }
 
-const getValidShortcutToken = `-- name: GetValidShortcutToken :one
-SELECT shortcut_tokens_id, shortcut_tokens_cuid, shortcut_tokens_token, shortcut_tokens_loaded, shortcut_tokens_created_at, shortcut_tokens_updated_at, shortcut_tokens_expires_at FROM public.shortcut_tokens
-ORDER BY shortcut_tokens_created_at DESC
-LIMIT 1
+const insertShortcutAdHistory = `-- name: InsertShortcutAdHistory :exec
+INSERT INTO public.shortcut_ad_history (
+    shortcut_ad_history_ad_id,
+    shortcut_ad_history_event,
+    shortcut_ad_history_price,
+    shortcut_ad_history_debt_free_price,
+    shortcut_ad_history_previous_price,
+    shortcut_ad_history_previous_debt_free_price,
+    shortcut_ad_history_status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
 `
 
-func (q *Queries) GetValidShortcutToken(ctx context.Context) (ShortcutToken, error) {
-	row := q.db.QueryRow(ctx, getValidShortcutToken)
-	var i ShortcutToken
-	err := row.Scan(
-		&i.ShortcutTokensID,
-		&i.ShortcutTokensCuid,
-		&i.ShortcutTokensToken,
-		&i.ShortcutTokensLoaded,
-		&i.ShortcutTokensCreatedAt,
-		&i.ShortcutTokensUpdatedAt,
-		&i.ShortcutTokensExpiresAt,
+type InsertShortcutAdHistoryParams struct {
+	ShortcutAdHistoryAdID                  int64    `db:"shortcut_ad_history_ad_id" json:"shortcut_ad_history_ad_id"`
+	ShortcutAdHistoryEvent                 string   `db:"shortcut_ad_history_event" json:"shortcut_ad_history_event"`
+	ShortcutAdHistoryPrice                 *float64 `db:"shortcut_ad_history_price" json:"shortcut_ad_history_price"`
+	ShortcutAdHistoryDebtFreePrice         *float64 `db:"shortcut_ad_history_debt_free_price" json:"shortcut_ad_history_debt_free_price"`
+	ShortcutAdHistoryPreviousPrice         *float64 `db:"shortcut_ad_history_previous_price" json:"shortcut_ad_history_previous_price"`
+	ShortcutAdHistoryPreviousDebtFreePrice *float64 `db:"shortcut_ad_history_previous_debt_free_price" json:"shortcut_ad_history_previous_debt_free_price"`
+	ShortcutAdHistoryStatus                *string  `db:"shortcut_ad_history_status" json:"shortcut_ad_history_status"`
+}
+
+func (q *Queries) InsertShortcutAdHistory(ctx context.Context, arg *InsertShortcutAdHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertShortcutAdHistory,
+		arg.ShortcutAdHistoryAdID,
+		arg.ShortcutAdHistoryEvent,
+		arg.ShortcutAdHistoryPrice,
+		arg.ShortcutAdHistoryDebtFreePrice,
+		arg.ShortcutAdHistoryPreviousPrice,
+		arg.ShortcutAdHistoryPreviousDebtFreePrice,
+		arg.ShortcutAdHistoryStatus,
 	)
-	return i, err
+	return err
 }
 
 const insertShortcutToken = `-- name: InsertShortcutToken :one
@@ -266,7 +363,7 @@ ON CONFLICT (shortcut_tokens_cuid) DO UPDATE SET
     shortcut_tokens_loaded = EXCLUDED.shortcut_tokens_loaded,
     shortcut_tokens_expires_at = EXCLUDED.shortcut_tokens_expires_at,
     shortcut_tokens_updated_at = NOW()
-RETURNING shortcut_tokens_id, shortcut_tokens_cuid, shortcut_tokens_token, shortcut_tokens_loaded, shortcut_tokens_created_at, shortcut_tokens_updated_at, shortcut_tokens_expires_at
+RETURNING shortcut_tokens_id, shortcut_tokens_cuid, shortcut_tokens_token, shortcut_tokens_loaded, shortcut_tokens_created_at, shortcut_tokens_updated_at, shortcut_tokens_expires_at, shortcut_tokens_failure_count, shortcut_tokens_quarantined_until, shortcut_tokens_dead_at
 `
 
 type InsertShortcutTokenParams struct {
@@ -292,12 +389,51 @@ func (q *Queries) InsertShortcutToken(ctx context.Context, arg *InsertShortcutTo
 		&i.ShortcutTokensCreatedAt,
 		&i.ShortcutTokensUpdatedAt,
 		&i.ShortcutTokensExpiresAt,
+		&i.ShortcutTokensFailureCount,
+		&i.ShortcutTokensQuarantinedUntil,
+		&i.ShortcutTokensDeadAt,
 	)
 	return i, err
 }
 
+const listShortcutAdHistory = `-- name: ListShortcutAdHistory :many
+SELECT shortcut_ad_history_id, shortcut_ad_history_ad_id, shortcut_ad_history_event, shortcut_ad_history_price, shortcut_ad_history_debt_free_price, shortcut_ad_history_previous_price, shortcut_ad_history_previous_debt_free_price, shortcut_ad_history_status, shortcut_ad_history_recorded_at FROM public.shortcut_ad_history
+WHERE shortcut_ad_history_ad_id = $1
+ORDER BY shortcut_ad_history_recorded_at ASC
+`
+
+func (q *Queries) ListShortcutAdHistory(ctx context.Context, shortcutAdHistoryAdID int64) ([]ShortcutAdHistory, error) {
+	rows, err := q.db.Query(ctx, listShortcutAdHistory, shortcutAdHistoryAdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ShortcutAdHistory{}
+	for rows.Next() {
+		var i ShortcutAdHistory
+		if err := rows.Scan(
+			&i.ShortcutAdHistoryID,
+			&i.ShortcutAdHistoryAdID,
+			&i.ShortcutAdHistoryEvent,
+			&i.ShortcutAdHistoryPrice,
+			&i.ShortcutAdHistoryDebtFreePrice,
+			&i.ShortcutAdHistoryPreviousPrice,
+			&i.ShortcutAdHistoryPreviousDebtFreePrice,
+			&i.ShortcutAdHistoryStatus,
+			&i.ShortcutAdHistoryRecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listShortcutAds = `-- name: ListShortcutAds :many
-SELECT shortcut_ads_id, shortcut_ads_url, shortcut_ads_type, shortcut_ads_first_seen_at, shortcut_ads_last_seen_at, shortcut_ads_data, shortcut_ads_updated_at, shortcut_ads_building_id FROM public.shortcut_ads
+SELECT shortcut_ads_id, shortcut_ads_url, shortcut_ads_type, shortcut_ads_first_seen_at, shortcut_ads_last_seen_at, shortcut_ads_data, shortcut_ads_updated_at, shortcut_ads_building_id, shortcut_ads_delisted_at FROM public.shortcut_ads
 ORDER BY shortcut_ads_last_seen_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -325,6 +461,7 @@ func (q *Queries) ListShortcutAds(ctx context.Context, arg *ListShortcutAdsParam
 			&i.ShortcutAdsData,
 			&i.ShortcutAdsUpdatedAt,
 			&i.ShortcutAdsBuildingID,
+			&i.ShortcutAdsDelistedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -337,7 +474,7 @@ func (q *Queries) ListShortcutAds(ctx context.Context, arg *ListShortcutAdsParam
 }
 
 const listShortcutBuildings = `-- name: ListShortcutBuildings :many
-SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_geom FROM public.shortcut_buildings
+SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_postcode, shortcut_buildings_geom, shortcut_buildings_vrk_id, shortcut_buildings_size_min, shortcut_buildings_size_max, shortcut_buildings_media, shortcut_buildings_api_synced_at FROM public.shortcut_buildings
 ORDER BY shortcut_buildings_created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -383,7 +520,13 @@ func (q *Queries) ListShortcutBuildings(ctx context.Context, arg *ListShortcutBu
 			&i.ShortcutBuildingsPageNotFound,
 			&i.ShortcutBuildingsFrameConstructionMethod,
 			&i.ShortcutBuildingsHousingCompany,
+			&i.ShortcutBuildingsPostcode,
 			&i.ShortcutBuildingsGeom,
+			&i.ShortcutBuildingsVrkID,
+			&i.ShortcutBuildingsSizeMin,
+			&i.ShortcutBuildingsSizeMax,
+			&i.ShortcutBuildingsMedia,
+			&i.ShortcutBuildingsApiSyncedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -396,7 +539,7 @@ func (q *Queries) ListShortcutBuildings(ctx context.Context, arg *ListShortcutBu
 }
 
 const listUnprocessedShortcutBuildings = `-- name: ListUnprocessedShortcutBuildings :many
-SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_geom FROM public.shortcut_buildings
+SELECT shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_postcode, shortcut_buildings_geom, shortcut_buildings_vrk_id, shortcut_buildings_size_min, shortcut_buildings_size_max, shortcut_buildings_media, shortcut_buildings_api_synced_at FROM public.shortcut_buildings
 WHERE shortcut_buildings_processed_at IS NULL AND shortcut_buildings_page_not_found = false
 ORDER BY shortcut_buildings_created_at DESC
 LIMIT $1
@@ -438,7 +581,13 @@ func (q *Queries) ListUnprocessedShortcutBuildings(ctx context.Context, limit in
 			&i.ShortcutBuildingsPageNotFound,
 			&i.ShortcutBuildingsFrameConstructionMethod,
 			&i.ShortcutBuildingsHousingCompany,
+			&i.ShortcutBuildingsPostcode,
 			&i.ShortcutBuildingsGeom,
+			&i.ShortcutBuildingsVrkID,
+			&i.ShortcutBuildingsSizeMin,
+			&i.ShortcutBuildingsSizeMax,
+			&i.ShortcutBuildingsMedia,
+			&i.ShortcutBuildingsApiSyncedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -450,6 +599,18 @@ func (q *Queries) ListUnprocessedShortcutBuildings(ctx context.Context, limit in
 	return items, nil
 }
 
+const markShortcutAdDelisted = `-- name: MarkShortcutAdDelisted :exec
+UPDATE public.shortcut_ads
+SET shortcut_ads_delisted_at = COALESCE(shortcut_ads_delisted_at, now()),
+    shortcut_ads_updated_at = CURRENT_TIMESTAMP
+WHERE shortcut_ads_id = $1
+`
+
+func (q *Queries) MarkShortcutAdDelisted(ctx context.Context, shortcutAdsID int64) error {
+	_, err := q.db.Exec(ctx, markShortcutAdDelisted, shortcutAdsID)
+	return err
+}
+
 const markShortcutBuildingPageNotFound = `-- name: MarkShortcutBuildingPageNotFound :exec
 UPDATE public.shortcut_buildings
 SET shortcut_buildings_page_not_found = true, shortcut_buildings_updated_at = CURRENT_TIMESTAMP
@@ -472,6 +633,110 @@ func (q *Queries) MarkShortcutBuildingProcessed(ctx context.Context, shortcutBui
 	return err
 }
 
+const recordShortcutSearchAd = `-- name: RecordShortcutSearchAd :one
+SELECT public.fnc__record_shortcut_search_ad(
+    $1::bigint,
+    $2::text,
+    $3::text
+)::boolean AS inserted
+`
+
+type RecordShortcutSearchAdParams struct {
+	AdID   int64  `db:"ad_id" json:"ad_id"`
+	Url    string `db:"url" json:"url"`
+	AdType string `db:"ad_type" json:"ad_type"`
+}
+
+// Records an ad found by the area search. The data of the ad is left to the
+// API sync; inserted tells whether the ad was not known before.
+func (q *Queries) RecordShortcutSearchAd(ctx context.Context, arg *RecordShortcutSearchAdParams) (bool, error) {
+	row := q.db.QueryRow(ctx, recordShortcutSearchAd, arg.AdID, arg.Url, arg.AdType)
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
+}
+
+const updateShortcutBuildingFromAPI = `-- name: UpdateShortcutBuildingFromAPI :exec
+UPDATE public.shortcut_buildings
+SET shortcut_buildings_vrk_id = COALESCE($1::text, shortcut_buildings_vrk_id),
+    shortcut_buildings_construction_year = COALESCE($2::int4, shortcut_buildings_construction_year),
+    shortcut_buildings_floor_count = COALESCE($3::int4, shortcut_buildings_floor_count),
+    shortcut_buildings_apartment_count = COALESCE($4::int4, shortcut_buildings_apartment_count),
+    shortcut_buildings_has_elevator = COALESCE(CASE $5::bool WHEN TRUE THEN 'Kyllä' WHEN FALSE THEN 'Ei' END, shortcut_buildings_has_elevator),
+    shortcut_buildings_has_sauna = COALESCE(CASE $6::bool WHEN TRUE THEN 'Kyllä' WHEN FALSE THEN 'Ei' END, shortcut_buildings_has_sauna),
+    shortcut_buildings_size_min = $7::int4,
+    shortcut_buildings_size_max = $8::int4,
+    shortcut_buildings_media = $9::jsonb,
+    shortcut_buildings_latitude = COALESCE($10::float8, shortcut_buildings_latitude),
+    shortcut_buildings_longitude = COALESCE($11::float8, shortcut_buildings_longitude),
+    shortcut_buildings_address = COALESCE($12::text, shortcut_buildings_address),
+    shortcut_buildings_postcode = COALESCE($13::text, shortcut_buildings_postcode),
+    shortcut_buildings_api_synced_at = CURRENT_TIMESTAMP,
+    shortcut_buildings_updated_at = CURRENT_TIMESTAMP
+WHERE shortcut_buildings_id = $14
+`
+
+type UpdateShortcutBuildingFromAPIParams struct {
+	VrkID            *string     `db:"vrk_id" json:"vrk_id"`
+	ConstructionYear *int32      `db:"construction_year" json:"construction_year"`
+	FloorCount       *int32      `db:"floor_count" json:"floor_count"`
+	ApartmentCount   *int32      `db:"apartment_count" json:"apartment_count"`
+	HasElevator      *bool       `db:"has_elevator" json:"has_elevator"`
+	HasSauna         *bool       `db:"has_sauna" json:"has_sauna"`
+	SizeMin          *int32      `db:"size_min" json:"size_min"`
+	SizeMax          *int32      `db:"size_max" json:"size_max"`
+	Media            []byte      `db:"media" json:"media"`
+	Latitude         *float64    `db:"latitude" json:"latitude"`
+	Longitude        *float64    `db:"longitude" json:"longitude"`
+	Address          *string     `db:"address" json:"address"`
+	Postcode         *string     `db:"postcode" json:"postcode"`
+	ID               pgtype.UUID `db:"id" json:"id"`
+}
+
+// Stores the structured data of the building API. Fields the API leaves out
+// keep the values the scraper found. The elevator and sauna flags are written
+// in the Kyllä/Ei form the scraper stores them in.
+func (q *Queries) UpdateShortcutBuildingFromAPI(ctx context.Context, arg *UpdateShortcutBuildingFromAPIParams) error {
+	_, err := q.db.Exec(ctx, updateShortcutBuildingFromAPI,
+		arg.VrkID,
+		arg.ConstructionYear,
+		arg.FloorCount,
+		arg.ApartmentCount,
+		arg.HasElevator,
+		arg.HasSauna,
+		arg.SizeMin,
+		arg.SizeMax,
+		arg.Media,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Address,
+		arg.Postcode,
+		arg.ID,
+	)
+	return err
+}
+
+const updateShortcutTokenHealth = `-- name: UpdateShortcutTokenHealth :exec
+UPDATE public.shortcut_tokens
+SET shortcut_tokens_failure_count = $1,
+    shortcut_tokens_quarantined_until = $2,
+    shortcut_tokens_dead_at = CASE WHEN $3::boolean THEN NOW() END,
+    shortcut_tokens_updated_at = NOW()
+WHERE shortcut_tokens_cuid = $4
+`
+
+type UpdateShortcutTokenHealthParams struct {
+	FailureCount     int32              `db:"failure_count" json:"failure_count"`
+	QuarantinedUntil pgtype.Timestamptz `db:"quarantined_until" json:"quarantined_until"`
+	Dead             bool               `db:"dead" json:"dead"`
+	Cuid             string             `db:"cuid" json:"cuid"`
+}
+
+func (q *Queries) UpdateShortcutTokenHealth(ctx context.Context, arg *UpdateShortcutTokenHealthParams) error {
+	_, err := q.db.Exec(ctx, updateShortcutTokenHealth, arg.FailureCount, arg.QuarantinedUntil, arg.Dead, arg.Cuid)
+	return err
+}
+
 const upsertShortcutAd = `-- name: UpsertShortcutAd :one
 INSERT INTO public.shortcut_ads (
     shortcut_ads_id,
@@ -487,9 +752,10 @@ ON CONFLICT (shortcut_ads_id) DO UPDATE SET
     shortcut_ads_type = EXCLUDED.shortcut_ads_type,
     shortcut_ads_data = EXCLUDED.shortcut_ads_data,
     shortcut_ads_building_id = EXCLUDED.shortcut_ads_building_id,
+    shortcut_ads_delisted_at = CASE WHEN EXCLUDED.shortcut_ads_data IS NULL THEN shortcut_ads.shortcut_ads_delisted_at END,
     shortcut_ads_last_seen_at = now(),
     shortcut_ads_updated_at = CURRENT_TIMESTAMP
-RETURNING shortcut_ads_id, shortcut_ads_url, shortcut_ads_type, shortcut_ads_first_seen_at, shortcut_ads_last_seen_at, shortcut_ads_data, shortcut_ads_updated_at, shortcut_ads_building_id
+RETURNING shortcut_ads_id, shortcut_ads_url, shortcut_ads_type, shortcut_ads_first_seen_at, shortcut_ads_last_seen_at, shortcut_ads_data, shortcut_ads_updated_at, shortcut_ads_building_id, shortcut_ads_delisted_at
 `
 
 type UpsertShortcutAdParams struct {
@@ -518,6 +784,7 @@ func (q *Queries) UpsertShortcutAd(ctx context.Context, arg *UpsertShortcutAdPar
 		&i.ShortcutAdsData,
 		&i.ShortcutAdsUpdatedAt,
 		&i.ShortcutAdsBuildingID,
+		&i.ShortcutAdsDelistedAt,
 	)
 	return i, err
 }
@@ -570,7 +837,7 @@ ON CONFLICT (shortcut_buildings_external_id) DO UPDATE SET
     shortcut_buildings_frame_construction_method = EXCLUDED.shortcut_buildings_frame_construction_method,
     shortcut_buildings_housing_company = EXCLUDED.shortcut_buildings_housing_company,
     shortcut_buildings_updated_at = CURRENT_TIMESTAMP
-RETURNING shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_geom
+RETURNING shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_postcode, shortcut_buildings_geom, shortcut_buildings_vrk_id, shortcut_buildings_size_min, shortcut_buildings_size_max, shortcut_buildings_media, shortcut_buildings_api_synced_at
 `
 
 type UpsertShortcutBuildingParams struct {
@@ -649,7 +916,13 @@ func (q *Queries) UpsertShortcutBuilding(ctx context.Context, arg *UpsertShortcu
 		&i.ShortcutBuildingsPageNotFound,
 		&i.ShortcutBuildingsFrameConstructionMethod,
 		&i.ShortcutBuildingsHousingCompany,
+		&i.ShortcutBuildingsPostcode,
 		&i.ShortcutBuildingsGeom,
+		&i.ShortcutBuildingsVrkID,
+		&i.ShortcutBuildingsSizeMin,
+		&i.ShortcutBuildingsSizeMax,
+		&i.ShortcutBuildingsMedia,
+		&i.ShortcutBuildingsApiSyncedAt,
 	)
 	return i, err
 }
@@ -664,7 +937,7 @@ INSERT INTO public.shortcut_buildings (
 ON CONFLICT (shortcut_buildings_external_id) DO UPDATE SET
     shortcut_buildings_url = EXCLUDED.shortcut_buildings_url,
     shortcut_buildings_updated_at = CURRENT_TIMESTAMP
-RETURNING shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_geom
+RETURNING shortcut_buildings_id, shortcut_buildings_external_id, shortcut_buildings_building_id, shortcut_buildings_building_type, shortcut_buildings_building_subtype, shortcut_buildings_construction_year, shortcut_buildings_floor_count, shortcut_buildings_apartment_count, shortcut_buildings_heating_system, shortcut_buildings_building_material, shortcut_buildings_plot_type, shortcut_buildings_wall_structure, shortcut_buildings_heat_source, shortcut_buildings_has_elevator, shortcut_buildings_has_sauna, shortcut_buildings_latitude, shortcut_buildings_longitude, shortcut_buildings_additional_addresses, shortcut_buildings_url, shortcut_buildings_created_at, shortcut_buildings_updated_at, shortcut_buildings_address, shortcut_buildings_processed_at, shortcut_buildings_page_not_found, shortcut_buildings_frame_construction_method, shortcut_buildings_housing_company, shortcut_buildings_postcode, shortcut_buildings_geom, shortcut_buildings_vrk_id, shortcut_buildings_size_min, shortcut_buildings_size_max, shortcut_buildings_media, shortcut_buildings_api_synced_at
 `
 
 type UpsertShortcutBuildingFromSitemapParams struct {
@@ -702,7 +975,13 @@ func (q *Queries) UpsertShortcutBuildingFromSitemap(ctx context.Context, arg *Up
 		&i.ShortcutBuildingsPageNotFound,
 		&i.ShortcutBuildingsFrameConstructionMethod,
 		&i.ShortcutBuildingsHousingCompany,
+		&i.ShortcutBuildingsPostcode,
 		&i.ShortcutBuildingsGeom,
+		&i.ShortcutBuildingsVrkID,
+		&i.ShortcutBuildingsSizeMin,
+		&i.ShortcutBuildingsSizeMax,
+		&i.ShortcutBuildingsMedia,
+		&i.ShortcutBuildingsApiSyncedAt,
 	)
 	return i, err
 }
@@ -25,12 +25,19 @@ CREATE TABLE public.shortcut_buildings (
     shortcut_buildings_page_not_found bool DEFAULT false,
     shortcut_buildings_frame_construction_method text,
     shortcut_buildings_housing_company text,
+    shortcut_buildings_postcode text,
     shortcut_buildings_geom geometry(Point, 4326),
+    shortcut_buildings_vrk_id text,
+    shortcut_buildings_size_min int4,
+    shortcut_buildings_size_max int4,
+    shortcut_buildings_media jsonb,
+    shortcut_buildings_api_synced_at timestamptz,
     PRIMARY KEY (shortcut_buildings_id)
 );
 
 CREATE UNIQUE INDEX shortcut_buildings_external_id_key ON public.shortcut_buildings USING btree (shortcut_buildings_external_id);
 CREATE INDEX shortcut_buildings_geom_idx ON public.shortcut_buildings USING GIST (shortcut_buildings_geom);
+CREATE INDEX idx_shortcut_buildings_vrk_id ON public.shortcut_buildings(shortcut_buildings_vrk_id);
 
 CREATE TABLE public.shortcut_building_listings (
     shortcut_building_listings_id uuid NOT NULL DEFAULT gen_random_uuid(),
@@ -91,11 +98,27 @@ CREATE TABLE public.shortcut_ads (
     shortcut_ads_data jsonb,
     shortcut_ads_updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
     shortcut_ads_building_id uuid REFERENCES public.shortcut_buildings(shortcut_buildings_id) ON DELETE SET NULL,
+    shortcut_ads_delisted_at timestamptz,
     PRIMARY KEY (shortcut_ads_id)
 );
 
 CREATE INDEX idx_shortcut_ads_zipcode_name ON public.shortcut_ads(((((shortcut_ads_data -> 'address'::text) -> 'zipCode'::text) ->> 'name'::text)));
 
+CREATE TABLE public.shortcut_ad_history (
+    shortcut_ad_history_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    shortcut_ad_history_ad_id int8 NOT NULL REFERENCES public.shortcut_ads(shortcut_ads_id) ON DELETE CASCADE,
+    shortcut_ad_history_event text NOT NULL,
+    shortcut_ad_history_price float8,
+    shortcut_ad_history_debt_free_price float8,
+    shortcut_ad_history_previous_price float8,
+    shortcut_ad_history_previous_debt_free_price float8,
+    shortcut_ad_history_status text,
+    shortcut_ad_history_recorded_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (shortcut_ad_history_id)
+);
+
+CREATE INDEX idx_shortcut_ad_history_ad_id ON public.shortcut_ad_history(shortcut_ad_history_ad_id, shortcut_ad_history_recorded_at);
+
 CREATE TABLE public.shortcut_tokens (
     shortcut_tokens_id uuid NOT NULL DEFAULT gen_random_uuid(),
     shortcut_tokens_cuid text NOT NULL,
@@ -104,9 +127,18 @@ CREATE TABLE public.shortcut_tokens (
     shortcut_tokens_created_at timestamptz NOT NULL DEFAULT now(),
     shortcut_tokens_updated_at timestamptz NOT NULL DEFAULT now(),
     shortcut_tokens_expires_at timestamptz NOT NULL,
+    shortcut_tokens_failure_count int4 NOT NULL DEFAULT 0,
+    shortcut_tokens_quarantined_until timestamptz,
+    shortcut_tokens_dead_at timestamptz,
     PRIMARY KEY (shortcut_tokens_id),
     UNIQUE(shortcut_tokens_cuid)
 );
 
 CREATE INDEX idx_shortcut_tokens_expires_at ON public.shortcut_tokens USING btree (shortcut_tokens_expires_at DESC);
 CREATE INDEX idx_shortcut_tokens_cuid ON public.shortcut_tokens USING btree (shortcut_tokens_cuid);
+
+CREATE OR REPLACE FUNCTION public.fnc__record_shortcut_search_ad(
+    p_ad_id BIGINT,
+    p_url TEXT,
+    p_type TEXT
+) RETURNS BOOLEAN AS $$ BEGIN RETURN FALSE; END; $$ LANGUAGE plpgsql;
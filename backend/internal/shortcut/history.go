@@ -0,0 +1,55 @@
+package shortcut
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"koditon-go/internal/listings"
+	"koditon-go/internal/shortcut/db"
+)
+
+// recordAdHistory appends a history row when the ad differs from its last
+// recorded state and returns it, or nil when nothing changed.
+func (s *Service) recordAdHistory(ctx context.Context, adID int64, next listings.AdSnapshot) (*listings.HistoryEntry, error) {
+	return listings.RecordAdHistory(ctx, adHistory{queries: s.queries, adID: adID}, next, nil)
+}
+
+// adHistory is the history of a shortcut ad.
+type adHistory struct {
+	queries *db.Queries
+	adID    int64
+}
+
+func (h adHistory) Latest(ctx context.Context) (*listings.HistoryEntry, error) {
+	latest, err := h.queries.GetLatestShortcutAdHistory(ctx, h.adID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest ad history (ad_id=%d): %w", h.adID, err)
+	}
+	return &listings.HistoryEntry{
+		Event:         latest.ShortcutAdHistoryEvent,
+		Price:         latest.ShortcutAdHistoryPrice,
+		DebtFreePrice: latest.ShortcutAdHistoryDebtFreePrice,
+		Status:        latest.ShortcutAdHistoryStatus,
+	}, nil
+}
+
+func (h adHistory) Append(ctx context.Context, entry listings.HistoryEntry) error {
+	if err := h.queries.InsertShortcutAdHistory(ctx, &db.InsertShortcutAdHistoryParams{
+		ShortcutAdHistoryAdID:                  h.adID,
+		ShortcutAdHistoryEvent:                 entry.Event,
+		ShortcutAdHistoryPrice:                 entry.Price,
+		ShortcutAdHistoryDebtFreePrice:         entry.DebtFreePrice,
+		ShortcutAdHistoryPreviousPrice:         entry.PreviousPrice,
+		ShortcutAdHistoryPreviousDebtFreePrice: entry.PreviousDebtFreePrice,
+		ShortcutAdHistoryStatus:                entry.Status,
+	}); err != nil {
+		return fmt.Errorf("insert ad history (ad_id=%d): %w", h.adID, err)
+	}
+	return nil
+}
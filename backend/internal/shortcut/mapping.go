@@ -1,6 +1,13 @@
 package shortcut
 
 import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"koditon-go/internal/listings"
 	"koditon-go/internal/shortcut/client"
 	"koditon-go/internal/shortcut/db"
 	"koditon-go/internal/util"
@@ -8,6 +15,8 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var adNumberCleanupRegexp = regexp.MustCompile(`[^0-9,.]`)
+
 func mapUpsertBuildingFromSitemapParams(entry client.ShortcutSitemapEntry) *db.UpsertShortcutBuildingFromSitemapParams {
 	return &db.UpsertShortcutBuildingFromSitemapParams{
 		ShortcutBuildingsExternalID: int64(entry.ID),
@@ -25,6 +34,32 @@ func mapUpsertAdParams(adID int64, url string, adType string, data []byte, short
 	}
 }
 
+// mapAdSnapshot takes the tracked fields from the raw ad JSON. Prices come
+// as numbers or as formatted text like '189 000 €'.
+func mapAdSnapshot(adType string, adData map[string]any) listings.AdSnapshot {
+	return listings.AdSnapshot{
+		Price:         parseAdNumber(adData["price"]),
+		DebtFreePrice: parseAdNumber(adData["debtFreePrice"]),
+		Status:        &adType,
+	}
+}
+
+func parseAdNumber(value any) *float64 {
+	switch v := value.(type) {
+	case float64:
+		return &v
+	case string:
+		cleaned := strings.ReplaceAll(adNumberCleanupRegexp.ReplaceAllString(v, ""), ",", ".")
+		parsed, err := strconv.ParseFloat(cleaned, 64)
+		if err != nil {
+			return nil
+		}
+		return &parsed
+	default:
+		return nil
+	}
+}
+
 func mapScrapedBuildingParams(shortcutBuildingID int64, url string, scraped *client.ScrapedBuilding) *db.UpsertShortcutBuildingParams {
 	return &db.UpsertShortcutBuildingParams{
 		ShortcutBuildingsExternalID:              shortcutBuildingID,
@@ -51,6 +86,51 @@ func mapScrapedBuildingParams(shortcutBuildingID int64, url string, scraped *cli
 	}
 }
 
+// mapBuildingDataParams takes the structured fields of the building API
+// response. The media list is stored as the API returned it.
+func mapBuildingDataParams(buildingID pgtype.UUID, data *client.BuildingResponse) (*db.UpdateShortcutBuildingFromAPIParams, error) {
+	params := &db.UpdateShortcutBuildingFromAPIParams{
+		VrkID:            data.VrkID,
+		ConstructionYear: toInt32Ptr(data.BuildYear),
+		FloorCount:       toInt32Ptr(data.Floors),
+		ApartmentCount:   toInt32Ptr(data.Apartments),
+		HasElevator:      data.Lift,
+		HasSauna:         data.Sauna,
+		SizeMin:          toInt32Ptr(data.SizeMin),
+		SizeMax:          toInt32Ptr(data.SizeMax),
+		Address:          data.FormattedAddress,
+		ID:               buildingID,
+	}
+	if data.Address != nil {
+		if params.Address == nil {
+			params.Address = data.Address.FormattedAddress
+		}
+		if data.Address.ZipCode != nil {
+			params.Postcode = data.Address.ZipCode.Name
+		}
+		if coords := data.Address.Coordinates; coords != nil && coords.Latitude != 0 && coords.Longitude != 0 {
+			params.Latitude = &coords.Latitude
+			params.Longitude = &coords.Longitude
+		}
+	}
+	if len(data.Media) > 0 {
+		media, err := json.Marshal(data.Media)
+		if err != nil {
+			return nil, fmt.Errorf("marshal media: %w", err)
+		}
+		params.Media = media
+	}
+	return params, nil
+}
+
+func toInt32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
 func mapListingParams(buildingID pgtype.UUID, listing *client.BuildingListing) *db.UpsertShortcutBuildingListingParams {
 	return &db.UpsertShortcutBuildingListingParams{
 		ShortcutBuildingListingsBuildingID:    buildingID,
@@ -0,0 +1,104 @@
+package shortcut
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"koditon-go/internal/shortcut/client"
+	"koditon-go/internal/shortcut/db"
+)
+
+const (
+	searchPageSize = 30
+	// searchMaxPages bounds the walk of a single card type of an area.
+	searchMaxPages = 100
+)
+
+var ErrLocationNotFound = errors.New("shortcut: no location for postcode")
+
+// SearchSync is the outcome of walking the search results of an area.
+type SearchSync struct {
+	AdIDs []string
+	// NewAdIDs are the ads the search found before the sitemap did.
+	NewAdIDs []string
+	// Failed counts the cards that could not be stored.
+	Failed int
+}
+
+// SyncSearch walks the sale and rent search results of a postcode and records
+// the ads it finds. Recording an ad registers it and queues the API sync of a
+// new one.
+func (s *Service) SyncSearch(ctx context.Context, postcode string) (*SearchSync, error) {
+	location, err := s.searchLocation(ctx, postcode)
+	if err != nil {
+		return nil, err
+	}
+	result := &SearchSync{}
+	var upsertErrors []error
+	for _, cardType := range []client.CardType{client.CardTypeSale, client.CardTypeRent} {
+		for page := 0; page < searchMaxPages; page++ {
+			search, err := s.client.SearchApartments(ctx, client.SearchParams{
+				Location: *location,
+				CardType: cardType,
+				Page:     page,
+				PageSize: searchPageSize,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("search %s page %d (postcode=%s): %w", adTypeOf(cardType), page, postcode, err)
+			}
+			for _, card := range search.Cards {
+				inserted, err := s.queries.RecordShortcutSearchAd(ctx, &db.RecordShortcutSearchAdParams{
+					AdID:   int64(card.ID),
+					Url:    card.URL,
+					AdType: adTypeOf(cardType),
+				})
+				if err != nil {
+					upsertErrors = append(upsertErrors, fmt.Errorf("record ad %d: %w", card.ID, err))
+					continue
+				}
+				entityID := fmt.Sprintf("ad:%d", card.ID)
+				result.AdIDs = append(result.AdIDs, entityID)
+				if inserted {
+					result.NewAdIDs = append(result.NewAdIDs, entityID)
+				}
+			}
+			if len(search.Cards) < searchPageSize || (page+1)*searchPageSize >= search.Found {
+				break
+			}
+		}
+	}
+	if len(result.AdIDs) == 0 && len(upsertErrors) > 0 {
+		return nil, fmt.Errorf("all upserts failed: %w", errors.Join(upsertErrors...))
+	}
+	result.Failed = len(upsertErrors)
+	return result, nil
+}
+
+// searchLocation looks up the location of a postcode, preferring the one
+// named exactly after it.
+func (s *Service) searchLocation(ctx context.Context, postcode string) (*client.LocationResponse, error) {
+	locations, err := s.client.FetchLocationIDs(ctx, postcode)
+	if err != nil {
+		return nil, fmt.Errorf("fetch location (postcode=%s): %w", postcode, err)
+	}
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrLocationNotFound, postcode)
+	}
+	for i := range locations {
+		if locations[i].Card.Name == postcode {
+			return &locations[i], nil
+		}
+	}
+	return &locations[0], nil
+}
+
+func adTypeOf(cardType client.CardType) string {
+	switch cardType {
+	case client.CardTypeSale:
+		return "sale"
+	case client.CardTypeRent:
+		return "rent"
+	}
+	return "unknown"
+}
@@ -6,13 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"koditon-go/internal/listings"
 	"koditon-go/internal/shortcut/client"
 	"koditon-go/internal/shortcut/db"
+	"koditon-go/internal/transport"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -30,23 +35,30 @@ func NewService(
 	adBaseURL string,
 	userAgent string,
 	sitemapBase string,
+	limiter *transport.Limiter,
+	tokenPool client.TokenPoolConfig,
 ) *Service {
 	queries := db.New(dbtx)
 	// Token management: We store tokens with a long expiry (1 year) and rely on the API
-	// returning 401 to trigger token refresh. This allows tokens to be reused as long as
-	// they're valid according to the API, rather than trying to predict expiry times.
-	tokenLoad := func(ctx context.Context) (*client.Tokens, error) {
-		dbToken, err := queries.GetValidShortcutToken(ctx)
+	// returning 401 to quarantine a token and eventually give up on it. This allows tokens
+	// to be reused as long as they're valid according to the API, rather than trying to
+	// predict expiry times.
+	tokenLoad := func(ctx context.Context, limit int) ([]client.PooledTokens, error) {
+		dbTokens, err := queries.GetAllValidShortcutTokens(ctx, int32(limit))
 		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return nil, errors.New("no valid token found")
-			}
 			return nil, err
 		}
-		tokens := &client.Tokens{
-			CUID:   dbToken.ShortcutTokensCuid,
-			Token:  dbToken.ShortcutTokensToken,
-			Loaded: dbToken.ShortcutTokensLoaded,
+		tokens := make([]client.PooledTokens, 0, len(dbTokens))
+		for _, dbToken := range dbTokens {
+			tokens = append(tokens, client.PooledTokens{
+				Tokens: client.Tokens{
+					CUID:   dbToken.ShortcutTokensCuid,
+					Token:  dbToken.ShortcutTokensToken,
+					Loaded: dbToken.ShortcutTokensLoaded,
+				},
+				Failures:         int(dbToken.ShortcutTokensFailureCount),
+				QuarantinedUntil: dbToken.ShortcutTokensQuarantinedUntil.Time,
+			})
 		}
 		return tokens, nil
 	}
@@ -59,15 +71,26 @@ func NewService(
 		})
 		return err
 	}
+	tokenHealth := func(ctx context.Context, tokens *client.PooledTokens) error {
+		return queries.UpdateShortcutTokenHealth(ctx, &db.UpdateShortcutTokenHealthParams{
+			FailureCount:     int32(tokens.Failures),
+			QuarantinedUntil: pgtype.Timestamptz{Time: tokens.QuarantinedUntil, Valid: !tokens.QuarantinedUntil.IsZero()},
+			Dead:             tokens.Dead,
+			Cuid:             tokens.CUID,
+		})
+	}
 	shortcutClient := client.NewClient(
 		logger,
 		tokenLoad,
 		tokenStore,
+		tokenHealth,
+		tokenPool,
 		baseURL,
 		docsBaseURL,
 		adBaseURL,
 		userAgent,
 		sitemapBase,
+		limiter,
 	)
 	return &Service{
 		client:  shortcutClient,
@@ -76,10 +99,22 @@ func NewService(
 	}
 }
 
-func (s *Service) SyncSitemap(ctx context.Context) (buildingIDs []string, adIDs []string, err error) {
-	allEntries, fetchErr := s.client.GetSitemapEntries(ctx)
+// SitemapSync is the outcome of a sitemap crawl.
+type SitemapSync struct {
+	BuildingIDs []string
+	AdIDs       []string
+	// Failed counts the sitemap entries that could not be stored and the
+	// sitemaps that could not be fetched. The IDs miss entries the site still
+	// lists while it is not zero.
+	Failed int
+	// FetchErrors are the errors of the sitemaps that could not be fetched.
+	FetchErrors []error
+}
+
+func (s *Service) SyncSitemap(ctx context.Context) (*SitemapSync, error) {
+	allEntries, fetchErrors, fetchErr := s.client.GetSitemapEntries(ctx)
 	if fetchErr != nil {
-		return nil, nil, fmt.Errorf("fetch sitemap entries: %w", fetchErr)
+		return nil, fmt.Errorf("fetch sitemap entries: %w", fetchErr)
 	}
 	var buildingEntries, listingEntries, rentalEntries []client.ShortcutSitemapEntry
 	for _, entry := range allEntries {
@@ -93,6 +128,7 @@ func (s *Service) SyncSitemap(ctx context.Context) (buildingIDs []string, adIDs
 		}
 	}
 	adEntries := append(listingEntries, rentalEntries...)
+	var buildingIDs, adIDs []string
 	var upsertErrors []error
 	if len(buildingEntries) > 0 {
 		buildingIDs = make([]string, 0, len(buildingEntries))
@@ -122,19 +158,44 @@ func (s *Service) SyncSitemap(ctx context.Context) (buildingIDs []string, adIDs
 		}
 	}
 	if len(buildingIDs) == 0 && len(adIDs) == 0 && len(upsertErrors) > 0 {
-		return nil, nil, fmt.Errorf("all upserts failed: %w", errors.Join(upsertErrors...))
+		return nil, fmt.Errorf("all upserts failed: %w", errors.Join(upsertErrors...))
+	}
+	return &SitemapSync{
+		BuildingIDs: buildingIDs,
+		AdIDs:       adIDs,
+		Failed:      len(upsertErrors) + len(fetchErrors),
+		FetchErrors: fetchErrors,
+	}, nil
+}
+
+// DelistAds marks ads that dropped out of the sitemap as delisted.
+func (s *Service) DelistAds(ctx context.Context, adIDs []int64) (int64, error) {
+	if len(adIDs) == 0 {
+		return 0, nil
+	}
+	count, err := s.queries.DelistShortcutAds(ctx, adIDs)
+	if err != nil {
+		return 0, fmt.Errorf("delist ads: %w", err)
 	}
-	return buildingIDs, adIDs, nil
+	return count, nil
 }
 
-func (s *Service) SyncAd(ctx context.Context, adID int64) error {
+// SyncAd fetches an ad and records its history. It returns the recorded
+// history entry, or nil when the ad did not change.
+func (s *Service) SyncAd(ctx context.Context, adID int64) (*listings.HistoryEntry, error) {
 	adData, err := s.client.GetAdByID(ctx, int(adID))
 	if err != nil {
-		return fmt.Errorf("fetch ad data (ad_id=%d): %w", adID, err)
+		if httpErr, ok := client.IsHTTPStatusError(err); ok && httpErr.StatusCode == http.StatusNotFound {
+			if markErr := s.queries.MarkShortcutAdDelisted(ctx, adID); markErr != nil {
+				return nil, fmt.Errorf("mark ad delisted (ad_id=%d): %w", adID, markErr)
+			}
+			return s.recordAdHistory(ctx, adID, listings.AdSnapshot{Delisted: true})
+		}
+		return nil, fmt.Errorf("fetch ad data (ad_id=%d): %w", adID, err)
 	}
 	var adDataMap map[string]any
 	if err := json.Unmarshal(adData, &adDataMap); err != nil {
-		return fmt.Errorf("unmarshal ad data (ad_id=%d): %w", adID, err)
+		return nil, fmt.Errorf("unmarshal ad data (ad_id=%d): %w", adID, err)
 	}
 	adType := "unknown"
 	if cardType, ok := adDataMap["cardType"].(float64); ok {
@@ -159,20 +220,107 @@ func (s *Service) SyncAd(ctx context.Context, adID int64) error {
 	}
 	existingAd, err := s.queries.GetShortcutAdByID(ctx, adID)
 	if err != nil {
-		return fmt.Errorf("get existing ad (ad_id=%d): %w", adID, err)
+		return nil, fmt.Errorf("get existing ad (ad_id=%d): %w", adID, err)
 	}
 	params := mapUpsertAdParams(adID, existingAd.ShortcutAdsUrl, adType, adData, shortcutBuildingID)
 	if _, err = s.queries.UpsertShortcutAd(ctx, params); err != nil {
-		return fmt.Errorf("upsert ad data (ad_id=%d): %w", adID, err)
+		return nil, fmt.Errorf("upsert ad data (ad_id=%d): %w", adID, err)
 	}
-	return nil
+	return s.recordAdHistory(ctx, adID, mapAdSnapshot(adType, adDataMap))
 }
 
+// SyncBuilding stores the structured data of the building API. The building
+// page is scraped for the building fields only when the API has no data for
+// the building; otherwise it is still scraped for the listings and rentals,
+// which the API does not have, and a failing scraper does not fail the sync
+// once the API data is stored. A failing API fails the sync so that it is
+// retried like any other source error.
 func (s *Service) SyncBuilding(ctx context.Context, buildingID uuid.UUID) error {
-	building, err := s.queries.GetShortcutBuildingByID(ctx, pgtype.UUID{Bytes: buildingID, Valid: true})
+	id := pgtype.UUID{Bytes: buildingID, Valid: true}
+	building, err := s.queries.GetShortcutBuildingByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("get building (building_id=%s): %w", buildingID, err)
 	}
+	data, err := s.fetchBuildingData(ctx, &building)
+	if err != nil {
+		return fmt.Errorf("fetch building data (building_id=%s): %w", buildingID, err)
+	}
+	if data == nil {
+		return s.scrapeBuilding(ctx, buildingID, &building, true)
+	}
+	params, err := mapBuildingDataParams(id, data)
+	if err != nil {
+		return fmt.Errorf("map building data (building_id=%s): %w", buildingID, err)
+	}
+	if err := s.queries.UpdateShortcutBuildingFromAPI(ctx, params); err != nil {
+		return fmt.Errorf("update building from API (building_id=%s): %w", buildingID, err)
+	}
+	if err := s.scrapeBuilding(ctx, buildingID, &building, false); err != nil {
+		s.logger.WarnContext(ctx, "shortcut building listings scrape failed, keeping the API data", "building_id", buildingID, "error", err)
+	}
+	if err := s.queries.MarkShortcutBuildingProcessed(ctx, id); err != nil {
+		return fmt.Errorf("mark building processed (building_id=%s): %w", buildingID, err)
+	}
+	return nil
+}
+
+// maxBuildingLocations caps the locations matching the address of a building
+// that are asked for its data.
+const maxBuildingLocations = 3
+
+// fetchBuildingData returns the building API data of a building, or nil when
+// the API does not know the building. The API looks buildings up by location,
+// so the locations are searched by the address of the building and only the
+// building with its id is accepted.
+func (s *Service) fetchBuildingData(ctx context.Context, building *db.ShortcutBuilding) (*client.BuildingResponse, error) {
+	query := buildingLocationQuery(building)
+	if query == "" {
+		return nil, nil
+	}
+	locations, err := s.client.FetchBuildings(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, location := range locations[:min(len(locations), maxBuildingLocations)] {
+		buildings, err := s.client.GetBuildingData(ctx, strconv.Itoa(location.Card.CardID))
+		if err != nil {
+			if httpErr, ok := client.IsHTTPStatusError(err); ok && httpErr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, err
+		}
+		for i := range buildings {
+			if int64(buildings[i].BuildingID) == building.ShortcutBuildingsExternalID {
+				return &buildings[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// buildingLocationQuery returns the location search text of a building. A
+// building found from the sitemap has no address before its page is scraped,
+// so the address is then read from the slug of its URL, such as
+// /talo/helsinki/kallio/fleminginkatu-1/123.
+func buildingLocationQuery(building *db.ShortcutBuilding) string {
+	if building.ShortcutBuildingsAddress != nil && *building.ShortcutBuildingsAddress != "" {
+		return *building.ShortcutBuildingsAddress
+	}
+	u, err := url.Parse(building.ShortcutBuildingsUrl)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "talo" {
+		return ""
+	}
+	// the last segment is the building id
+	return strings.ReplaceAll(strings.Join(parts[1:len(parts)-1], " "), "-", " ")
+}
+
+// scrapeBuilding stores the listings and rentals of the building page, and
+// with withFields the building fields scraped from it.
+func (s *Service) scrapeBuilding(ctx context.Context, buildingID uuid.UUID, building *db.ShortcutBuilding, withFields bool) error {
 	if building.ShortcutBuildingsPageNotFound != nil && *building.ShortcutBuildingsPageNotFound {
 		return nil
 	}
@@ -189,9 +337,11 @@ func (s *Service) SyncBuilding(ctx context.Context, buildingID uuid.UUID) error
 		}
 		return fmt.Errorf("scrape building page (building_id=%s, url=%s): %w", buildingID, building.ShortcutBuildingsUrl, err)
 	}
-	params := mapScrapedBuildingParams(int64(scrapedBuilding.ShortcutBuildingID), building.ShortcutBuildingsUrl, scrapedBuilding)
-	if _, err = s.queries.UpsertShortcutBuilding(ctx, params); err != nil {
-		return fmt.Errorf("update building (building_id=%s): %w", buildingID, err)
+	if withFields {
+		params := mapScrapedBuildingParams(int64(scrapedBuilding.ShortcutBuildingID), building.ShortcutBuildingsUrl, scrapedBuilding)
+		if _, err = s.queries.UpsertShortcutBuilding(ctx, params); err != nil {
+			return fmt.Errorf("update building (building_id=%s): %w", buildingID, err)
+		}
 	}
 	var upsertErrors []error
 	for _, listing := range listings {
@@ -0,0 +1,244 @@
+package shortcut
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"koditon-go/internal/shortcut/client"
+	"koditon-go/internal/sources"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+const (
+	SourceName = "shortcut"
+
+	EntityTypeSitemap  = "shortcut_sitemap"
+	EntityTypeAd       = "shortcut_ad"
+	EntityTypeBuilding = "shortcut_building"
+	EntityTypeSearch   = "shortcut_search"
+
+	TaskTypeSitemapSync = "shortcut_sitemap_sync"
+	TaskTypeScraperSync = "shortcut_scraper_sync"
+	TaskTypeAPISync     = "shortcut_api_sync"
+	TaskTypeSearchSync  = "shortcut_search_sync"
+)
+
+// Source runs the shortcut sitemap, area search, building and ad API
+// syncs for the task queue.
+type Source struct {
+	service         *Service
+	taskQueueClient *taskqueue.Client
+	hooks           sources.Hooks
+}
+
+func NewSource(service *Service, taskQueueClient *taskqueue.Client, hooks sources.Hooks) *Source {
+	return &Source{
+		service:         service,
+		taskQueueClient: taskQueueClient,
+		hooks:           hooks,
+	}
+}
+
+func (s *Source) Name() string {
+	return SourceName
+}
+
+func (s *Source) TaskTypes() []sources.TaskType {
+	return []sources.TaskType{
+		{
+			Name:        TaskTypeSitemapSync,
+			EntityTypes: []string{EntityTypeSitemap},
+			Handle:      s.handleSitemapSync,
+		},
+		{
+			Name:        TaskTypeScraperSync,
+			EntityTypes: []string{EntityTypeBuilding},
+			Handle:      s.handleScraperSync,
+		},
+		{
+			Name:        TaskTypeAPISync,
+			EntityTypes: []string{EntityTypeAd},
+			Handle:      s.handleAPISync,
+		},
+		{
+			Name:        TaskTypeSearchSync,
+			EntityTypes: []string{EntityTypeSearch},
+			Handle:      s.handleSearchSync,
+		},
+	}
+}
+
+// RegisterSearchAreas registers the postcodes the search sync walks every
+// day. Postcodes dropped from the list stay registered until they are removed
+// through the admin API.
+func (s *Source) RegisterSearchAreas(ctx context.Context, postcodes []string) error {
+	entityIDs := make([]string, 0, len(postcodes))
+	for _, postcode := range postcodes {
+		if postcode = strings.TrimSpace(postcode); postcode != "" {
+			entityIDs = append(entityIDs, taskqueue.EntityPrefixSearch+postcode)
+		}
+	}
+	if len(entityIDs) == 0 {
+		return nil
+	}
+	if _, err := s.taskQueueClient.RegisterEntities(ctx, entityIDs, EntityTypeSearch, "daily"); err != nil {
+		return fmt.Errorf("register search areas: %w", err)
+	}
+	return nil
+}
+
+func (s *Source) ClassifyError(err error) error {
+	if errors.Is(err, ErrLocationNotFound) {
+		return taskqueue.NewPermanentError(err, "unknown postcode")
+	}
+	if errors.Is(err, client.ErrScraperForbidden) || errors.Is(err, client.ErrAuthFailed) ||
+		errors.Is(err, client.ErrNoTokens) {
+		return sources.NewSourceFailure(err)
+	}
+	if httpErr, ok := client.IsHTTPStatusError(err); ok {
+		return sources.ClassifyHTTPStatus(err, httpErr.StatusCode, httpErr.RetryAfter)
+	}
+	return err
+}
+
+func (s *Source) handleSitemapSync(ctx context.Context, logger *slog.Logger, _ taskqueuedb.TaskQueueTask) error {
+	result, err := s.service.SyncSitemap(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "shortcut sitemap sync failed", "error", err)
+		return fmt.Errorf("shortcut sitemap sync: %w", err)
+	}
+	if len(result.FetchErrors) > 0 {
+		logger.WarnContext(ctx, "shortcut sitemaps could not be fetched", "count", len(result.FetchErrors), "error", errors.Join(result.FetchErrors...))
+	}
+	buildingIDs, adIDs := result.BuildingIDs, result.AdIDs
+	var regErrors []error
+	if len(buildingIDs) > 0 {
+		if _, regErr := s.taskQueueClient.RegisterEntities(ctx, buildingIDs, EntityTypeBuilding, "daily"); regErr != nil {
+			logger.ErrorContext(ctx, "failed to register building entities", "error", regErr, "count", len(buildingIDs))
+			regErrors = append(regErrors, fmt.Errorf("register building entities: %w", regErr))
+		}
+	}
+	if len(adIDs) > 0 {
+		if _, regErr := s.taskQueueClient.RegisterEntities(ctx, adIDs, EntityTypeAd, "daily"); regErr != nil {
+			logger.ErrorContext(ctx, "failed to register ad entities", "error", regErr, "count", len(adIDs))
+			regErrors = append(regErrors, fmt.Errorf("register ad entities: %w", regErr))
+		}
+	}
+	if len(regErrors) > 0 && len(buildingIDs) == 0 && len(adIDs) == 0 {
+		return fmt.Errorf("shortcut sitemap sync: all entity registrations failed")
+	}
+	removed := s.hooks.SitemapSynced(ctx, logger, SourceName, result.Failed, taskqueue.SitemapEntities{
+		EntityTypeBuilding: buildingIDs,
+		EntityTypeAd:       adIDs,
+	}, EntityTypeAd)
+	if len(removed) > 0 {
+		removedIDs := make([]int64, 0, len(removed))
+		for _, id := range removed {
+			adID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				logger.WarnContext(ctx, "skipping removed ad with invalid ID", "ad_id", id)
+				continue
+			}
+			removedIDs = append(removedIDs, adID)
+		}
+		if count, err := s.service.DelistAds(ctx, removedIDs); err != nil {
+			logger.ErrorContext(ctx, "failed to delist removed ads", "count", len(removedIDs), "error", err)
+		} else {
+			logger.InfoContext(ctx, "removed ads delisted", "count", count)
+		}
+	}
+	logger.InfoContext(ctx, "shortcut sitemap sync completed", "buildings", len(buildingIDs), "ads", len(adIDs))
+	return nil
+}
+
+func (s *Source) handleScraperSync(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error {
+	entityType, externalID, err := sources.ParseEntityID(task.EntityID)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to parse entity ID", "entity_id", task.EntityID, "error", err)
+		return err
+	}
+	if entityType != "building" {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   fmt.Sprintf("expected building entity type for scraper, got: %s", entityType),
+		}
+	}
+	buildingID, err := uuid.Parse(externalID)
+	if err != nil {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   "invalid building UUID",
+			Err:      err,
+		}
+	}
+	if err := s.service.SyncBuilding(ctx, buildingID); err != nil {
+		logger.ErrorContext(ctx, "shortcut building sync failed", "building_id", buildingID, "error", err)
+		return fmt.Errorf("sync shortcut building %s: %w", buildingID, err)
+	}
+	logger.InfoContext(ctx, "shortcut building synced", "building_id", buildingID)
+	s.hooks.BuildingSynced(ctx, logger, SourceName, buildingID.String())
+	return nil
+}
+
+func (s *Source) handleAPISync(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error {
+	entityType, externalID, err := sources.ParseEntityID(task.EntityID)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to parse entity ID", "entity_id", task.EntityID, "error", err)
+		return err
+	}
+	if entityType != "ad" {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   fmt.Sprintf("expected ad entity type for API sync, got: %s", entityType),
+		}
+	}
+	adID, err := strconv.ParseInt(externalID, 10, 64)
+	if err != nil {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   "invalid ad ID",
+			Err:      err,
+		}
+	}
+	entry, err := s.service.SyncAd(ctx, adID)
+	if err != nil {
+		logger.ErrorContext(ctx, "shortcut ad sync failed", "ad_id", adID, "error", err)
+		return fmt.Errorf("sync shortcut ad %d: %w", adID, err)
+	}
+	logger.InfoContext(ctx, "shortcut ad synced", "ad_id", adID)
+	s.hooks.AdSynced(ctx, logger, SourceName, externalID, entry)
+	return nil
+}
+
+func (s *Source) handleSearchSync(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error {
+	entityType, postcode, err := sources.ParseEntityID(task.EntityID)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to parse entity ID", "entity_id", task.EntityID, "error", err)
+		return err
+	}
+	if entityType != "search" {
+		return &sources.EntityParseError{
+			EntityID: task.EntityID,
+			Reason:   fmt.Sprintf("expected search entity type for search sync, got: %s", entityType),
+		}
+	}
+	result, err := s.service.SyncSearch(ctx, postcode)
+	if err != nil {
+		logger.ErrorContext(ctx, "shortcut search sync failed", "postcode", postcode, "error", err)
+		return fmt.Errorf("shortcut search sync %s: %w", postcode, err)
+	}
+	logger.InfoContext(ctx, "shortcut search sync completed",
+		"postcode", postcode,
+		"ads", len(result.AdIDs),
+		"new_ads", len(result.NewAdIDs),
+		"failed", result.Failed,
+	)
+	return nil
+}
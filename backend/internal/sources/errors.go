@@ -0,0 +1,111 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"koditon-go/internal/taskqueue"
+)
+
+type EntityParseError struct {
+	EntityID string
+	Reason   string
+	Err      error
+}
+
+func (e *EntityParseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("parse entity %s: %s: %v", e.EntityID, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("parse entity %s: %s", e.EntityID, e.Reason)
+}
+
+func (e *EntityParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseEntityID splits a "type:value" entity ID.
+func ParseEntityID(entityID string) (entityType, value string, err error) {
+	idx := strings.Index(entityID, ":")
+	if idx == -1 || idx == 0 || idx == len(entityID)-1 {
+		return "", "", &EntityParseError{
+			EntityID: entityID,
+			Reason:   "expected 'type:value' format",
+		}
+	}
+	return entityID[:idx], entityID[idx+1:], nil
+}
+
+// SourceFailureError marks an error that says the source is failing rather
+// than the task, such as a 5xx, a 429 or a block. Circuit breakers count only
+// these, so that errors of our own, like a database blip, cannot trip them.
+type SourceFailureError struct {
+	Err error
+}
+
+func (e *SourceFailureError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SourceFailureError) Unwrap() error {
+	return e.Err
+}
+
+// NewSourceFailure classifies err as a retryable failure of the source.
+func NewSourceFailure(err error) error {
+	return taskqueue.NewRetryableError(&SourceFailureError{Err: err})
+}
+
+// NewSourceFailureWithDelay is NewSourceFailure retried no sooner than
+// retryAfter.
+func NewSourceFailureWithDelay(err error, retryAfter time.Duration) error {
+	return taskqueue.NewRetryableErrorWithDelay(&SourceFailureError{Err: err}, retryAfterSeconds(retryAfter))
+}
+
+// IsSourceFailure reports whether err was classified as a failure of the
+// source.
+func IsSourceFailure(err error) bool {
+	var sourceErr *SourceFailureError
+	return errors.As(err, &sourceErr)
+}
+
+// defaultRateLimitDelay is how long to back off after a 429 without a
+// Retry-After.
+const defaultRateLimitDelay = time.Minute
+
+// ClassifyHTTPStatus classifies an error from an HTTP response. retryAfter is
+// the delay the server asked for, zero when it did not; the task is retried
+// no sooner than that.
+func ClassifyHTTPStatus(err error, statusCode int, retryAfter time.Duration) error {
+	switch {
+	case statusCode == http.StatusNotFound:
+		return taskqueue.NewPermanentError(err, "resource not found")
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		// a rejected session or a block concerns the whole source, the task is
+		// retried once the circuit breaker of the source lets it through
+		return NewSourceFailure(err)
+	case statusCode == http.StatusTooManyRequests:
+		if retryAfter <= 0 {
+			retryAfter = defaultRateLimitDelay
+		}
+		return NewSourceFailureWithDelay(err, retryAfter)
+	case statusCode >= 400 && statusCode < 500:
+		return taskqueue.NewPermanentError(err, fmt.Sprintf("client error: %d", statusCode))
+	case statusCode >= 500:
+		if retryAfter > 0 {
+			return NewSourceFailureWithDelay(err, retryAfter)
+		}
+		return NewSourceFailure(err)
+	}
+	return err
+}
+
+// retryAfterSeconds rounds up so that a sub-second Retry-After still delays
+// the retry.
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,52 @@
+package sources
+
+import (
+	"fmt"
+)
+
+type registration struct {
+	source   Source
+	taskType TaskType
+}
+
+// Registry maps task types to the source handling them.
+type Registry struct {
+	sources   []Source
+	taskTypes map[string]registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		taskTypes: make(map[string]registration),
+	}
+}
+
+// Register adds the task types of a source. A task type can only be handled
+// by one source.
+func (r *Registry) Register(source Source) error {
+	taskTypes := source.TaskTypes()
+	for _, tt := range taskTypes {
+		if tt.Name == "" || tt.Handle == nil {
+			return fmt.Errorf("source %s: task type %q has no name or handler", source.Name(), tt.Name)
+		}
+		if existing, ok := r.taskTypes[tt.Name]; ok {
+			return fmt.Errorf("source %s: task type %s already registered by %s", source.Name(), tt.Name, existing.source.Name())
+		}
+	}
+	for _, tt := range taskTypes {
+		r.taskTypes[tt.Name] = registration{source: source, taskType: tt}
+	}
+	r.sources = append(r.sources, source)
+	return nil
+}
+
+// Lookup returns the source and declaration of a task type.
+func (r *Registry) Lookup(taskType string) (Source, TaskType, bool) {
+	reg, ok := r.taskTypes[taskType]
+	return reg.source, reg.taskType, ok
+}
+
+// Sources returns the registered sources in registration order.
+func (r *Registry) Sources() []Source {
+	return r.sources
+}
@@ -0,0 +1,47 @@
+// Package sources defines what the task queue consumer needs from a package
+// that syncs an external source, so that each source stays self-contained.
+package sources
+
+import (
+	"context"
+	"log/slog"
+
+	"koditon-go/internal/listings"
+	"koditon-go/internal/taskqueue"
+	taskqueuedb "koditon-go/internal/taskqueue/db"
+)
+
+// Handler processes a task. Errors are classified by the source before the
+// worker decides whether to retry.
+type Handler func(ctx context.Context, logger *slog.Logger, task taskqueuedb.TaskQueueTask) error
+
+// TaskType is a task type a source handles and the entity types it runs for.
+// The consumer keeps task_type_entity_type_mapping in sync with EntityTypes.
+type TaskType struct {
+	Name        string
+	EntityTypes []string
+	Handle      Handler
+}
+
+type Source interface {
+	// Name identifies the source in logs and sitemap crawls, e.g. frontdoor.
+	Name() string
+	TaskTypes() []TaskType
+	// ClassifyError wraps the errors the source recognises with
+	// taskqueue.NewRetryableError or taskqueue.NewPermanentError and returns
+	// other errors unchanged.
+	ClassifyError(err error) error
+}
+
+// Hooks are the side effects sources trigger in the rest of the system once
+// they stored data. Failures are handled by the hooks, never by the source.
+type Hooks interface {
+	// AdSynced is called after an ad was synced with the history entry it
+	// recorded, nil when the ad did not change.
+	AdSynced(ctx context.Context, logger *slog.Logger, source, externalID string, entry *listings.HistoryEntry)
+	BuildingSynced(ctx context.Context, logger *slog.Logger, source, buildingID string)
+	// SitemapSynced records the crawl of a sitemap, failed being the number of
+	// entries that could not be stored, and returns the external IDs of the
+	// ads of adEntityType that disappeared since the previous crawl.
+	SitemapSynced(ctx context.Context, logger *slog.Logger, source string, failed int, entities taskqueue.SitemapEntities, adEntityType string) []string
+}
@@ -0,0 +1,245 @@
+package taskqueue
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	// BreakerClosed lets every task run.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen holds every task back until the open period ends.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets one probe task run at a time; it closes the
+	// breaker when it succeeds and opens it again when it fails.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerConfig trips a breaker once FailureRate of the last Window tasks
+// failed, counting only when at least MinTasks ran.
+type BreakerConfig struct {
+	FailureRate float64
+	Window      int
+	MinTasks    int
+	// OpenDuration is how long the breaker stays open after tripping. Every
+	// failed probe doubles it, up to MaxOpenDuration.
+	OpenDuration    time.Duration
+	MaxOpenDuration time.Duration
+}
+
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureRate:     0.5,
+		Window:          20,
+		MinTasks:        10,
+		OpenDuration:    5 * time.Minute,
+		MaxOpenDuration: time.Hour,
+	}
+}
+
+// breakerProbeHold is how long tasks are held back while a probe runs.
+const breakerProbeHold = 30 * time.Second
+
+// Breaker is the circuit breaker of an upstream source. Workers ask it before
+// running a task of the source and hold the task back while it is open, so
+// that an unavailable source does not use up the attempts of its tasks.
+type Breaker struct {
+	name   string
+	config BreakerConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	outcomes []bool
+	next     int
+	count    int
+	failures int
+	// openFor doubles with every failed probe
+	openFor      time.Duration
+	openedAt     time.Time
+	openUntil    time.Time
+	probeUntil   time.Time
+	probeTaskID  int64
+	trips        int
+	lastFailure  string
+	lastChangeAt time.Time
+}
+
+func NewBreaker(name string, config BreakerConfig, logger *slog.Logger) *Breaker {
+	defaults := DefaultBreakerConfig()
+	if config.FailureRate <= 0 || config.FailureRate > 1 {
+		config.FailureRate = defaults.FailureRate
+	}
+	if config.Window < 1 {
+		config.Window = defaults.Window
+	}
+	if config.MinTasks < 1 || config.MinTasks > config.Window {
+		config.MinTasks = min(defaults.MinTasks, config.Window)
+	}
+	if config.OpenDuration <= 0 {
+		config.OpenDuration = defaults.OpenDuration
+	}
+	if config.MaxOpenDuration < config.OpenDuration {
+		config.MaxOpenDuration = config.OpenDuration
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Breaker{
+		name:     name,
+		config:   config,
+		logger:   logger.With("breaker", name),
+		now:      time.Now,
+		state:    BreakerClosed,
+		outcomes: make([]bool, config.Window),
+		openFor:  config.OpenDuration,
+	}
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow reports whether the task may run now. When it may not, it returns how
+// long to hold the task back. Once the open period ends a single probe is let
+// through; a probe that is not recorded within the open period is given up
+// on and another one is let through.
+func (b *Breaker) Allow(taskID int64) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	switch b.state {
+	case BreakerOpen:
+		if now.Before(b.openUntil) {
+			return b.openUntil.Sub(now), false
+		}
+		b.setState(BreakerHalfOpen, now)
+		b.probeUntil = now.Add(b.config.OpenDuration)
+		b.probeTaskID = taskID
+		return 0, true
+	case BreakerHalfOpen:
+		if now.Before(b.probeUntil) {
+			return breakerProbeHold, false
+		}
+		b.probeUntil = now.Add(b.config.OpenDuration)
+		b.probeTaskID = taskID
+		return 0, true
+	}
+	return 0, true
+}
+
+// Record counts the outcome of a task Allow let through. failure is the error
+// of a task that failed because of the source, nil when the task succeeded or
+// failed for reasons of its own. While half-open only the probe counts.
+func (b *Breaker) Record(taskID int64, failure error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if failure != nil {
+		b.lastFailure = failure.Error()
+	}
+	switch b.state {
+	case BreakerOpen:
+		// a task that started before the breaker tripped
+		return
+	case BreakerHalfOpen:
+		if taskID != b.probeTaskID {
+			// a task let through before the probe
+			return
+		}
+		if failure != nil {
+			b.openFor = min(2*b.openFor, b.config.MaxOpenDuration)
+			b.open(now)
+			return
+		}
+		b.openFor = b.config.OpenDuration
+		b.reset()
+		b.setState(BreakerClosed, now)
+		b.logger.Info("circuit breaker closed")
+		return
+	}
+	if b.count == len(b.outcomes) && b.outcomes[b.next] {
+		b.failures--
+	}
+	b.outcomes[b.next] = failure != nil
+	b.next = (b.next + 1) % len(b.outcomes)
+	b.count = min(b.count+1, len(b.outcomes))
+	if failure != nil {
+		b.failures++
+	}
+	if b.count >= b.config.MinTasks && float64(b.failures)/float64(b.count) >= b.config.FailureRate {
+		b.trips++
+		b.open(now)
+	}
+}
+
+// Paused reports whether the breaker holds back every task, so that a queue
+// with only tasks of the source need not be read.
+func (b *Breaker) Paused() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == BreakerOpen && b.now().Before(b.openUntil)
+}
+
+func (b *Breaker) open(now time.Time) {
+	b.reset()
+	b.openedAt = now
+	b.openUntil = now.Add(b.openFor)
+	b.setState(BreakerOpen, now)
+	b.logger.Warn("circuit breaker opened",
+		"open_until", b.openUntil,
+		"last_failure", b.lastFailure,
+	)
+}
+
+func (b *Breaker) reset() {
+	clear(b.outcomes)
+	b.next, b.count, b.failures = 0, 0, 0
+}
+
+func (b *Breaker) setState(state BreakerState, now time.Time) {
+	b.state = state
+	b.lastChangeAt = now
+}
+
+type BreakerStatus struct {
+	Name  string
+	State BreakerState
+	// Tasks and Failures count the outcomes in the window of a closed
+	// breaker.
+	Tasks    int
+	Failures int
+	Trips    int
+	// OpenedAt and OpenUntil are set once the breaker tripped.
+	OpenedAt     *time.Time
+	OpenUntil    *time.Time
+	LastFailure  string
+	LastChangeAt *time.Time
+}
+
+func (b *Breaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := BreakerStatus{
+		Name:        b.name,
+		State:       b.state,
+		Tasks:       b.count,
+		Failures:    b.failures,
+		Trips:       b.trips,
+		LastFailure: b.lastFailure,
+	}
+	if !b.openedAt.IsZero() {
+		openedAt, openUntil := b.openedAt, b.openUntil
+		status.OpenedAt = &openedAt
+		status.OpenUntil = &openUntil
+	}
+	if !b.lastChangeAt.IsZero() {
+		lastChangeAt := b.lastChangeAt
+		status.LastChangeAt = &lastChangeAt
+	}
+	return status
+}
@@ -10,6 +10,18 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CronJob struct {
+	Jobid    int64       `db:"jobid" json:"jobid"`
+	Schedule string      `db:"schedule" json:"schedule"`
+	Command  string      `db:"command" json:"command"`
+	Nodename string      `db:"nodename" json:"nodename"`
+	Nodeport int64       `db:"nodeport" json:"nodeport"`
+	Database string      `db:"database" json:"database"`
+	Username string      `db:"username" json:"username"`
+	Active   bool        `db:"active" json:"active"`
+	Jobname  pgtype.Text `db:"jobname" json:"jobname"`
+}
+
 // Stores tasks that have exhausted all retry attempts for debugging and manual reprocessing.
 type TaskQueueDeadLetterQueue struct {
 	DlqID          int64       `db:"dlq_id" json:"dlq_id"`
@@ -42,6 +54,42 @@ type TaskQueueEntityRegistry struct {
 	Metadata           []byte             `db:"metadata" json:"metadata"`
 	CreatedAt          pgtype.Timestamptz `db:"created_at" json:"created_at"`
 	UpdatedAt          pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	ManualOverride     bool               `db:"manual_override" json:"manual_override"`
+}
+
+type TaskQueueSitemapCrawl struct {
+	CrawlID         int64              `db:"crawl_id" json:"crawl_id"`
+	Source          string             `db:"source" json:"source"`
+	PreviousCrawlID pgtype.Int8        `db:"previous_crawl_id" json:"previous_crawl_id"`
+	EntryCount      int64              `db:"entry_count" json:"entry_count"`
+	AddedCount      int64              `db:"added_count" json:"added_count"`
+	RemovedCount    int64              `db:"removed_count" json:"removed_count"`
+	CrawledAt       pgtype.Timestamptz `db:"crawled_at" json:"crawled_at"`
+}
+
+type TaskQueueSitemapCrawlChange struct {
+	CrawlID    int64  `db:"crawl_id" json:"crawl_id"`
+	EntityID   string `db:"entity_id" json:"entity_id"`
+	EntityType string `db:"entity_type" json:"entity_type"`
+	Change     string `db:"change" json:"change"`
+}
+
+type TaskQueueSitemapCrawlEntry struct {
+	CrawlID    int64  `db:"crawl_id" json:"crawl_id"`
+	EntityID   string `db:"entity_id" json:"entity_id"`
+	EntityType string `db:"entity_type" json:"entity_type"`
+}
+
+type TaskQueueTaskTypeEntityTypeMapping struct {
+	TaskType   string `db:"task_type" json:"task_type"`
+	EntityType string `db:"entity_type" json:"entity_type"`
+}
+
+type TaskQueueTaskTypeQueue struct {
+	TaskType           string             `db:"task_type" json:"task_type"`
+	QueueName          string             `db:"queue_name" json:"queue_name"`
+	TaskTimeoutSeconds int32              `db:"task_timeout_seconds" json:"task_timeout_seconds"`
+	UpdatedAt          pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
 type TaskQueueTask struct {
@@ -60,6 +108,7 @@ type TaskQueueTask struct {
 	CompletedAt    pgtype.Timestamptz `db:"completed_at" json:"completed_at"`
 	RunOn          pgtype.Date        `db:"run_on" json:"run_on"`
 	QueueMessageID pgtype.Int8        `db:"queue_message_id" json:"queue_message_id"`
+	QueueName      pgtype.Text        `db:"queue_name" json:"queue_name"`
 	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
@@ -6,7 +6,8 @@ SELECT
     scheduling_strategy,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    manual_override
 FROM task_queue.entity_registry
 WHERE entity_id = $1;
 
@@ -18,10 +19,28 @@ SELECT
     scheduling_strategy,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    manual_override
 FROM task_queue.entity_registry
 ORDER BY entity_id;
 
+-- name: ListEntitiesFiltered :many
+SELECT
+    entity_id,
+    entity_type,
+    status,
+    scheduling_strategy,
+    metadata,
+    created_at,
+    updated_at,
+    manual_override
+FROM task_queue.entity_registry
+WHERE (sqlc.narg('entity_type')::text IS NULL OR entity_type = sqlc.narg('entity_type')::text)
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+    AND (sqlc.narg('scheduling_strategy')::text IS NULL OR scheduling_strategy = sqlc.narg('scheduling_strategy')::text)
+ORDER BY entity_id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
 -- name: ListActiveEntities :many
 SELECT
     entity_id,
@@ -30,7 +49,8 @@ SELECT
     scheduling_strategy,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    manual_override
 FROM task_queue.entity_registry
 WHERE status = 'active'
 ORDER BY entity_id;
@@ -62,12 +82,23 @@ SET
     updated_at = NOW()
 RETURNING *;
 
--- name: UpdateEntityStatus :exec
+-- name: UpdateEntityStatus :one
 UPDATE task_queue.entity_registry
 SET
     status = $2,
+    manual_override = TRUE,
     updated_at = NOW()
-WHERE entity_id = $1;
+WHERE entity_id = $1
+RETURNING entity_id;
+
+-- name: UpdateEntitySchedulingStrategy :one
+UPDATE task_queue.entity_registry
+SET
+    scheduling_strategy = $2,
+    manual_override = TRUE,
+    updated_at = NOW()
+WHERE entity_id = $1
+RETURNING entity_id;
 
 -- name: DeleteEntity :exec
 DELETE FROM task_queue.entity_registry
@@ -124,6 +155,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -145,6 +177,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -168,6 +201,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -190,6 +224,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -213,6 +248,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -220,6 +256,32 @@ WHERE entity_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
+-- name: ListTasksFiltered :many
+SELECT
+    task_id,
+    entity_id,
+    task_type,
+    status,
+    priority,
+    attempt,
+    max_attempts,
+    last_error,
+    worker_id,
+    scheduled_for,
+    started_at,
+    completed_at,
+    run_on,
+    queue_message_id,
+    queue_name,
+    created_at,
+    updated_at
+FROM task_queue.task
+WHERE (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+    AND (sqlc.narg('task_type')::text IS NULL OR task_type = sqlc.narg('task_type')::text)
+    AND (sqlc.narg('entity_id')::text IS NULL OR entity_id = sqlc.narg('entity_id')::text)
+ORDER BY priority DESC, created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
 -- name: ListTasksByWorker :many
 SELECT
     task_id,
@@ -236,6 +298,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -260,6 +323,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -284,6 +348,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -308,6 +373,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at,
     EXTRACT(EPOCH FROM (NOW() - started_at))::INT AS stuck_seconds
@@ -323,6 +389,16 @@ SET
     updated_at = NOW()
 WHERE task_id = $1;
 
+-- name: CancelPendingTask :one
+-- Stops a task that has not started yet and returns its queue message.
+UPDATE task_queue.task
+SET
+    status = 'stopped',
+    updated_at = NOW()
+WHERE task_id = $1
+    AND status = 'pending'
+RETURNING queue_name, queue_message_id;
+
 -- name: UpdateTaskPriority :exec
 UPDATE task_queue.task
 SET
@@ -367,6 +443,7 @@ SET
     scheduled_for = $2,
     started_at = NULL,
     queue_message_id = NULL,
+    queue_name = NULL,
     updated_at = NOW()
 WHERE task_id = $1;
 
@@ -379,6 +456,7 @@ SET
     scheduled_for = $2,
     started_at = NULL,
     queue_message_id = NULL,
+    queue_name = NULL,
     updated_at = NOW()
 WHERE task_id = $1;
 
@@ -389,6 +467,17 @@ SET
     updated_at = NOW()
 WHERE task_id = $1;
 
+-- name: HoldQueuedTasks :execrows
+-- Hides the queued messages of pending tasks of the task types until the
+-- hold is over, leaving alone the ones scheduled after it.
+SELECT pgmq.set_vt(t.queue_name, t.queue_message_id, $3::int)
+FROM task_queue.task t
+WHERE t.queue_name = $1::text
+    AND t.task_type = ANY($2::text[])
+    AND t.status = 'pending'
+    AND t.queue_message_id IS NOT NULL
+    AND t.scheduled_for < NOW() + make_interval(secs => $3::int);
+
 -- name: DeleteTask :exec
 DELETE FROM task_queue.task
 WHERE task_id = $1;
@@ -428,6 +517,15 @@ SELECT
     COUNT(*) FILTER (WHERE status IN ('failed', 'stopped') AND completed_at >= CURRENT_DATE) AS failed_today
 FROM task_queue.task;
 
+-- name: GetTaskCountsByTypeAndStatus :many
+SELECT
+    task_type,
+    status,
+    COUNT(*) AS count
+FROM task_queue.task
+GROUP BY task_type, status
+ORDER BY task_type, status;
+
 -- name: ListActiveWorkers :many
 SELECT
     worker_id,
@@ -474,6 +572,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -520,9 +619,43 @@ SELECT task_queue.fnc__enqueue_task($1::bigint) AS message_id;
 -- name: CallScheduleDailySyncs :one
 SELECT task_queue.fnc__schedule_daily_syncs($1::text) AS count;
 
+-- name: ScheduleSingletonTask :one
+SELECT
+    pending_task_id::bigint AS task_id,
+    created::boolean AS created
+FROM task_queue.fnc__schedule_singleton_task(sqlc.arg('entity_id')::text, sqlc.arg('task_type')::text, sqlc.arg('priority')::int, sqlc.arg('max_attempts')::int);
+
 -- name: CallRequeueStuckTasks :one
 SELECT task_queue.fnc__requeue_stuck_tasks() AS count;
 
+-- name: GetSyncStatistics :one
+SELECT
+    total_entities,
+    active_entities,
+    stopped_entities,
+    total_tasks,
+    pending_tasks,
+    processing_tasks,
+    completed_tasks_today,
+    failed_tasks_today,
+    avg_task_duration_seconds::float8 AS avg_task_duration_seconds,
+    sitemap_last_sync,
+    sitemap_last_status
+FROM task_queue.fnc__get_sync_statistics();
+
+-- name: GetEntitySyncStatus :one
+SELECT
+    entity_id,
+    entity_status,
+    last_task_id,
+    last_task_status,
+    last_task_completed_at,
+    last_task_error,
+    total_completed_count,
+    total_failed_count,
+    success_rate::float8 AS success_rate
+FROM task_queue.fnc__get_entity_sync_status($1::text);
+
 -- ============================================================================
 -- Dead Letter Queue (DLQ) Queries
 -- ============================================================================
@@ -682,6 +815,50 @@ WHERE requeued_at IS NULL
 GROUP BY task_type
 ORDER BY count DESC;
 
+-- name: ListDLQEntriesMatching :many
+SELECT
+    dlq_id,
+    original_task_id,
+    entity_id,
+    task_type,
+    priority,
+    total_attempts,
+    first_error,
+    last_error,
+    error_history,
+    task_metadata,
+    original_created_at,
+    first_attempted_at,
+    last_attempted_at,
+    moved_to_dlq_at,
+    requeued_at,
+    requeue_count
+FROM task_queue.dead_letter_queue
+WHERE (sqlc.narg('task_type')::text IS NULL OR task_type = sqlc.narg('task_type')::text)
+    AND (sqlc.narg('entity_prefix')::text IS NULL OR starts_with(entity_id, sqlc.narg('entity_prefix')::text))
+    AND (sqlc.narg('error_substring')::text IS NULL
+        OR strpos(lower(last_error), lower(sqlc.narg('error_substring')::text)) > 0)
+    AND (NOT sqlc.arg('pending_only')::bool OR requeued_at IS NULL)
+ORDER BY moved_to_dlq_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountDLQEntriesMatching :one
+SELECT COUNT(*) AS count
+FROM task_queue.dead_letter_queue
+WHERE (sqlc.narg('task_type')::text IS NULL OR task_type = sqlc.narg('task_type')::text)
+    AND (sqlc.narg('entity_prefix')::text IS NULL OR starts_with(entity_id, sqlc.narg('entity_prefix')::text))
+    AND (sqlc.narg('error_substring')::text IS NULL
+        OR strpos(lower(last_error), lower(sqlc.narg('error_substring')::text)) > 0)
+    AND (NOT sqlc.arg('pending_only')::bool OR requeued_at IS NULL);
+
+-- name: DeleteDLQEntriesMatching :execrows
+DELETE FROM task_queue.dead_letter_queue
+WHERE (sqlc.narg('task_type')::text IS NULL OR task_type = sqlc.narg('task_type')::text)
+    AND (sqlc.narg('entity_prefix')::text IS NULL OR starts_with(entity_id, sqlc.narg('entity_prefix')::text))
+    AND (sqlc.narg('error_substring')::text IS NULL
+        OR strpos(lower(last_error), lower(sqlc.narg('error_substring')::text)) > 0)
+    AND (NOT sqlc.arg('pending_only')::bool OR requeued_at IS NULL);
+
 -- name: DeleteDLQEntry :exec
 DELETE FROM task_queue.dead_letter_queue
 WHERE dlq_id = $1;
@@ -696,3 +873,91 @@ SELECT task_queue.fnc__move_to_dlq($1::bigint, $2::jsonb) AS dlq_id;
 
 -- name: CallRequeueFromDLQ :one
 SELECT task_queue.fnc__requeue_from_dlq($1::bigint, $2::int, $3::int) AS task_id;
+
+-- name: ListCronJobs :many
+SELECT
+    jobname,
+    active
+FROM cron.job
+ORDER BY jobname;
+
+-- name: RecordSitemapCrawl :one
+SELECT
+    crawl_id,
+    source,
+    previous_crawl_id,
+    entry_count,
+    added_count,
+    removed_count,
+    crawled_at
+FROM task_queue.fnc__record_sitemap_crawl(sqlc.arg('source')::text, sqlc.arg('entity_ids')::text[], sqlc.arg('entity_types')::text[]);
+
+-- name: GetSitemapCrawl :one
+SELECT
+    crawl_id,
+    source,
+    previous_crawl_id,
+    entry_count,
+    added_count,
+    removed_count,
+    crawled_at
+FROM task_queue.sitemap_crawl
+WHERE crawl_id = $1;
+
+-- name: ListSitemapCrawls :many
+SELECT
+    crawl_id,
+    source,
+    previous_crawl_id,
+    entry_count,
+    added_count,
+    removed_count,
+    crawled_at
+FROM task_queue.sitemap_crawl
+WHERE (sqlc.narg('source')::text IS NULL OR source = sqlc.narg('source')::text)
+ORDER BY crawl_id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListSitemapCrawlChanges :many
+SELECT
+    crawl_id,
+    entity_id,
+    entity_type,
+    change
+FROM task_queue.sitemap_crawl_change
+WHERE crawl_id = sqlc.arg('crawl_id')
+    AND (sqlc.narg('change')::text IS NULL OR change = sqlc.narg('change')::text)
+    AND (sqlc.narg('entity_type')::text IS NULL OR entity_type = sqlc.narg('entity_type')::text)
+ORDER BY entity_type, entity_id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListSitemapCrawlChangedEntityIDs :many
+SELECT entity_id
+FROM task_queue.sitemap_crawl_change
+WHERE crawl_id = $1
+    AND change = $2
+    AND entity_type = $3
+ORDER BY entity_id;
+
+-- name: EnsureTaskTypeMapping :exec
+INSERT INTO task_queue.task_type_entity_type_mapping (task_type, entity_type)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ReplaceTaskTypeQueues :exec
+WITH removed AS (
+    DELETE FROM task_queue.task_type_queue
+    WHERE task_type <> ALL(sqlc.arg('task_types')::text[])
+)
+INSERT INTO task_queue.task_type_queue (task_type, queue_name, task_timeout_seconds)
+SELECT
+    unnest(sqlc.arg('task_types')::text[]),
+    unnest(sqlc.arg('queue_names')::text[]),
+    unnest(sqlc.arg('task_timeout_seconds')::int[])
+ON CONFLICT (task_type) DO UPDATE
+SET queue_name = EXCLUDED.queue_name,
+    task_timeout_seconds = EXCLUDED.task_timeout_seconds,
+    updated_at = NOW();
+
+-- name: CallReroutePendingTasks :one
+SELECT task_queue.fnc__reroute_pending_tasks() AS count;
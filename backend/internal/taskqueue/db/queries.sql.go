@@ -83,6 +83,17 @@ func (q *Queries) CallRequeueStuckTasks(ctx context.Context) (int32, error) {
 	return count, err
 }
 
+const callReroutePendingTasks = `-- name: CallReroutePendingTasks :one
+SELECT task_queue.fnc__reroute_pending_tasks() AS count
+`
+
+func (q *Queries) CallReroutePendingTasks(ctx context.Context) (int32, error) {
+	row := q.db.QueryRow(ctx, callReroutePendingTasks)
+	var count int32
+	err := row.Scan(&count)
+	return count, err
+}
+
 const callScheduleDailySyncs = `-- name: CallScheduleDailySyncs :one
 SELECT task_queue.fnc__schedule_daily_syncs($1::text) AS count
 `
@@ -94,6 +105,29 @@ func (q *Queries) CallScheduleDailySyncs(ctx context.Context, dollar_1 string) (
 	return count, err
 }
 
+const cancelPendingTask = `-- name: CancelPendingTask :one
+UPDATE task_queue.task
+SET
+    status = 'stopped',
+    updated_at = NOW()
+WHERE task_id = $1
+    AND status = 'pending'
+RETURNING queue_name, queue_message_id
+`
+
+type CancelPendingTaskRow struct {
+	QueueName      pgtype.Text `db:"queue_name" json:"queue_name"`
+	QueueMessageID pgtype.Int8 `db:"queue_message_id" json:"queue_message_id"`
+}
+
+// Stops a task that has not started yet and returns its queue message.
+func (q *Queries) CancelPendingTask(ctx context.Context, taskID int64) (CancelPendingTaskRow, error) {
+	row := q.db.QueryRow(ctx, cancelPendingTask, taskID)
+	var i CancelPendingTaskRow
+	err := row.Scan(&i.QueueName, &i.QueueMessageID)
+	return i, err
+}
+
 const countDLQEntries = `-- name: CountDLQEntries :one
 SELECT
     COUNT(*) AS total,
@@ -150,6 +184,23 @@ func (q *Queries) CountDLQEntriesByTaskType(ctx context.Context) ([]CountDLQEntr
 	return items, nil
 }
 
+const countDLQEntriesMatching = `-- name: CountDLQEntriesMatching :one
+SELECT COUNT(*) AS count
+FROM task_queue.dead_letter_queue
+WHERE ($1::text IS NULL OR task_type = $1::text)
+    AND ($2::text IS NULL OR starts_with(entity_id, $2::text))
+    AND ($3::text IS NULL
+        OR strpos(lower(last_error), lower($3::text)) > 0)
+    AND (NOT $4::bool OR requeued_at IS NULL)
+`
+
+func (q *Queries) CountDLQEntriesMatching(ctx context.Context, taskType pgtype.Text, entityPrefix pgtype.Text, errorSubstring pgtype.Text, pendingOnly bool) (int64, error) {
+	row := q.db.QueryRow(ctx, countDLQEntriesMatching, taskType, entityPrefix, errorSubstring, pendingOnly)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countEntitiesByStatus = `-- name: CountEntitiesByStatus :one
 SELECT COUNT(*) AS count
 FROM task_queue.entity_registry
@@ -189,7 +240,7 @@ INSERT INTO task_queue.task (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING task_id, entity_id, task_type, status, priority, attempt, max_attempts, last_error, worker_id, scheduled_for, started_at, completed_at, run_on, queue_message_id, created_at, updated_at
+RETURNING task_id, entity_id, task_type, status, priority, attempt, max_attempts, last_error, worker_id, scheduled_for, started_at, completed_at, run_on, queue_message_id, queue_name, created_at, updated_at
 `
 
 func (q *Queries) CreateTask(ctx context.Context, entityID string, taskType string, status string, priority int32, attempt int32, maxAttempts int32, scheduledFor time.Time, runOn pgtype.Date) (TaskQueueTask, error) {
@@ -219,6 +270,7 @@ func (q *Queries) CreateTask(ctx context.Context, entityID string, taskType stri
 		&i.CompletedAt,
 		&i.RunOn,
 		&i.QueueMessageID,
+		&i.QueueName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -238,7 +290,7 @@ INSERT INTO task_queue.task (
 ) VALUES (
     $1, $2, 'pending', $3, 0, $4, $5, $6
 )
-RETURNING task_id, entity_id, task_type, status, priority, attempt, max_attempts, last_error, worker_id, scheduled_for, started_at, completed_at, run_on, queue_message_id, created_at, updated_at
+RETURNING task_id, entity_id, task_type, status, priority, attempt, max_attempts, last_error, worker_id, scheduled_for, started_at, completed_at, run_on, queue_message_id, queue_name, created_at, updated_at
 `
 
 func (q *Queries) CreateTaskWithPriority(ctx context.Context, entityID string, taskType string, priority int32, maxAttempts int32, scheduledFor time.Time, runOn pgtype.Date) (TaskQueueTask, error) {
@@ -266,12 +318,30 @@ func (q *Queries) CreateTaskWithPriority(ctx context.Context, entityID string, t
 		&i.CompletedAt,
 		&i.RunOn,
 		&i.QueueMessageID,
+		&i.QueueName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const deleteDLQEntriesMatching = `-- name: DeleteDLQEntriesMatching :execrows
+DELETE FROM task_queue.dead_letter_queue
+WHERE ($1::text IS NULL OR task_type = $1::text)
+    AND ($2::text IS NULL OR starts_with(entity_id, $2::text))
+    AND ($3::text IS NULL
+        OR strpos(lower(last_error), lower($3::text)) > 0)
+    AND (NOT $4::bool OR requeued_at IS NULL)
+`
+
+func (q *Queries) DeleteDLQEntriesMatching(ctx context.Context, taskType pgtype.Text, entityPrefix pgtype.Text, errorSubstring pgtype.Text, pendingOnly bool) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDLQEntriesMatching, taskType, entityPrefix, errorSubstring, pendingOnly)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteDLQEntry = `-- name: DeleteDLQEntry :exec
 DELETE FROM task_queue.dead_letter_queue
 WHERE dlq_id = $1
@@ -344,6 +414,17 @@ func (q *Queries) DeleteTask(ctx context.Context, taskID int64) error {
 	return err
 }
 
+const ensureTaskTypeMapping = `-- name: EnsureTaskTypeMapping :exec
+INSERT INTO task_queue.task_type_entity_type_mapping (task_type, entity_type)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) EnsureTaskTypeMapping(ctx context.Context, taskType string, entityType string) error {
+	_, err := q.db.Exec(ctx, ensureTaskTypeMapping, taskType, entityType)
+	return err
+}
+
 const getDLQEntry = `-- name: GetDLQEntry :one
 SELECT
     dlq_id,
@@ -429,7 +510,8 @@ SELECT
     scheduling_strategy,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    manual_override
 FROM task_queue.entity_registry
 WHERE entity_id = $1
 `
@@ -445,6 +527,127 @@ func (q *Queries) GetEntity(ctx context.Context, entityID string) (TaskQueueEnti
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ManualOverride,
+	)
+	return i, err
+}
+
+const getEntitySyncStatus = `-- name: GetEntitySyncStatus :one
+SELECT
+    entity_id,
+    entity_status,
+    last_task_id,
+    last_task_status,
+    last_task_completed_at,
+    last_task_error,
+    total_completed_count,
+    total_failed_count,
+    success_rate::float8 AS success_rate
+FROM task_queue.fnc__get_entity_sync_status($1::text)
+`
+
+type GetEntitySyncStatusRow struct {
+	EntityID            pgtype.Text        `db:"entity_id" json:"entity_id"`
+	EntityStatus        pgtype.Text        `db:"entity_status" json:"entity_status"`
+	LastTaskID          pgtype.Int8        `db:"last_task_id" json:"last_task_id"`
+	LastTaskStatus      pgtype.Text        `db:"last_task_status" json:"last_task_status"`
+	LastTaskCompletedAt pgtype.Timestamptz `db:"last_task_completed_at" json:"last_task_completed_at"`
+	LastTaskError       pgtype.Text        `db:"last_task_error" json:"last_task_error"`
+	TotalCompletedCount pgtype.Int8        `db:"total_completed_count" json:"total_completed_count"`
+	TotalFailedCount    pgtype.Int8        `db:"total_failed_count" json:"total_failed_count"`
+	SuccessRate         float64            `db:"success_rate" json:"success_rate"`
+}
+
+func (q *Queries) GetEntitySyncStatus(ctx context.Context, dollar1 string) (GetEntitySyncStatusRow, error) {
+	row := q.db.QueryRow(ctx, getEntitySyncStatus, dollar1)
+	var i GetEntitySyncStatusRow
+	err := row.Scan(
+		&i.EntityID,
+		&i.EntityStatus,
+		&i.LastTaskID,
+		&i.LastTaskStatus,
+		&i.LastTaskCompletedAt,
+		&i.LastTaskError,
+		&i.TotalCompletedCount,
+		&i.TotalFailedCount,
+		&i.SuccessRate,
+	)
+	return i, err
+}
+
+const getSitemapCrawl = `-- name: GetSitemapCrawl :one
+SELECT
+    crawl_id,
+    source,
+    previous_crawl_id,
+    entry_count,
+    added_count,
+    removed_count,
+    crawled_at
+FROM task_queue.sitemap_crawl
+WHERE crawl_id = $1
+`
+
+func (q *Queries) GetSitemapCrawl(ctx context.Context, crawlID int64) (TaskQueueSitemapCrawl, error) {
+	row := q.db.QueryRow(ctx, getSitemapCrawl, crawlID)
+	var i TaskQueueSitemapCrawl
+	err := row.Scan(
+		&i.CrawlID,
+		&i.Source,
+		&i.PreviousCrawlID,
+		&i.EntryCount,
+		&i.AddedCount,
+		&i.RemovedCount,
+		&i.CrawledAt,
+	)
+	return i, err
+}
+
+const getSyncStatistics = `-- name: GetSyncStatistics :one
+SELECT
+    total_entities,
+    active_entities,
+    stopped_entities,
+    total_tasks,
+    pending_tasks,
+    processing_tasks,
+    completed_tasks_today,
+    failed_tasks_today,
+    avg_task_duration_seconds::float8 AS avg_task_duration_seconds,
+    sitemap_last_sync,
+    sitemap_last_status
+FROM task_queue.fnc__get_sync_statistics()
+`
+
+type GetSyncStatisticsRow struct {
+	TotalEntities          pgtype.Int4        `db:"total_entities" json:"total_entities"`
+	ActiveEntities         pgtype.Int4        `db:"active_entities" json:"active_entities"`
+	StoppedEntities        pgtype.Int4        `db:"stopped_entities" json:"stopped_entities"`
+	TotalTasks             pgtype.Int4        `db:"total_tasks" json:"total_tasks"`
+	PendingTasks           pgtype.Int4        `db:"pending_tasks" json:"pending_tasks"`
+	ProcessingTasks        pgtype.Int4        `db:"processing_tasks" json:"processing_tasks"`
+	CompletedTasksToday    pgtype.Int4        `db:"completed_tasks_today" json:"completed_tasks_today"`
+	FailedTasksToday       pgtype.Int4        `db:"failed_tasks_today" json:"failed_tasks_today"`
+	AvgTaskDurationSeconds float64            `db:"avg_task_duration_seconds" json:"avg_task_duration_seconds"`
+	SitemapLastSync        pgtype.Timestamptz `db:"sitemap_last_sync" json:"sitemap_last_sync"`
+	SitemapLastStatus      pgtype.Text        `db:"sitemap_last_status" json:"sitemap_last_status"`
+}
+
+func (q *Queries) GetSyncStatistics(ctx context.Context) (GetSyncStatisticsRow, error) {
+	row := q.db.QueryRow(ctx, getSyncStatistics)
+	var i GetSyncStatisticsRow
+	err := row.Scan(
+		&i.TotalEntities,
+		&i.ActiveEntities,
+		&i.StoppedEntities,
+		&i.TotalTasks,
+		&i.PendingTasks,
+		&i.ProcessingTasks,
+		&i.CompletedTasksToday,
+		&i.FailedTasksToday,
+		&i.AvgTaskDurationSeconds,
+		&i.SitemapLastSync,
+		&i.SitemapLastStatus,
 	)
 	return i, err
 }
@@ -465,6 +668,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -489,6 +693,7 @@ func (q *Queries) GetTask(ctx context.Context, taskID int64) (TaskQueueTask, err
 		&i.CompletedAt,
 		&i.RunOn,
 		&i.QueueMessageID,
+		&i.QueueName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -511,6 +716,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -537,12 +743,53 @@ func (q *Queries) GetTaskByEntityAndDate(ctx context.Context, entityID string, t
 		&i.CompletedAt,
 		&i.RunOn,
 		&i.QueueMessageID,
+		&i.QueueName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const getTaskCountsByTypeAndStatus = `-- name: GetTaskCountsByTypeAndStatus :many
+SELECT
+    task_type,
+    status,
+    COUNT(*) AS count
+FROM task_queue.task
+GROUP BY task_type, status
+ORDER BY task_type, status
+`
+
+type GetTaskCountsByTypeAndStatusRow struct {
+	TaskType string `db:"task_type" json:"task_type"`
+	Status   string `db:"status" json:"status"`
+	Count    int64  `db:"count" json:"count"`
+}
+
+func (q *Queries) GetTaskCountsByTypeAndStatus(ctx context.Context) ([]GetTaskCountsByTypeAndStatusRow, error) {
+	rows, err := q.db.Query(ctx, getTaskCountsByTypeAndStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTaskCountsByTypeAndStatusRow{}
+	for rows.Next() {
+		var i GetTaskCountsByTypeAndStatusRow
+		if err := rows.Scan(
+			&i.TaskType,
+			&i.Status,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTaskStatusSummary = `-- name: GetTaskStatusSummary :one
 SELECT
     COUNT(*) FILTER (WHERE status = 'pending') AS pending,
@@ -596,6 +843,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -627,6 +875,7 @@ func (q *Queries) GetTasksByRunDate(ctx context.Context, runOn pgtype.Date) ([]T
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -640,6 +889,26 @@ func (q *Queries) GetTasksByRunDate(ctx context.Context, runOn pgtype.Date) ([]T
 	return items, nil
 }
 
+const holdQueuedTasks = `-- name: HoldQueuedTasks :execrows
+SELECT pgmq.set_vt(t.queue_name, t.queue_message_id, $3::int)
+FROM task_queue.task t
+WHERE t.queue_name = $1::text
+    AND t.task_type = ANY($2::text[])
+    AND t.status = 'pending'
+    AND t.queue_message_id IS NOT NULL
+    AND t.scheduled_for < NOW() + make_interval(secs => $3::int)
+`
+
+// Hides the queued messages of pending tasks of the task types until the
+// hold is over, leaving alone the ones scheduled after it.
+func (q *Queries) HoldQueuedTasks(ctx context.Context, queueName string, taskTypes []string, seconds int32) (int64, error) {
+	result, err := q.db.Exec(ctx, holdQueuedTasks, queueName, taskTypes, seconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const insertIntoDLQ = `-- name: InsertIntoDLQ :one
 
 INSERT INTO task_queue.dead_letter_queue (
@@ -709,7 +978,8 @@ SELECT
     scheduling_strategy,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    manual_override
 FROM task_queue.entity_registry
 WHERE status = 'active'
 ORDER BY entity_id
@@ -732,6 +1002,7 @@ func (q *Queries) ListActiveEntities(ctx context.Context) ([]TaskQueueEntityRegi
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ManualOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -788,6 +1059,42 @@ func (q *Queries) ListActiveWorkers(ctx context.Context) ([]ListActiveWorkersRow
 	return items, nil
 }
 
+const listCronJobs = `-- name: ListCronJobs :many
+SELECT
+    jobname,
+    active
+FROM cron.job
+ORDER BY jobname
+`
+
+type ListCronJobsRow struct {
+	Jobname pgtype.Text `db:"jobname" json:"jobname"`
+	Active  bool        `db:"active" json:"active"`
+}
+
+func (q *Queries) ListCronJobs(ctx context.Context) ([]ListCronJobsRow, error) {
+	rows, err := q.db.Query(ctx, listCronJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCronJobsRow{}
+	for rows.Next() {
+		var i ListCronJobsRow
+		if err := rows.Scan(
+			&i.Jobname,
+			&i.Active,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDLQEntries = `-- name: ListDLQEntries :many
 SELECT
     dlq_id,
@@ -971,6 +1278,78 @@ func (q *Queries) ListDLQEntriesByTaskType(ctx context.Context, taskType string,
 	return items, nil
 }
 
+const listDLQEntriesMatching = `-- name: ListDLQEntriesMatching :many
+SELECT
+    dlq_id,
+    original_task_id,
+    entity_id,
+    task_type,
+    priority,
+    total_attempts,
+    first_error,
+    last_error,
+    error_history,
+    task_metadata,
+    original_created_at,
+    first_attempted_at,
+    last_attempted_at,
+    moved_to_dlq_at,
+    requeued_at,
+    requeue_count
+FROM task_queue.dead_letter_queue
+WHERE ($1::text IS NULL OR task_type = $1::text)
+    AND ($2::text IS NULL OR starts_with(entity_id, $2::text))
+    AND ($3::text IS NULL
+        OR strpos(lower(last_error), lower($3::text)) > 0)
+    AND (NOT $4::bool OR requeued_at IS NULL)
+ORDER BY moved_to_dlq_at DESC
+LIMIT $5 OFFSET $6
+`
+
+func (q *Queries) ListDLQEntriesMatching(ctx context.Context, taskType pgtype.Text, entityPrefix pgtype.Text, errorSubstring pgtype.Text, pendingOnly bool, limit int64, offset int64) ([]TaskQueueDeadLetterQueue, error) {
+	rows, err := q.db.Query(ctx, listDLQEntriesMatching,
+		taskType,
+		entityPrefix,
+		errorSubstring,
+		pendingOnly,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskQueueDeadLetterQueue{}
+	for rows.Next() {
+		var i TaskQueueDeadLetterQueue
+		if err := rows.Scan(
+			&i.DlqID,
+			&i.OriginalTaskID,
+			&i.EntityID,
+			&i.TaskType,
+			&i.Priority,
+			&i.TotalAttempts,
+			&i.FirstError,
+			&i.LastError,
+			&i.ErrorHistory,
+			&i.TaskMetadata,
+			&i.OriginalCreatedAt,
+			&i.FirstAttemptedAt,
+			&i.LastAttemptedAt,
+			&i.MovedToDlqAt,
+			&i.RequeuedAt,
+			&i.RequeueCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDLQEntriesNotRequeued = `-- name: ListDLQEntriesNotRequeued :many
 SELECT
     dlq_id,
@@ -1040,7 +1419,8 @@ SELECT
     scheduling_strategy,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    manual_override
 FROM task_queue.entity_registry
 ORDER BY entity_id
 `
@@ -1062,6 +1442,60 @@ func (q *Queries) ListEntities(ctx context.Context) ([]TaskQueueEntityRegistry,
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ManualOverride,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntitiesFiltered = `-- name: ListEntitiesFiltered :many
+SELECT
+    entity_id,
+    entity_type,
+    status,
+    scheduling_strategy,
+    metadata,
+    created_at,
+    updated_at,
+    manual_override
+FROM task_queue.entity_registry
+WHERE ($1::text IS NULL OR entity_type = $1::text)
+    AND ($2::text IS NULL OR status = $2::text)
+    AND ($3::text IS NULL OR scheduling_strategy = $3::text)
+ORDER BY entity_id
+LIMIT $4 OFFSET $5
+`
+
+func (q *Queries) ListEntitiesFiltered(ctx context.Context, entityType pgtype.Text, status pgtype.Text, schedulingStrategy pgtype.Text, limit int64, offset int64) ([]TaskQueueEntityRegistry, error) {
+	rows, err := q.db.Query(ctx, listEntitiesFiltered,
+		entityType,
+		status,
+		schedulingStrategy,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskQueueEntityRegistry{}
+	for rows.Next() {
+		var i TaskQueueEntityRegistry
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.EntityType,
+			&i.Status,
+			&i.SchedulingStrategy,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ManualOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -1089,6 +1523,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -1123,6 +1558,7 @@ func (q *Queries) ListPendingTasks(ctx context.Context, limit int64) ([]TaskQueu
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1217,6 +1653,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -1250,6 +1687,7 @@ func (q *Queries) ListScheduledTasks(ctx context.Context, limit int64, offset in
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1263,6 +1701,123 @@ func (q *Queries) ListScheduledTasks(ctx context.Context, limit int64, offset in
 	return items, nil
 }
 
+const listSitemapCrawlChangedEntityIDs = `-- name: ListSitemapCrawlChangedEntityIDs :many
+SELECT entity_id
+FROM task_queue.sitemap_crawl_change
+WHERE crawl_id = $1
+    AND change = $2
+    AND entity_type = $3
+ORDER BY entity_id
+`
+
+func (q *Queries) ListSitemapCrawlChangedEntityIDs(ctx context.Context, crawlID int64, change string, entityType string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listSitemapCrawlChangedEntityIDs, crawlID, change, entityType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var entity_id string
+		if err := rows.Scan(&entity_id); err != nil {
+			return nil, err
+		}
+		items = append(items, entity_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSitemapCrawlChanges = `-- name: ListSitemapCrawlChanges :many
+SELECT
+    crawl_id,
+    entity_id,
+    entity_type,
+    change
+FROM task_queue.sitemap_crawl_change
+WHERE crawl_id = $1
+    AND ($2::text IS NULL OR change = $2::text)
+    AND ($3::text IS NULL OR entity_type = $3::text)
+ORDER BY entity_type, entity_id
+LIMIT $4 OFFSET $5
+`
+
+func (q *Queries) ListSitemapCrawlChanges(ctx context.Context, crawlID int64, change pgtype.Text, entityType pgtype.Text, limit int64, offset int64) ([]TaskQueueSitemapCrawlChange, error) {
+	rows, err := q.db.Query(ctx, listSitemapCrawlChanges,
+		crawlID,
+		change,
+		entityType,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskQueueSitemapCrawlChange{}
+	for rows.Next() {
+		var i TaskQueueSitemapCrawlChange
+		if err := rows.Scan(
+			&i.CrawlID,
+			&i.EntityID,
+			&i.EntityType,
+			&i.Change,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSitemapCrawls = `-- name: ListSitemapCrawls :many
+SELECT
+    crawl_id,
+    source,
+    previous_crawl_id,
+    entry_count,
+    added_count,
+    removed_count,
+    crawled_at
+FROM task_queue.sitemap_crawl
+WHERE ($1::text IS NULL OR source = $1::text)
+ORDER BY crawl_id DESC
+LIMIT $2 OFFSET $3
+`
+
+func (q *Queries) ListSitemapCrawls(ctx context.Context, source pgtype.Text, limit int64, offset int64) ([]TaskQueueSitemapCrawl, error) {
+	rows, err := q.db.Query(ctx, listSitemapCrawls, source, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskQueueSitemapCrawl{}
+	for rows.Next() {
+		var i TaskQueueSitemapCrawl
+		if err := rows.Scan(
+			&i.CrawlID,
+			&i.Source,
+			&i.PreviousCrawlID,
+			&i.EntryCount,
+			&i.AddedCount,
+			&i.RemovedCount,
+			&i.CrawledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listStuckTasks = `-- name: ListStuckTasks :many
 SELECT
     task_id,
@@ -1279,6 +1834,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at,
     EXTRACT(EPOCH FROM (NOW() - started_at))::INT AS stuck_seconds
@@ -1303,6 +1859,7 @@ type ListStuckTasksRow struct {
 	CompletedAt    pgtype.Timestamptz `db:"completed_at" json:"completed_at"`
 	RunOn          pgtype.Date        `db:"run_on" json:"run_on"`
 	QueueMessageID pgtype.Int8        `db:"queue_message_id" json:"queue_message_id"`
+	QueueName      pgtype.Text        `db:"queue_name" json:"queue_name"`
 	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 	StuckSeconds   int64              `db:"stuck_seconds" json:"stuck_seconds"`
@@ -1332,6 +1889,7 @@ func (q *Queries) ListStuckTasks(ctx context.Context) ([]ListStuckTasksRow, erro
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.StuckSeconds,
@@ -1362,6 +1920,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -1393,6 +1952,7 @@ func (q *Queries) ListTasks(ctx context.Context, limit int64, offset int64) ([]T
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1422,6 +1982,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -1454,6 +2015,7 @@ func (q *Queries) ListTasksByEntity(ctx context.Context, entityID string, limit
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1483,6 +2045,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -1515,6 +2078,7 @@ func (q *Queries) ListTasksByStatus(ctx context.Context, status string, limit in
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1544,6 +2108,7 @@ SELECT
     completed_at,
     run_on,
     queue_message_id,
+    queue_name,
     created_at,
     updated_at
 FROM task_queue.task
@@ -1576,6 +2141,78 @@ func (q *Queries) ListTasksByWorker(ctx context.Context, workerID pgtype.Text) (
 			&i.CompletedAt,
 			&i.RunOn,
 			&i.QueueMessageID,
+			&i.QueueName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksFiltered = `-- name: ListTasksFiltered :many
+SELECT
+    task_id,
+    entity_id,
+    task_type,
+    status,
+    priority,
+    attempt,
+    max_attempts,
+    last_error,
+    worker_id,
+    scheduled_for,
+    started_at,
+    completed_at,
+    run_on,
+    queue_message_id,
+    queue_name,
+    created_at,
+    updated_at
+FROM task_queue.task
+WHERE ($1::text IS NULL OR status = $1::text)
+    AND ($2::text IS NULL OR task_type = $2::text)
+    AND ($3::text IS NULL OR entity_id = $3::text)
+ORDER BY priority DESC, created_at DESC
+LIMIT $4 OFFSET $5
+`
+
+func (q *Queries) ListTasksFiltered(ctx context.Context, status pgtype.Text, taskType pgtype.Text, entityID pgtype.Text, limit int64, offset int64) ([]TaskQueueTask, error) {
+	rows, err := q.db.Query(ctx, listTasksFiltered,
+		status,
+		taskType,
+		entityID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskQueueTask{}
+	for rows.Next() {
+		var i TaskQueueTask
+		if err := rows.Scan(
+			&i.TaskID,
+			&i.EntityID,
+			&i.TaskType,
+			&i.Status,
+			&i.Priority,
+			&i.Attempt,
+			&i.MaxAttempts,
+			&i.LastError,
+			&i.WorkerID,
+			&i.ScheduledFor,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.RunOn,
+			&i.QueueMessageID,
+			&i.QueueName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1602,17 +2239,105 @@ func (q *Queries) MarkDLQEntryRequeued(ctx context.Context, dlqID int64) error {
 	return err
 }
 
-const updateEntityStatus = `-- name: UpdateEntityStatus :exec
+const recordSitemapCrawl = `-- name: RecordSitemapCrawl :one
+SELECT
+    crawl_id,
+    source,
+    previous_crawl_id,
+    entry_count,
+    added_count,
+    removed_count,
+    crawled_at
+FROM task_queue.fnc__record_sitemap_crawl($1::text, $2::text[], $3::text[])
+`
+
+func (q *Queries) RecordSitemapCrawl(ctx context.Context, source string, entityIds []string, entityTypes []string) (TaskQueueSitemapCrawl, error) {
+	row := q.db.QueryRow(ctx, recordSitemapCrawl, source, entityIds, entityTypes)
+	var i TaskQueueSitemapCrawl
+	err := row.Scan(
+		&i.CrawlID,
+		&i.Source,
+		&i.PreviousCrawlID,
+		&i.EntryCount,
+		&i.AddedCount,
+		&i.RemovedCount,
+		&i.CrawledAt,
+	)
+	return i, err
+}
+
+const replaceTaskTypeQueues = `-- name: ReplaceTaskTypeQueues :exec
+WITH removed AS (
+    DELETE FROM task_queue.task_type_queue
+    WHERE task_type <> ALL($1::text[])
+)
+INSERT INTO task_queue.task_type_queue (task_type, queue_name, task_timeout_seconds)
+SELECT
+    unnest($1::text[]),
+    unnest($2::text[]),
+    unnest($3::int[])
+ON CONFLICT (task_type) DO UPDATE
+SET queue_name = EXCLUDED.queue_name,
+    task_timeout_seconds = EXCLUDED.task_timeout_seconds,
+    updated_at = NOW()
+`
+
+func (q *Queries) ReplaceTaskTypeQueues(ctx context.Context, taskTypes []string, queueNames []string, taskTimeoutSeconds []int32) error {
+	_, err := q.db.Exec(ctx, replaceTaskTypeQueues, taskTypes, queueNames, taskTimeoutSeconds)
+	return err
+}
+
+const scheduleSingletonTask = `-- name: ScheduleSingletonTask :one
+SELECT
+    pending_task_id::bigint AS task_id,
+    created::boolean AS created
+FROM task_queue.fnc__schedule_singleton_task($1::text, $2::text, $3::int, $4::int)
+`
+
+type ScheduleSingletonTaskRow struct {
+	TaskID  int64 `db:"task_id" json:"task_id"`
+	Created bool  `db:"created" json:"created"`
+}
+
+func (q *Queries) ScheduleSingletonTask(ctx context.Context, entityID string, taskType string, priority int32, maxAttempts int32) (ScheduleSingletonTaskRow, error) {
+	row := q.db.QueryRow(ctx, scheduleSingletonTask, entityID, taskType, priority, maxAttempts)
+	var i ScheduleSingletonTaskRow
+	err := row.Scan(&i.TaskID, &i.Created)
+	return i, err
+}
+
+const updateEntitySchedulingStrategy = `-- name: UpdateEntitySchedulingStrategy :one
+UPDATE task_queue.entity_registry
+SET
+    scheduling_strategy = $2,
+    manual_override = TRUE,
+    updated_at = NOW()
+WHERE entity_id = $1
+RETURNING entity_id
+`
+
+func (q *Queries) UpdateEntitySchedulingStrategy(ctx context.Context, entityID string, schedulingStrategy string) (string, error) {
+	row := q.db.QueryRow(ctx, updateEntitySchedulingStrategy, entityID, schedulingStrategy)
+	var entity_id string
+	err := row.Scan(&entity_id)
+	return entity_id, err
+}
+
+const updateEntityStatus = `-- name: UpdateEntityStatus :one
 UPDATE task_queue.entity_registry
 SET
     status = $2,
+    manual_override = TRUE,
     updated_at = NOW()
 WHERE entity_id = $1
+RETURNING entity_id
 `
 
-func (q *Queries) UpdateEntityStatus(ctx context.Context, entityID string, status string) error {
-	_, err := q.db.Exec(ctx, updateEntityStatus, entityID, status)
-	return err
+func (q *Queries) UpdateEntityStatus(ctx context.Context, entityID string, status string) (string, error) {
+	row := q.db.QueryRow(ctx, updateEntityStatus, entityID, status)
+	var entity_id string
+	err := row.Scan(&entity_id)
+	return entity_id, err
 }
 
 const updateTaskPriority = `-- name: UpdateTaskPriority :exec
@@ -1693,6 +2418,7 @@ SET
     scheduled_for = $2,
     started_at = NULL,
     queue_message_id = NULL,
+    queue_name = NULL,
     updated_at = NOW()
 WHERE task_id = $1
 `
@@ -1710,6 +2436,7 @@ SET
     scheduled_for = $2,
     started_at = NULL,
     queue_message_id = NULL,
+    queue_name = NULL,
     updated_at = NOW()
 WHERE task_id = $1
 `
@@ -1761,7 +2488,7 @@ SET
     scheduling_strategy = EXCLUDED.scheduling_strategy,
     metadata = EXCLUDED.metadata,
     updated_at = NOW()
-RETURNING entity_id, entity_type, status, scheduling_strategy, metadata, created_at, updated_at
+RETURNING entity_id, entity_type, status, scheduling_strategy, metadata, created_at, updated_at, manual_override
 `
 
 func (q *Queries) UpsertEntity(ctx context.Context, entityID string, entityType string, column3 pgtype.Text, column4 pgtype.Text, column5 []byte) (TaskQueueEntityRegistry, error) {
@@ -1781,6 +2508,7 @@ func (q *Queries) UpsertEntity(ctx context.Context, entityID string, entityType
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ManualOverride,
 	)
 	return i, err
 }
@@ -1811,7 +2539,7 @@ DO UPDATE SET
     scheduled_for = COALESCE(task_queue.task.scheduled_for, EXCLUDED.scheduled_for),
     priority = GREATEST(task_queue.task.priority, EXCLUDED.priority),
     updated_at = NOW()
-RETURNING task_id, entity_id, task_type, status, priority, attempt, max_attempts, last_error, worker_id, scheduled_for, started_at, completed_at, run_on, queue_message_id, created_at, updated_at
+RETURNING task_id, entity_id, task_type, status, priority, attempt, max_attempts, last_error, worker_id, scheduled_for, started_at, completed_at, run_on, queue_message_id, queue_name, created_at, updated_at
 `
 
 func (q *Queries) UpsertTaskForDate(ctx context.Context, entityID string, taskType string, column3 pgtype.Int4, maxAttempts int32, scheduledFor time.Time, runOn pgtype.Date) (TaskQueueTask, error) {
@@ -1839,6 +2567,7 @@ func (q *Queries) UpsertTaskForDate(ctx context.Context, entityID string, taskTy
 		&i.CompletedAt,
 		&i.RunOn,
 		&i.QueueMessageID,
+		&i.QueueName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
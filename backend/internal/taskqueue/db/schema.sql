@@ -4,12 +4,13 @@ CREATE TABLE task_queue.entity_registry (
     entity_id TEXT NOT NULL PRIMARY KEY,
     entity_type TEXT NOT NULL,
     status TEXT NOT NULL DEFAULT 'active'
-        CHECK (status IN ('active', 'stopped')),
+        CHECK (status IN ('active', 'stopped', 'removed')),
     scheduling_strategy TEXT NOT NULL DEFAULT 'manual'
         CHECK (scheduling_strategy IN ('daily', 'manual', 'on_demand', 'cron')),
     metadata JSONB DEFAULT '{}'::jsonb,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    manual_override BOOLEAN NOT NULL DEFAULT FALSE
 );
 
 CREATE INDEX idx_entity_registry_status ON task_queue.entity_registry(status);
@@ -35,6 +36,8 @@ CREATE TABLE task_queue.task (
     completed_at TIMESTAMPTZ,
     run_on DATE,
     queue_message_id BIGINT,
+    -- the queue the message was sent to, routing may have changed since
+    queue_name TEXT,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
@@ -84,6 +87,68 @@ CREATE INDEX idx_dlq_task_type ON task_queue.dead_letter_queue(task_type);
 CREATE INDEX idx_dlq_moved_at ON task_queue.dead_letter_queue(moved_to_dlq_at DESC);
 CREATE INDEX idx_dlq_not_requeued ON task_queue.dead_letter_queue(moved_to_dlq_at DESC) WHERE requeued_at IS NULL;
 
+CREATE TABLE task_queue.task_type_entity_type_mapping (
+    task_type TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    PRIMARY KEY (task_type, entity_type)
+);
+
+CREATE TABLE task_queue.task_type_queue (
+    task_type TEXT PRIMARY KEY,
+    queue_name TEXT NOT NULL,
+    task_timeout_seconds INT NOT NULL DEFAULT 300,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE task_queue.sitemap_crawl (
+    crawl_id BIGSERIAL PRIMARY KEY,
+    source TEXT NOT NULL
+        CHECK (source IN ('frontdoor', 'shortcut')),
+    previous_crawl_id BIGINT
+        REFERENCES task_queue.sitemap_crawl(crawl_id) ON DELETE SET NULL,
+    entry_count INT NOT NULL DEFAULT 0,
+    added_count INT NOT NULL DEFAULT 0,
+    removed_count INT NOT NULL DEFAULT 0,
+    crawled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sitemap_crawl_source_crawled ON task_queue.sitemap_crawl(source, crawled_at DESC);
+
+CREATE TABLE task_queue.sitemap_crawl_entry (
+    crawl_id BIGINT NOT NULL
+        REFERENCES task_queue.sitemap_crawl(crawl_id) ON DELETE CASCADE,
+    entity_id TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    PRIMARY KEY (crawl_id, entity_type, entity_id)
+);
+
+CREATE TABLE task_queue.sitemap_crawl_change (
+    crawl_id BIGINT NOT NULL
+        REFERENCES task_queue.sitemap_crawl(crawl_id) ON DELETE CASCADE,
+    entity_id TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    change TEXT NOT NULL
+        CHECK (change IN ('added', 'removed')),
+    PRIMARY KEY (crawl_id, entity_type, entity_id)
+);
+
+CREATE INDEX idx_sitemap_crawl_change_crawl_change ON task_queue.sitemap_crawl_change(crawl_id, change, entity_type);
+
+-- pg_cron job table, managed by the extension
+CREATE SCHEMA IF NOT EXISTS cron;
+
+CREATE TABLE IF NOT EXISTS cron.job (
+    jobid BIGSERIAL PRIMARY KEY,
+    schedule TEXT NOT NULL,
+    command TEXT NOT NULL,
+    nodename TEXT NOT NULL DEFAULT 'localhost',
+    nodeport INT NOT NULL DEFAULT 5432,
+    database TEXT NOT NULL DEFAULT current_database(),
+    username TEXT NOT NULL DEFAULT current_user,
+    active BOOLEAN NOT NULL DEFAULT true,
+    jobname TEXT
+);
+
 -- Function signatures for sqlc
 CREATE OR REPLACE FUNCTION task_queue.fnc__register_entity(
     p_entity_id TEXT,
@@ -103,10 +168,24 @@ CREATE OR REPLACE FUNCTION task_queue.fnc__enqueue_task(
     p_task_id BIGINT
 ) RETURNS BIGINT AS $$ BEGIN RETURN 0; END; $$ LANGUAGE plpgsql;
 
+CREATE OR REPLACE FUNCTION task_queue.fnc__task_queue_name(
+    p_task_type TEXT
+) RETURNS TEXT AS $$ BEGIN RETURN 'tasks'; END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__reroute_pending_tasks()
+RETURNS INT AS $$ BEGIN RETURN 0; END; $$ LANGUAGE plpgsql;
+
 CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_daily_syncs(
     p_task_type TEXT DEFAULT 'frontdoor_sync'
 ) RETURNS INT AS $$ BEGIN RETURN 0; END; $$ LANGUAGE plpgsql;
 
+CREATE OR REPLACE FUNCTION task_queue.fnc__schedule_singleton_task(
+    p_entity_id TEXT,
+    p_task_type TEXT,
+    p_priority INT DEFAULT 0,
+    p_max_attempts INT DEFAULT 3
+) RETURNS TABLE(pending_task_id BIGINT, created BOOLEAN) AS $$ BEGIN END; $$ LANGUAGE plpgsql;
+
 CREATE OR REPLACE FUNCTION task_queue.fnc__requeue_stuck_tasks()
 RETURNS INT AS $$ BEGIN RETURN 0; END; $$ LANGUAGE plpgsql;
 
@@ -120,3 +199,35 @@ CREATE OR REPLACE FUNCTION task_queue.fnc__requeue_from_dlq(
     p_priority INT DEFAULT NULL,
     p_max_attempts INT DEFAULT 3
 ) RETURNS BIGINT AS $$ BEGIN RETURN 0; END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__get_sync_statistics() RETURNS TABLE(
+    total_entities INT,
+    active_entities INT,
+    stopped_entities INT,
+    total_tasks INT,
+    pending_tasks INT,
+    processing_tasks INT,
+    completed_tasks_today INT,
+    failed_tasks_today INT,
+    avg_task_duration_seconds NUMERIC,
+    sitemap_last_sync TIMESTAMPTZ,
+    sitemap_last_status TEXT
+) AS $$ BEGIN END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__get_entity_sync_status(p_entity_id TEXT) RETURNS TABLE(
+    entity_id TEXT,
+    entity_status TEXT,
+    last_task_id BIGINT,
+    last_task_status TEXT,
+    last_task_completed_at TIMESTAMPTZ,
+    last_task_error TEXT,
+    total_completed_count BIGINT,
+    total_failed_count BIGINT,
+    success_rate NUMERIC
+) AS $$ BEGIN END; $$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_queue.fnc__record_sitemap_crawl(
+    p_source TEXT,
+    p_entity_ids TEXT[],
+    p_entity_types TEXT[]
+) RETURNS SETOF task_queue.sitemap_crawl AS $$ BEGIN END; $$ LANGUAGE plpgsql;
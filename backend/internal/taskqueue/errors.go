@@ -7,17 +7,20 @@ import (
 )
 
 var (
-	ErrTaskNotFound      = errors.New("task not found")
-	ErrEntityNotFound    = errors.New("entity not found")
-	ErrInvalidEntityID   = errors.New("invalid entity ID format")
-	ErrInvalidTaskType   = errors.New("invalid task type")
-	ErrTaskAlreadyExists = errors.New("task already exists")
-	ErrMaxRetriesReached = errors.New("max retries reached")
-	ErrTaskCancelled     = errors.New("task cancelled")
-	ErrTaskTimeout       = errors.New("task timeout")
-	ErrTaskPanicked      = errors.New("task handler panicked")
-	ErrQueueFull         = errors.New("queue is full")
-	ErrWorkerStopped     = errors.New("worker stopped")
+	ErrTaskNotFound         = errors.New("task not found")
+	ErrEntityNotFound       = errors.New("entity not found")
+	ErrDLQEntryNotFound     = errors.New("DLQ entry not found")
+	ErrSitemapCrawlNotFound = errors.New("sitemap crawl not found")
+	ErrInvalidEntityID      = errors.New("invalid entity ID format")
+	ErrInvalidTaskType      = errors.New("invalid task type")
+	ErrTaskAlreadyExists    = errors.New("task already exists")
+	ErrMaxRetriesReached    = errors.New("max retries reached")
+	ErrTaskCancelled        = errors.New("task cancelled")
+	ErrTaskNotCancelable    = errors.New("task is not pending")
+	ErrTaskTimeout          = errors.New("task timeout")
+	ErrTaskPanicked         = errors.New("task handler panicked")
+	ErrQueueFull            = errors.New("queue is full")
+	ErrWorkerStopped        = errors.New("worker stopped")
 )
 
 type TaskError struct {
@@ -0,0 +1,90 @@
+package taskqueue
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+
+	"koditon-go/internal/metrics"
+)
+
+// Task outcomes recorded by workers
+const (
+	TaskOutcomeCompleted    = "completed"
+	TaskOutcomeRetried      = "retried"
+	TaskOutcomeDeadLettered = "dead_lettered"
+	TaskOutcomeSkipped      = "skipped"
+	// TaskOutcomeHeld is a task left in the queue because its gate held it
+	// back.
+	TaskOutcomeHeld = "held"
+)
+
+var (
+	taskOutcomesTotal = promauto.With(metrics.Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "koditon_worker_tasks_total",
+		Help: "Tasks processed by workers, by task type and outcome.",
+	}, []string{"task_type", "outcome"})
+	taskDurationSeconds = promauto.With(metrics.Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "koditon_worker_task_duration_seconds",
+		Help:    "Handler duration of tasks processed by workers, by task type and outcome.",
+		Buckets: metrics.DefaultDurationBuckets,
+	}, []string{"task_type", "outcome"})
+)
+
+// WorkerTaskStats summarizes the tasks processed by the workers of this
+// process since it started.
+type WorkerTaskStats struct {
+	TaskType        string
+	Outcome         string
+	Count           uint64
+	TotalDuration   time.Duration
+	AverageDuration time.Duration
+}
+
+func recordTaskOutcome(taskType, outcome string, duration time.Duration) {
+	taskOutcomesTotal.WithLabelValues(taskType, outcome).Inc()
+	if outcome != TaskOutcomeSkipped && outcome != TaskOutcomeHeld {
+		taskDurationSeconds.WithLabelValues(taskType, outcome).Observe(duration.Seconds())
+	}
+}
+
+// GetWorkerTaskStats returns the handler durations recorded by the workers
+// running in this process, ordered by task type and outcome.
+func GetWorkerTaskStats() []WorkerTaskStats {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		taskDurationSeconds.Collect(ch)
+		close(ch)
+	}()
+	var stats []WorkerTaskStats
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			continue
+		}
+		stat := WorkerTaskStats{
+			Count:         pb.GetHistogram().GetSampleCount(),
+			TotalDuration: time.Duration(pb.GetHistogram().GetSampleSum() * float64(time.Second)),
+		}
+		for _, label := range pb.GetLabel() {
+			switch label.GetName() {
+			case "task_type":
+				stat.TaskType = label.GetValue()
+			case "outcome":
+				stat.Outcome = label.GetValue()
+			}
+		}
+		if stat.Count > 0 {
+			stat.AverageDuration = stat.TotalDuration / time.Duration(stat.Count)
+		}
+		stats = append(stats, stat)
+	}
+	slices.SortFunc(stats, func(a, b WorkerTaskStats) int {
+		return cmp.Or(cmp.Compare(a.TaskType, b.TaskType), cmp.Compare(a.Outcome, b.Outcome))
+	})
+	return stats
+}
@@ -0,0 +1,88 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"koditon-go/internal/taskqueue/db"
+)
+
+type SitemapCrawlChange string
+
+const (
+	SitemapCrawlChangeAdded   SitemapCrawlChange = "added"
+	SitemapCrawlChangeRemoved SitemapCrawlChange = "removed"
+)
+
+// SitemapEntities groups the entity IDs listed by a sitemap crawl by entity type.
+type SitemapEntities map[string][]string
+
+type SitemapCrawlChangeFilter struct {
+	Change     string
+	EntityType string
+}
+
+// RecordSitemapCrawl stores the entities a sitemap crawl listed and diffs them
+// against the previous crawl of the source, or against the registry for the
+// first crawl. Entities missing from this crawl are marked removed so daily
+// syncs skip them until a later crawl lists them again.
+func (c *Client) RecordSitemapCrawl(ctx context.Context, source string, entities SitemapEntities) (*db.TaskQueueSitemapCrawl, error) {
+	var entityIDs, entityTypes []string
+	for entityType, ids := range entities {
+		for _, id := range ids {
+			entityIDs = append(entityIDs, id)
+			entityTypes = append(entityTypes, entityType)
+		}
+	}
+	crawl, err := c.queries.RecordSitemapCrawl(ctx, source, entityIDs, entityTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record sitemap crawl: %w", err)
+	}
+	return &crawl, nil
+}
+
+func (c *Client) GetSitemapCrawl(ctx context.Context, crawlID int64) (*db.TaskQueueSitemapCrawl, error) {
+	crawl, err := c.queries.GetSitemapCrawl(ctx, crawlID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSitemapCrawlNotFound
+		}
+		return nil, fmt.Errorf("failed to get sitemap crawl: %w", err)
+	}
+	return &crawl, nil
+}
+
+func (c *Client) ListSitemapCrawls(ctx context.Context, source string, limit, offset int) ([]db.TaskQueueSitemapCrawl, error) {
+	crawls, err := c.queries.ListSitemapCrawls(ctx, optionalPgText(source), int64(limit), int64(offset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sitemap crawls: %w", err)
+	}
+	return crawls, nil
+}
+
+func (c *Client) ListSitemapCrawlChanges(ctx context.Context, crawlID int64, filter SitemapCrawlChangeFilter, limit, offset int) ([]db.TaskQueueSitemapCrawlChange, error) {
+	changes, err := c.queries.ListSitemapCrawlChanges(ctx,
+		crawlID,
+		optionalPgText(filter.Change),
+		optionalPgText(filter.EntityType),
+		int64(limit),
+		int64(offset),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sitemap crawl changes: %w", err)
+	}
+	return changes, nil
+}
+
+// ListRemovedSitemapEntities returns the entities of a type that were missing
+// from a crawl although the previous crawl listed them.
+func (c *Client) ListRemovedSitemapEntities(ctx context.Context, crawlID int64, entityType string) ([]string, error) {
+	ids, err := c.queries.ListSitemapCrawlChangedEntityIDs(ctx, crawlID, string(SitemapCrawlChangeRemoved), entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list removed sitemap entities: %w", err)
+	}
+	return ids, nil
+}
@@ -3,9 +3,12 @@ package taskqueue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -44,9 +47,28 @@ type DLQByTaskType struct {
 }
 
 const (
+	// QueueName is the shared queue of the task types that are not routed to
+	// a queue of their own.
 	QueueName = "tasks"
 )
 
+// CronJobNames are the pg_cron jobs created by the migrations that drive
+// scheduling.
+var CronJobNames = []string{
+	"trigger-frontdoor-sitemap-sync",
+	"schedule-daily-frontdoor-syncs",
+	"cleanup-old-completed",
+	"reset-stuck-tasks",
+	"trigger-shortcut-sitemap-sync",
+	"schedule-daily-shortcut-scraper-syncs",
+	"schedule-daily-shortcut-api-syncs",
+	"schedule-daily-shortcut-search-syncs",
+	"trigger-prices-cities-init",
+	"schedule-daily-prices-syncs",
+	"cleanup-webhook-delivery-entities",
+	"cleanup-shortcut-tokens",
+}
+
 var (
 	ErrNoRows = pgmq.ErrNoRows
 )
@@ -72,6 +94,7 @@ type EntityStatus string
 const (
 	EntityStatusActive  EntityStatus = "active"
 	EntityStatusStopped EntityStatus = "stopped"
+	EntityStatusRemoved EntityStatus = "removed"
 )
 
 type TaskMessage struct {
@@ -96,15 +119,15 @@ func NewClient(pool *pgxpool.Pool) *Client {
 	}
 }
 
-func (c *Client) EnsureQueue(ctx context.Context) error {
-	if err := c.pgmqClient.CreateQueue(ctx, QueueName); err != nil {
-		return fmt.Errorf("create queue %s: %w", QueueName, err)
+func (c *Client) EnsureQueue(ctx context.Context, queueName string) error {
+	if err := c.pgmqClient.CreateQueue(ctx, queueName); err != nil {
+		return fmt.Errorf("create queue %s: %w", queueName, err)
 	}
 	return nil
 }
 
-func (c *Client) ReadTask(ctx context.Context, visibilityTimeoutSeconds int) (*TaskMessage, error) {
-	msg, err := c.pgmqClient.Read(ctx, QueueName, int64(visibilityTimeoutSeconds))
+func (c *Client) ReadTask(ctx context.Context, queueName string, visibilityTimeoutSeconds int) (*TaskMessage, error) {
+	msg, err := c.pgmqClient.Read(ctx, queueName, int64(visibilityTimeoutSeconds))
 	if err != nil {
 		if pgmq.IsNoRows(err) {
 			return nil, nil
@@ -124,8 +147,8 @@ func (c *Client) ReadTask(ctx context.Context, visibilityTimeoutSeconds int) (*T
 	}, nil
 }
 
-func (c *Client) DeleteTaskFromQueue(ctx context.Context, messageID int64) error {
-	deleted, err := c.pgmqClient.Delete(ctx, QueueName, messageID)
+func (c *Client) DeleteTaskFromQueue(ctx context.Context, queueName string, messageID int64) error {
+	deleted, err := c.pgmqClient.Delete(ctx, queueName, messageID)
 	if err != nil {
 		return fmt.Errorf("failed to delete task from queue: %w", err)
 	}
@@ -135,8 +158,8 @@ func (c *Client) DeleteTaskFromQueue(ctx context.Context, messageID int64) error
 	return nil
 }
 
-func (c *Client) ArchiveTaskFromQueue(ctx context.Context, messageID int64) error {
-	archived, err := c.pgmqClient.Archive(ctx, QueueName, messageID)
+func (c *Client) ArchiveTaskFromQueue(ctx context.Context, queueName string, messageID int64) error {
+	archived, err := c.pgmqClient.Archive(ctx, queueName, messageID)
 	if err != nil {
 		return fmt.Errorf("failed to archive task from queue: %w", err)
 	}
@@ -146,27 +169,67 @@ func (c *Client) ArchiveTaskFromQueue(ctx context.Context, messageID int64) erro
 	return nil
 }
 
-func (c *Client) EnqueueTask(ctx context.Context, taskID int64, entityID string, attempt int32, scheduledFor time.Time) (int64, error) {
-	msgData := TaskMessageData{
-		TaskID:   taskID,
-		EntityID: entityID,
-		Attempt:  attempt,
+// HoldTask leaves a message in the queue but hides it from workers for the
+// delay, without touching the task.
+func (c *Client) HoldTask(ctx context.Context, queueName string, messageID int64, delay time.Duration) error {
+	seconds := int64(math.Ceil(delay.Seconds()))
+	if _, err := c.pgmqClient.SetVisibilityTimeout(ctx, queueName, messageID, max(seconds, 1)); err != nil {
+		return fmt.Errorf("failed to hold task in queue: %w", err)
 	}
-	msgJSON, err := json.Marshal(msgData)
+	return nil
+}
+
+// HoldQueuedTasks hides the queued messages of the pending tasks of the task
+// types for the delay and returns how many it held.
+func (c *Client) HoldQueuedTasks(ctx context.Context, queueName string, taskTypes []string, delay time.Duration) (int64, error) {
+	seconds := int32(math.Ceil(delay.Seconds()))
+	held, err := c.queries.HoldQueuedTasks(ctx, queueName, taskTypes, max(seconds, 1))
 	if err != nil {
-		return 0, fmt.Errorf("failed to marshal task message: %w", err)
+		return 0, fmt.Errorf("failed to hold queued tasks: %w", err)
 	}
-	delay := int(time.Until(scheduledFor).Seconds())
-	delay = max(delay, 0)
-	msgID, err := c.pgmqClient.SendWithDelay(ctx, QueueName, json.RawMessage(msgJSON), delay)
+	return held, nil
+}
+
+// EnqueueTask sends a pending task to the queue of its task type, delayed
+// until its scheduled time, and records the message on the task.
+func (c *Client) EnqueueTask(ctx context.Context, taskID int64) (int64, error) {
+	msgID, err := c.queries.CallEnqueueTask(ctx, taskID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to enqueue task: %w", err)
 	}
 	return msgID, nil
 }
 
-func (c *Client) EnqueueTaskImmediate(ctx context.Context, taskID int64, entityID string, attempt int32) (int64, error) {
-	return c.EnqueueTask(ctx, taskID, entityID, attempt, time.Now())
+// TaskTypeQueue routes a task type to a queue.
+type TaskTypeQueue struct {
+	TaskType string
+	Queue    string
+	// TaskTimeout is how long the workers of the queue let a task run, tasks
+	// processing for longer are eventually requeued as stuck.
+	TaskTimeout time.Duration
+}
+
+// SetTaskTypeQueues replaces the routing of task types to queues. Task types
+// left out fall back to QueueName. The messages of pending tasks are moved to
+// the new queue of their task type; tasks already processing finish where
+// they are. It returns how many pending tasks were moved.
+func (c *Client) SetTaskTypeQueues(ctx context.Context, routes []TaskTypeQueue) (int, error) {
+	taskTypes := make([]string, 0, len(routes))
+	queueNames := make([]string, 0, len(routes))
+	timeouts := make([]int32, 0, len(routes))
+	for _, route := range routes {
+		taskTypes = append(taskTypes, route.TaskType)
+		queueNames = append(queueNames, route.Queue)
+		timeouts = append(timeouts, int32(route.TaskTimeout.Seconds()))
+	}
+	if err := c.queries.ReplaceTaskTypeQueues(ctx, taskTypes, queueNames, timeouts); err != nil {
+		return 0, fmt.Errorf("failed to set task type queues: %w", err)
+	}
+	rerouted, err := c.queries.CallReroutePendingTasks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reroute pending tasks: %w", err)
+	}
+	return int(rerouted), nil
 }
 
 func (c *Client) GetQueueMetrics(ctx context.Context) (*QueueMetrics, error) {
@@ -193,6 +256,88 @@ type QueueMetrics struct {
 	ScrapeTime      time.Time
 }
 
+// GetAllQueueMetrics returns the metrics of every pgmq queue, not only the
+// task queue.
+func (c *Client) GetAllQueueMetrics(ctx context.Context) ([]QueueMetrics, error) {
+	rows, err := c.pgmqClient.MetricsAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue metrics: %w", err)
+	}
+	metrics := make([]QueueMetrics, 0, len(rows))
+	for _, m := range rows {
+		metrics = append(metrics, QueueMetrics{
+			QueueName:       m.QueueName,
+			QueueLength:     m.QueueLength,
+			NewestMsgAgeSec: &m.NewestMsgAgeSec,
+			OldestMsgAgeSec: &m.OldestMsgAgeSec,
+			TotalMessages:   m.TotalMessages,
+			ScrapeTime:      m.ScrapeTime,
+		})
+	}
+	return metrics, nil
+}
+
+// QueueExists reports whether the task queue has been created in pgmq.
+func (c *Client) QueueExists(ctx context.Context, queueName string) (bool, error) {
+	queues, err := c.pgmqClient.ListQueues(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list queues: %w", err)
+	}
+	for _, q := range queues {
+		if q.QueueName == queueName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListCronJobs returns the pg_cron jobs by name with their active flag.
+func (c *Client) ListCronJobs(ctx context.Context) (map[string]bool, error) {
+	rows, err := c.queries.ListCronJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cron jobs: %w", err)
+	}
+	jobs := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if row.Jobname.Valid {
+			jobs[row.Jobname.String] = row.Active
+		}
+	}
+	return jobs, nil
+}
+
+func (c *Client) GetTaskStatusSummary(ctx context.Context) (*db.GetTaskStatusSummaryRow, error) {
+	summary, err := c.queries.GetTaskStatusSummary(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task status summary: %w", err)
+	}
+	return &summary, nil
+}
+
+func (c *Client) GetTaskCountsByTypeAndStatus(ctx context.Context) ([]db.GetTaskCountsByTypeAndStatusRow, error) {
+	counts, err := c.queries.GetTaskCountsByTypeAndStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task counts: %w", err)
+	}
+	return counts, nil
+}
+
+func (c *Client) GetDailyProgress(ctx context.Context) (*db.GetDailyProgressRow, error) {
+	progress, err := c.queries.GetDailyProgress(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily progress: %w", err)
+	}
+	return &progress, nil
+}
+
+func (c *Client) GetSyncStatistics(ctx context.Context) (*db.GetSyncStatisticsRow, error) {
+	stats, err := c.queries.GetSyncStatistics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync statistics: %w", err)
+	}
+	return &stats, nil
+}
+
 func (c *Client) RegisterEntity(ctx context.Context, entityID, entityType, status, schedulingStrategy string) error {
 	err := c.queries.CallRegisterEntity(ctx, entityID, entityType, status, schedulingStrategy, []byte("{}"))
 	if err != nil {
@@ -209,6 +354,15 @@ func (c *Client) RegisterEntities(ctx context.Context, entityIDs []string, entit
 	return int(count), nil
 }
 
+// EnsureTaskTypeMapping allows a task type to run for an entity type, which
+// is what the daily scheduling uses to pick the entities of a task type.
+func (c *Client) EnsureTaskTypeMapping(ctx context.Context, taskType, entityType string) error {
+	if err := c.queries.EnsureTaskTypeMapping(ctx, taskType, entityType); err != nil {
+		return fmt.Errorf("failed to ensure task type mapping: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) ScheduleDailySyncs(ctx context.Context, taskType string) (int, error) {
 	count, err := c.queries.CallScheduleDailySyncs(ctx, taskType)
 	if err != nil {
@@ -246,11 +400,185 @@ func (c *Client) UpdateTaskPriority(ctx context.Context, taskID int64, priority
 	return nil
 }
 
+type TaskFilter struct {
+	Status   string
+	TaskType string
+	EntityID string
+}
+
+func (c *Client) ListTasks(ctx context.Context, filter TaskFilter, limit, offset int) ([]db.TaskQueueTask, error) {
+	tasks, err := c.queries.ListTasksFiltered(ctx,
+		optionalPgText(filter.Status),
+		optionalPgText(filter.TaskType),
+		optionalPgText(filter.EntityID),
+		int64(limit),
+		int64(offset),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, taskID int64) (*db.TaskQueueTask, error) {
+	task, err := c.queries.GetTask(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	return &task, nil
+}
+
+// CancelTask marks a pending task as stopped and archives its queue message so
+// that workers no longer pick it up. Tasks that started cannot be cancelled.
+func (c *Client) CancelTask(ctx context.Context, taskID int64) error {
+	cancelled, err := c.queries.CancelPendingTask(ctx, taskID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := c.GetTask(ctx, taskID); err != nil {
+			return err
+		}
+		return ErrTaskNotCancelable
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel task: %w", err)
+	}
+	if cancelled.QueueMessageID.Valid && cancelled.QueueName.Valid {
+		// the message may already be read; workers skip stopped tasks anyway
+		_ = c.ArchiveTaskFromQueue(ctx, cancelled.QueueName.String, cancelled.QueueMessageID.Int64)
+	}
+	return nil
+}
+
+// RunTaskNow creates a critical priority task for the entity and enqueues it
+// without delay, bypassing the daily schedule.
+func (c *Client) RunTaskNow(ctx context.Context, entityID, taskType string, maxAttempts int) (int64, error) {
+	return c.CreateAndEnqueueTask(ctx, entityID, taskType, PriorityCritical, maxAttempts)
+}
+
+// CreateAndEnqueueTask creates a task with the given priority and enqueues it
+// without delay.
+func (c *Client) CreateAndEnqueueTask(ctx context.Context, entityID, taskType string, priority int, maxAttempts int) (int64, error) {
+	taskID, err := c.CreateTaskWithPriority(ctx, entityID, taskType, priority, maxAttempts, time.Now(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := c.EnqueueTask(ctx, taskID); err != nil {
+		return taskID, fmt.Errorf("task created but failed to enqueue: %w", err)
+	}
+	return taskID, nil
+}
+
+// ScheduleSingletonTask queues a task for an entity unless one is already
+// pending. It returns the pending task and whether this call created it.
+func (c *Client) ScheduleSingletonTask(ctx context.Context, entityID, taskType string, priority int, maxAttempts int) (int64, bool, error) {
+	row, err := c.queries.ScheduleSingletonTask(ctx, entityID, taskType, int32(priority), int32(maxAttempts))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to schedule singleton task: %w", err)
+	}
+	return row.TaskID, row.Created, nil
+}
+
+// Entity Operations
+
+type EntityFilter struct {
+	EntityType         string
+	Status             string
+	SchedulingStrategy string
+}
+
+type EntitySyncStatus struct {
+	EntityID            string
+	EntityStatus        string
+	LastTaskID          *int64
+	LastTaskStatus      *string
+	LastTaskCompletedAt *time.Time
+	LastTaskError       *string
+	TotalCompletedCount int64
+	TotalFailedCount    int64
+	SuccessRate         float64
+}
+
+func (c *Client) ListEntities(ctx context.Context, filter EntityFilter, limit, offset int) ([]db.TaskQueueEntityRegistry, error) {
+	entities, err := c.queries.ListEntitiesFiltered(ctx,
+		optionalPgText(filter.EntityType),
+		optionalPgText(filter.Status),
+		optionalPgText(filter.SchedulingStrategy),
+		int64(limit),
+		int64(offset),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entities: %w", err)
+	}
+	return entities, nil
+}
+
+func (c *Client) GetEntity(ctx context.Context, entityID string) (*db.TaskQueueEntityRegistry, error) {
+	entity, err := c.queries.GetEntity(ctx, entityID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+	return &entity, nil
+}
+
+// SetEntityStatus pauses or resumes the daily scheduling of an entity. Tasks
+// that are already queued are not affected. The status is kept when the
+// entity is registered again by a sitemap crawl.
+func (c *Client) SetEntityStatus(ctx context.Context, entityID string, status EntityStatus) error {
+	if _, err := c.queries.UpdateEntityStatus(ctx, entityID, string(status)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEntityNotFound
+		}
+		return fmt.Errorf("failed to update entity status: %w", err)
+	}
+	return nil
+}
+
+// SetEntitySchedulingStrategy changes how an entity is scheduled. Like the
+// status, the strategy is kept when the entity is registered again.
+func (c *Client) SetEntitySchedulingStrategy(ctx context.Context, entityID, schedulingStrategy string) error {
+	if _, err := c.queries.UpdateEntitySchedulingStrategy(ctx, entityID, schedulingStrategy); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEntityNotFound
+		}
+		return fmt.Errorf("failed to update entity scheduling strategy: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) GetEntitySyncStatus(ctx context.Context, entityID string) (*EntitySyncStatus, error) {
+	row, err := c.queries.GetEntitySyncStatus(ctx, entityID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to get entity sync status: %w", err)
+	}
+	return &EntitySyncStatus{
+		EntityID:            row.EntityID.String,
+		EntityStatus:        row.EntityStatus.String,
+		LastTaskID:          PgInt8ToInt64(row.LastTaskID),
+		LastTaskStatus:      PgTextToString(row.LastTaskStatus),
+		LastTaskCompletedAt: PgTimestamptzToTime(row.LastTaskCompletedAt),
+		LastTaskError:       PgTextToString(row.LastTaskError),
+		TotalCompletedCount: row.TotalCompletedCount.Int64,
+		TotalFailedCount:    row.TotalFailedCount.Int64,
+		SuccessRate:         row.SuccessRate,
+	}, nil
+}
+
 // DLQ Operations
 
 func (c *Client) GetDLQEntry(ctx context.Context, dlqID int64) (*DLQEntry, error) {
 	entry, err := c.queries.GetDLQEntry(ctx, dlqID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDLQEntryNotFound
+		}
 		return nil, fmt.Errorf("failed to get DLQ entry: %w", err)
 	}
 	return convertDBDLQEntry(entry), nil
@@ -332,7 +660,14 @@ func (c *Client) GetDLQStatsByTaskType(ctx context.Context) ([]DLQByTaskType, er
 }
 
 func (c *Client) RequeueFromDLQ(ctx context.Context, dlqID int64, priority *int, maxAttempts int) (int64, error) {
-	var priorityVal int64
+	entry, err := c.queries.GetDLQEntry(ctx, dlqID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrDLQEntryNotFound
+		}
+		return 0, fmt.Errorf("failed to get DLQ entry: %w", err)
+	}
+	priorityVal := entry.Priority
 	if priority != nil {
 		priorityVal = int64(*priority)
 	}
@@ -340,15 +675,86 @@ func (c *Client) RequeueFromDLQ(ctx context.Context, dlqID int64, priority *int,
 	if err != nil {
 		return 0, fmt.Errorf("failed to requeue from DLQ: %w", err)
 	}
-	msgID, err := c.EnqueueTaskImmediate(ctx, taskID, "", 0)
-	if err != nil {
+	if _, err := c.EnqueueTask(ctx, taskID); err != nil {
 		return taskID, fmt.Errorf("task created but failed to enqueue: %w", err)
 	}
-	queueMsgID := pgtype.Int8{Int64: msgID, Valid: true}
-	_ = c.queries.UpdateTaskQueueMessageId(ctx, taskID, queueMsgID)
 	return taskID, nil
 }
 
+type DLQFilter struct {
+	TaskType       string
+	EntityPrefix   string
+	ErrorSubstring string
+	PendingOnly    bool
+}
+
+func (c *Client) ListDLQEntriesMatching(ctx context.Context, filter DLQFilter, limit, offset int) ([]DLQEntry, error) {
+	entries, err := c.queries.ListDLQEntriesMatching(ctx,
+		optionalPgText(filter.TaskType),
+		optionalPgText(filter.EntityPrefix),
+		optionalPgText(filter.ErrorSubstring),
+		filter.PendingOnly,
+		int64(limit),
+		int64(offset),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list matching DLQ entries: %w", err)
+	}
+	result := make([]DLQEntry, len(entries))
+	for i, e := range entries {
+		result[i] = *convertDBDLQEntry(e)
+	}
+	return result, nil
+}
+
+func (c *Client) CountDLQEntriesMatching(ctx context.Context, filter DLQFilter) (int64, error) {
+	count, err := c.queries.CountDLQEntriesMatching(ctx,
+		optionalPgText(filter.TaskType),
+		optionalPgText(filter.EntityPrefix),
+		optionalPgText(filter.ErrorSubstring),
+		filter.PendingOnly,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count matching DLQ entries: %w", err)
+	}
+	return count, nil
+}
+
+// BulkRequeueFromDLQ requeues up to limit entries that have not been requeued
+// yet and match the filter. It stops at the first failure and returns how many
+// entries were requeued before it.
+func (c *Client) BulkRequeueFromDLQ(ctx context.Context, filter DLQFilter, limit int, priority *int, maxAttempts int) (int, error) {
+	filter.PendingOnly = true
+	entries, err := c.ListDLQEntriesMatching(ctx, filter, limit, 0)
+	if err != nil {
+		return 0, err
+	}
+	requeued := 0
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return requeued, err
+		}
+		if _, err := c.RequeueFromDLQ(ctx, entry.DLQID, priority, maxAttempts); err != nil {
+			return requeued, fmt.Errorf("failed to requeue DLQ entry %d: %w", entry.DLQID, err)
+		}
+		requeued++
+	}
+	return requeued, nil
+}
+
+func (c *Client) BulkDeleteDLQEntries(ctx context.Context, filter DLQFilter) (int64, error) {
+	count, err := c.queries.DeleteDLQEntriesMatching(ctx,
+		optionalPgText(filter.TaskType),
+		optionalPgText(filter.EntityPrefix),
+		optionalPgText(filter.ErrorSubstring),
+		filter.PendingOnly,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete matching DLQ entries: %w", err)
+	}
+	return count, nil
+}
+
 func (c *Client) DeleteDLQEntry(ctx context.Context, dlqID int64) error {
 	err := c.queries.DeleteDLQEntry(ctx, dlqID)
 	if err != nil {
@@ -407,6 +813,13 @@ func PgTextToString(t pgtype.Text) *string {
 	return &t.String
 }
 
+func optionalPgText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: s, Valid: true}
+}
+
 func TimeToPgTimestamptz(t *time.Time) pgtype.Timestamptz {
 	if t == nil {
 		return pgtype.Timestamptz{Valid: false}
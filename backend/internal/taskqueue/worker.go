@@ -18,22 +18,15 @@ import (
 	"koditon-go/internal/taskqueue/db"
 )
 
-// Task types
-const (
-	TaskTypeFrontdoorSitemapSync = "frontdoor_sitemap_sync"
-	TaskTypeFrontdoorSync        = "frontdoor_sync"
-	TaskTypeShortcutSitemapSync  = "shortcut_sitemap_sync"
-	TaskTypeShortcutScraperSync  = "shortcut_scraper_sync"
-	TaskTypeShortcutAPISync      = "shortcut_api_sync"
-	TaskTypePricesCitiesInit     = "prices_cities_init"
-	TaskTypePricesSync           = "prices_sync"
-)
-
 // Entity prefixes
 const (
 	EntityPrefixAd       = "ad:"
 	EntityPrefixBuilding = "building:"
 	EntityPrefixCity     = "city:"
+	// search:<postcode>
+	EntityPrefixSearch = "search:"
+	// webhook_delivery:<delivery uuid>
+	EntityPrefixWebhookDelivery = "webhook_delivery:"
 )
 
 // Task priority levels
@@ -55,21 +48,44 @@ type Worker struct {
 	doneCh   chan struct{}
 	stopOnce sync.Once
 	stopped  atomic.Bool
+	// unix nanoseconds, zero when unset
+	lastPollAt atomic.Int64
+	busySince  atomic.Int64
+	// consecutive held reads and the time the worker reads again after them,
+	// only used by the poll loop
+	heldStreak  int
+	heldBackoff time.Time
 }
 
 type TaskHandler func(ctx context.Context, task db.TaskQueueTask) error
 
 type WorkerConfig struct {
+	// Queue is the queue the worker reads, QueueName when empty.
+	Queue             string
 	VisibilityTimeout time.Duration
 	PollInterval      time.Duration
 	TaskTimeout       time.Duration
 	BaseRetryDelay    time.Duration
 	MaxRetryDelay     time.Duration
 	Logger            *slog.Logger
+	// OnDeadLetter, when set, is called after a task was moved to the dead
+	// letter queue.
+	OnDeadLetter func(ctx context.Context, task db.TaskQueueTask, lastErr error)
+	// Gate, when set, is asked before a task runs. A task it holds back stays
+	// in the queue for the returned delay without using an attempt.
+	Gate func(task db.TaskQueueTask) (hold time.Duration, ok bool)
+	// HoldGroup, when set, returns the task types held back together with a
+	// held task, so that the queued tasks of a source that is down are set
+	// aside at once instead of being read one by one.
+	HoldGroup func(taskType string) []string
+	// Paused, when set, stops the worker from reading the queue while it
+	// returns true.
+	Paused func() bool
 }
 
 func DefaultWorkerConfig() WorkerConfig {
 	return WorkerConfig{
+		Queue:             QueueName,
 		VisibilityTimeout: 5 * time.Minute,
 		PollInterval:      1 * time.Second,
 		TaskTimeout:       5 * time.Minute,
@@ -86,13 +102,16 @@ func NewWorker(pool *pgxpool.Pool, handler TaskHandler, config WorkerConfig) *Wo
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if config.Queue == "" {
+		config.Queue = QueueName
+	}
 	return &Worker{
 		client:   client,
 		queries:  db.New(pool),
 		workerID: workerID,
 		handler:  handler,
 		config:   config,
-		logger:   logger.With("worker_id", workerID),
+		logger:   logger.With("worker_id", workerID, "queue", config.Queue),
 		stopCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
 	}
@@ -100,11 +119,12 @@ func NewWorker(pool *pgxpool.Pool, handler TaskHandler, config WorkerConfig) *Wo
 
 func (w *Worker) Start(ctx context.Context) {
 	w.logger.InfoContext(ctx, "worker starting")
-	if err := w.client.EnsureQueue(ctx); err != nil {
+	if err := w.client.EnsureQueue(ctx, w.config.Queue); err != nil {
 		w.logger.ErrorContext(ctx, "failed to ensure queue exists", "error", err)
 		close(w.doneCh)
 		return
 	}
+	w.lastPollAt.Store(time.Now().UnixNano())
 	ticker := time.NewTicker(w.config.PollInterval)
 	defer ticker.Stop()
 	for {
@@ -118,13 +138,96 @@ func (w *Worker) Start(ctx context.Context) {
 			close(w.doneCh)
 			return
 		case <-ticker.C:
-			if err := w.processNextTask(ctx); err != nil {
-				w.logger.WarnContext(ctx, "error processing task", "error", err)
-			}
+			w.poll(ctx)
+		}
+	}
+}
+
+const (
+	// maxHeldPerPoll is how many held tasks a poll reads on past before the
+	// worker backs off.
+	maxHeldPerPoll = 10
+	// maxHeldBackoff caps the back off after held tasks.
+	maxHeldBackoff = 30 * time.Second
+)
+
+// poll processes the next task. Tasks the gate holds back do not count, the
+// worker reads on so that a queue is not stalled by the tasks of a source
+// that is down. After maxHeldPerPoll held tasks in a row the worker backs off,
+// longer each time, until it reads a task it can run or finds the queue empty.
+func (w *Worker) poll(ctx context.Context) {
+	w.lastPollAt.Store(time.Now().UnixNano())
+	if time.Now().Before(w.heldBackoff) {
+		return
+	}
+	for held := 0; !w.stopped.Load() && ctx.Err() == nil; held++ {
+		w.lastPollAt.Store(time.Now().UnixNano())
+		if w.config.Paused != nil && w.config.Paused() {
+			return
+		}
+		if held == maxHeldPerPoll {
+			w.heldStreak++
+			backoff := min(w.config.PollInterval<<min(w.heldStreak, 16), maxHeldBackoff)
+			w.heldBackoff = time.Now().Add(backoff)
+			w.logger.DebugContext(ctx, "backing off after held tasks", "backoff", backoff.String())
+			return
+		}
+		isHeld, err := w.processNextTask(ctx)
+		if err != nil {
+			w.logger.WarnContext(ctx, "error processing task", "error", err)
+		}
+		if !isHeld {
+			w.heldStreak = 0
+			return
 		}
 	}
 }
 
+// workerLivenessGrace is how much a worker may lag behind its poll interval,
+// or its task timeout while running a task, before it is reported as stalled.
+const workerLivenessGrace = 30 * time.Second
+
+type WorkerStatus struct {
+	WorkerID   string
+	Queue      string
+	Running    bool
+	Live       bool
+	LastPollAt *time.Time
+	BusySince  *time.Time
+}
+
+// Status reports whether the worker loop is running and has polled the queue
+// recently. A worker that is running a task is live until the task timeout.
+func (w *Worker) Status() WorkerStatus {
+	status := WorkerStatus{WorkerID: w.workerID, Queue: w.config.Queue}
+	select {
+	case <-w.doneCh:
+		return status
+	default:
+	}
+	now := time.Now()
+	lastPoll := unixNanoToTime(w.lastPollAt.Load())
+	busySince := unixNanoToTime(w.busySince.Load())
+	status.Running = lastPoll != nil
+	status.LastPollAt = lastPoll
+	status.BusySince = busySince
+	switch {
+	case busySince != nil:
+		status.Live = now.Sub(*busySince) <= w.config.TaskTimeout+workerLivenessGrace
+	case lastPoll != nil:
+		status.Live = now.Sub(*lastPoll) <= w.config.PollInterval+workerLivenessGrace
+	}
+	return status
+}
+
+func unixNanoToTime(ns int64) *time.Time {
+	if ns == 0 {
+		return nil
+	}
+	t := time.Unix(0, ns)
+	return &t
+}
+
 func (w *Worker) Stop() {
 	w.stopOnce.Do(func() {
 		w.stopped.Store(true)
@@ -136,17 +239,19 @@ func (w *Worker) Wait() {
 	<-w.doneCh
 }
 
-func (w *Worker) processNextTask(ctx context.Context) (err error) {
+// processNextTask runs the next task of the queue and reports whether the
+// gate held it back instead.
+func (w *Worker) processNextTask(ctx context.Context) (held bool, err error) {
 	vtSeconds := int(w.config.VisibilityTimeout.Seconds())
-	msg, err := w.client.ReadTask(ctx, vtSeconds)
+	msg, err := w.client.ReadTask(ctx, w.config.Queue, vtSeconds)
 	if err != nil {
 		if err == ErrNoRows {
-			return nil
+			return false, nil
 		}
-		return NewTaskError("Worker.processNextTask", err).Build()
+		return false, NewTaskError("Worker.processNextTask", err).Build()
 	}
 	if msg == nil {
-		return nil
+		return false, nil
 	}
 	taskLogger := w.logger.With(
 		"task_id", msg.Message.TaskID,
@@ -154,12 +259,11 @@ func (w *Worker) processNextTask(ctx context.Context) (err error) {
 		"attempt", msg.Message.Attempt,
 		"message_id", msg.MessageID,
 	)
-	taskLogger.InfoContext(ctx, "received task")
 	task, err := w.queries.GetTask(ctx, msg.Message.TaskID)
 	if err != nil {
 		taskLogger.ErrorContext(ctx, "failed to get task from database", "error", err)
-		_ = w.client.ArchiveTaskFromQueue(ctx, msg.MessageID)
-		return NewTaskError("Worker.GetTask", err).
+		_ = w.client.ArchiveTaskFromQueue(ctx, w.config.Queue, msg.MessageID)
+		return false, NewTaskError("Worker.GetTask", err).
 			WithTaskID(msg.Message.TaskID).
 			WithEntityID(msg.Message.EntityID).
 			Build()
@@ -169,10 +273,32 @@ func (w *Worker) processNextTask(ctx context.Context) (err error) {
 		"max_attempts", task.MaxAttempts,
 		"priority", task.Priority,
 	)
+	if TaskStatus(task.Status) == TaskStatusStopped {
+		taskLogger.InfoContext(ctx, "skipping stopped task")
+		_ = w.client.DeleteTaskFromQueue(ctx, w.config.Queue, msg.MessageID)
+		recordTaskOutcome(task.TaskType, TaskOutcomeSkipped, 0)
+		return false, nil
+	}
+	if w.config.Gate != nil {
+		if hold, ok := w.config.Gate(task); !ok {
+			taskLogger.DebugContext(ctx, "holding back task", "hold", hold.String())
+			recordTaskOutcome(task.TaskType, TaskOutcomeHeld, 0)
+			if err := w.client.HoldTask(ctx, w.config.Queue, msg.MessageID, hold); err != nil {
+				// the message becomes visible again after the visibility timeout
+				return false, NewTaskError("Worker.HoldTask", err).
+					WithTaskID(task.TaskID).
+					WithTaskType(task.TaskType).
+					Build()
+			}
+			w.holdGroup(ctx, taskLogger, task.TaskType, hold)
+			return true, nil
+		}
+	}
+	taskLogger.InfoContext(ctx, "received task")
 	workerIDText := pgtype.Text{String: w.workerID, Valid: true}
 	if err := w.queries.UpdateTaskToProcessing(ctx, task.TaskID, workerIDText); err != nil {
 		taskLogger.ErrorContext(ctx, "failed to update task to processing", "error", err)
-		return NewTaskError("Worker.UpdateTaskToProcessing", err).
+		return false, NewTaskError("Worker.UpdateTaskToProcessing", err).
 			WithTaskID(task.TaskID).
 			WithEntityID(task.EntityID).
 			WithTaskType(task.TaskType).
@@ -180,31 +306,53 @@ func (w *Worker) processNextTask(ctx context.Context) (err error) {
 	}
 	taskCtx, cancel := context.WithTimeout(ctx, w.config.TaskTimeout)
 	startTime := time.Now()
+	w.busySince.Store(startTime.UnixNano())
 	processingErr := w.executeHandler(taskCtx, taskLogger, task)
+	w.busySince.Store(0)
 	duration := time.Since(startTime)
 	cancel()
 	taskLogger = taskLogger.With("duration_ms", duration.Milliseconds())
 	if processingErr != nil {
 		w.handleTaskFailure(ctx, taskLogger, task, msg.MessageID, processingErr, duration)
-		return processingErr
+		return false, processingErr
 	}
 	taskLogger.InfoContext(ctx, "task completed successfully")
+	recordTaskOutcome(task.TaskType, TaskOutcomeCompleted, duration)
 	if err := w.queries.UpdateTaskToCompleted(ctx, task.TaskID); err != nil {
 		taskLogger.ErrorContext(ctx, "failed to mark task as completed", "error", err)
-		return NewTaskError("Worker.UpdateTaskToCompleted", err).
+		return false, NewTaskError("Worker.UpdateTaskToCompleted", err).
 			WithTaskID(task.TaskID).
 			WithEntityID(task.EntityID).
 			WithTaskType(task.TaskType).
 			Build()
 	}
-	if err := w.client.DeleteTaskFromQueue(ctx, msg.MessageID); err != nil {
+	if err := w.client.DeleteTaskFromQueue(ctx, w.config.Queue, msg.MessageID); err != nil {
 		taskLogger.ErrorContext(ctx, "failed to delete message from queue", "error", err)
-		return NewTaskError("Worker.DeleteTaskFromQueue", err).
+		return false, NewTaskError("Worker.DeleteTaskFromQueue", err).
 			WithTaskID(task.TaskID).
 			WithAttr("message_id", msg.MessageID).
 			Build()
 	}
-	return nil
+	return false, nil
+}
+
+// holdGroup holds the queued tasks of the task types held together with the
+// task type, so that the worker does not read them one by one while the hold
+// lasts.
+func (w *Worker) holdGroup(ctx context.Context, logger *slog.Logger, taskType string, hold time.Duration) {
+	if w.config.HoldGroup == nil {
+		return
+	}
+	taskTypes := w.config.HoldGroup(taskType)
+	if len(taskTypes) == 0 {
+		return
+	}
+	held, err := w.client.HoldQueuedTasks(ctx, w.config.Queue, taskTypes, hold)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to hold queued tasks", "error", err)
+		return
+	}
+	logger.DebugContext(ctx, "held queued tasks", "task_types", taskTypes, "held", held)
 }
 
 func (w *Worker) executeHandler(ctx context.Context, logger *slog.Logger, task db.TaskQueueTask) (err error) {
@@ -238,11 +386,13 @@ func (w *Worker) handleTaskFailure(ctx context.Context, logger *slog.Logger, tas
 		"will_retry", shouldRetry,
 	)
 	if shouldRetry {
+		recordTaskOutcome(task.TaskType, TaskOutcomeRetried, duration)
 		w.scheduleRetry(ctx, logger, task, currentAttempt, processingErr)
 	} else {
+		recordTaskOutcome(task.TaskType, TaskOutcomeDeadLettered, duration)
 		w.moveToDLQ(ctx, logger, task, currentAttempt, processingErr, duration)
 	}
-	_ = w.client.DeleteTaskFromQueue(ctx, messageID)
+	_ = w.client.DeleteTaskFromQueue(ctx, w.config.Queue, messageID)
 }
 
 func (w *Worker) scheduleRetry(ctx context.Context, logger *slog.Logger, task db.TaskQueueTask, currentAttempt int64, processingErr error) {
@@ -257,13 +407,9 @@ func (w *Worker) scheduleRetry(ctx context.Context, logger *slog.Logger, task db
 		logger.ErrorContext(ctx, "failed to update task for retry", "error", err)
 		return
 	}
-	msgID, err := w.client.EnqueueTask(ctx, task.TaskID, task.EntityID, int32(currentAttempt), retryAt)
-	if err != nil {
+	if _, err := w.client.EnqueueTask(ctx, task.TaskID); err != nil {
 		logger.ErrorContext(ctx, "failed to enqueue retry", "error", err)
-		return
 	}
-	queueMsgID := pgtype.Int8{Int64: msgID, Valid: true}
-	_ = w.queries.UpdateTaskQueueMessageId(ctx, task.TaskID, queueMsgID)
 }
 
 func (w *Worker) moveToDLQ(ctx context.Context, logger *slog.Logger, task db.TaskQueueTask, totalAttempts int64, lastErr error, duration time.Duration) {
@@ -332,6 +478,9 @@ func (w *Worker) moveToDLQ(ctx context.Context, logger *slog.Logger, task db.Tas
 	if err := w.queries.UpdateTaskToFailed(ctx, task.TaskID, lastErrorText); err != nil {
 		logger.ErrorContext(ctx, "failed to mark task as failed", "error", err)
 	}
+	if w.config.OnDeadLetter != nil {
+		w.config.OnDeadLetter(ctx, task, lastErr)
+	}
 }
 
 func (w *Worker) getDLQReason(task db.TaskQueueTask, totalAttempts int64, lastErr error) string {
@@ -374,6 +523,9 @@ func NewWorkerPool(numWorkers int, pool *pgxpool.Pool, handler TaskHandler, conf
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if config.Queue == "" {
+		config.Queue = QueueName
+	}
 	workers := make([]*Worker, numWorkers)
 	for i := range numWorkers {
 		workers[i] = NewWorker(pool, handler, config)
@@ -386,14 +538,22 @@ func NewWorkerPool(numWorkers int, pool *pgxpool.Pool, handler TaskHandler, conf
 }
 
 func (p *WorkerPool) Start(ctx context.Context) {
-	p.logger.InfoContext(ctx, "starting worker pool", "worker_count", len(p.workers))
+	p.logger.InfoContext(ctx, "starting worker pool", "queue", p.config.Queue, "worker_count", len(p.workers))
 	for _, worker := range p.workers {
 		go worker.Start(ctx)
 	}
 }
 
+func (p *WorkerPool) Statuses() []WorkerStatus {
+	statuses := make([]WorkerStatus, 0, len(p.workers))
+	for _, worker := range p.workers {
+		statuses = append(statuses, worker.Status())
+	}
+	return statuses
+}
+
 func (p *WorkerPool) Stop() {
-	p.logger.Info("stopping worker pool")
+	p.logger.Info("stopping worker pool", "queue", p.config.Queue)
 	for _, worker := range p.workers {
 		worker.Stop()
 	}
@@ -403,5 +563,5 @@ func (p *WorkerPool) Wait() {
 	for _, worker := range p.workers {
 		worker.Wait()
 	}
-	p.logger.Info("worker pool stopped")
+	p.logger.Info("worker pool stopped", "queue", p.config.Queue)
 }
@@ -0,0 +1,35 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRetryAfter parses a Retry-After header, given either as seconds or as
+// an HTTP date, into the delay from now. A date in the past is a zero delay.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+// RetryAfter returns the delay the Retry-After header of a response asks for,
+// zero when it has none.
+func RetryAfter(resp *http.Response) time.Duration {
+	delay, _ := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return delay
+}
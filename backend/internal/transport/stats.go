@@ -0,0 +1,93 @@
+package transport
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"koditon-go/internal/metrics"
+)
+
+var (
+	requestsTotal = promauto.With(metrics.Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "koditon_http_requests_total",
+		Help: "Outbound requests of the scraper clients, by source, host and status code. The code is 0 for transport errors.",
+	}, []string{"source", "host", "code"})
+	throttleWaitSeconds = promauto.With(metrics.Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "koditon_http_throttle_wait_seconds",
+		Help:    "Time outbound requests waited for a rate limit token or a concurrency slot, by source and host.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"source", "host"})
+)
+
+func recordRequest(source, host string, statusCode int, waited time.Duration) {
+	requestsTotal.WithLabelValues(source, host, strconv.Itoa(statusCode)).Inc()
+	if waited > 0 {
+		throttleWaitSeconds.WithLabelValues(source, host).Observe(waited.Seconds())
+	}
+}
+
+// HostStats summarizes the requests a source sent to a host since the
+// process started.
+type HostStats struct {
+	Source string
+	Host   string
+	// Requests counts the requests sent, Errors the ones without a response.
+	Requests    uint64
+	Errors      uint64
+	RateLimited uint64
+	// Throttled counts the requests that waited for a token or a slot, for
+	// WaitTime in total.
+	Throttled        uint64
+	WaitTime         time.Duration
+	RetryAfterPauses uint64
+	InFlight         int
+	PausedUntil      *time.Time
+}
+
+// Stats returns the host stats of the limiters, ordered by source and host.
+// Nil limiters are skipped.
+func Stats(limiters ...*Limiter) []HostStats {
+	var stats []HostStats
+	for _, l := range limiters {
+		if l != nil {
+			stats = append(stats, l.stats()...)
+		}
+	}
+	slices.SortFunc(stats, func(a, b HostStats) int {
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Host, b.Host)
+	})
+	return stats
+}
+
+func (l *Limiter) stats() []HostStats {
+	l.mu.Lock()
+	hosts := make([]*host, 0, len(l.hosts))
+	for _, h := range l.hosts {
+		hosts = append(hosts, h)
+	}
+	l.mu.Unlock()
+	now := l.now()
+	stats := make([]HostStats, 0, len(hosts))
+	for _, h := range hosts {
+		h.mu.Lock()
+		s := h.stats
+		if now.Before(h.pausedUntil) {
+			pausedUntil := h.pausedUntil
+			s.PausedUntil = &pausedUntil
+		}
+		h.mu.Unlock()
+		s.Source = l.source
+		s.Host = h.name
+		s.InFlight = len(h.slots)
+		stats = append(stats, s)
+	}
+	return stats
+}
@@ -0,0 +1,260 @@
+// Package transport implements the HTTP transport shared by the scraper
+// clients. It keeps every source polite towards the hosts it crawls: requests
+// are rate limited with a token bucket per host, the number of requests in
+// flight per host is capped and a host answering 429 or 503 with a
+// Retry-After is left alone until the time it asked for has passed.
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits apply to each host a source talks to.
+type Limits struct {
+	// RequestsPerSecond is the sustained request rate, zero disables rate
+	// limiting.
+	RequestsPerSecond float64
+	// Burst is how many requests may be sent at once after being idle.
+	Burst int
+	// MaxConcurrent caps the requests in flight, zero means no cap.
+	MaxConcurrent int
+	// MaxRetryAfter caps how long a Retry-After can pause a host.
+	MaxRetryAfter time.Duration
+}
+
+const defaultMaxRetryAfter = 10 * time.Minute
+
+// Transport is an http.RoundTripper applying the Limits of its source per
+// host.
+type Transport struct {
+	base    http.RoundTripper
+	limiter *Limiter
+}
+
+// New returns a transport limited by limiter on top of base, or on top of
+// http.DefaultTransport when base is nil. Transports sharing a limiter share
+// their hosts, so the limits hold across the clients of a source.
+func New(limiter *Limiter, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		base:    base,
+		limiter: limiter,
+	}
+}
+
+// Source returns the name of the source the transport was created for.
+func (t *Transport) Source() string {
+	return t.limiter.source
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := t.limiter
+	h := l.host(req.URL.Host)
+	ctx := req.Context()
+	start := l.now()
+	acquireBlocked, err := h.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	waitBlocked, err := h.wait(ctx, l.now)
+	if err != nil {
+		h.release()
+		return nil, err
+	}
+	var waited time.Duration
+	if acquireBlocked || waitBlocked {
+		waited = l.now().Sub(start)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		h.release()
+		h.record(0, waited)
+		recordRequest(l.source, h.name, 0, waited)
+		return nil, err
+	}
+	h.record(resp.StatusCode, waited)
+	recordRequest(l.source, h.name, resp.StatusCode, waited)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if delay, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), l.now()); ok {
+			h.pause(l.now().Add(min(delay, l.limits.MaxRetryAfter)))
+		}
+	}
+	// the slot is held until the body is read
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: h.release}
+	return resp, nil
+}
+
+// Limiter holds the per host state of a source. Every client of a source
+// must share its limiter for the limits to hold.
+type Limiter struct {
+	source string
+	limits Limits
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+// NewLimiter returns a limiter applying the limits to each host of the
+// source.
+func NewLimiter(source string, limits Limits) *Limiter {
+	if limits.Burst < 1 {
+		limits.Burst = 1
+	}
+	if limits.MaxRetryAfter <= 0 {
+		limits.MaxRetryAfter = defaultMaxRetryAfter
+	}
+	return &Limiter{
+		source: source,
+		limits: limits,
+		now:    time.Now,
+		hosts:  make(map[string]*host),
+	}
+}
+
+func (l *Limiter) host(name string) *host {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h, ok := l.hosts[name]
+	if !ok {
+		h = newHost(name, l.limits, l.now())
+		l.hosts[name] = h
+	}
+	return h
+}
+
+type host struct {
+	name   string
+	limits Limits
+	// slots is nil without a concurrency cap
+	slots chan struct{}
+
+	mu          sync.Mutex
+	tokens      float64
+	refilledAt  time.Time
+	pausedUntil time.Time
+	stats       HostStats
+}
+
+func newHost(name string, limits Limits, now time.Time) *host {
+	h := &host{
+		name:       name,
+		limits:     limits,
+		tokens:     float64(limits.Burst),
+		refilledAt: now,
+	}
+	if limits.MaxConcurrent > 0 {
+		h.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return h
+}
+
+// acquire takes a concurrency slot and reports whether it had to wait for
+// one.
+func (h *host) acquire(ctx context.Context) (bool, error) {
+	if h.slots == nil {
+		return false, nil
+	}
+	select {
+	case h.slots <- struct{}{}:
+		return false, nil
+	default:
+	}
+	select {
+	case h.slots <- struct{}{}:
+		return true, nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+func (h *host) release() {
+	if h.slots != nil {
+		<-h.slots
+	}
+}
+
+// wait blocks until the host is no longer paused and a token is available,
+// then takes the token. It reports whether it had to wait.
+func (h *host) wait(ctx context.Context, now func() time.Time) (bool, error) {
+	blocked := false
+	for {
+		delay := h.reserve(now())
+		if delay <= 0 {
+			return blocked, nil
+		}
+		blocked = true
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return blocked, ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long to wait before
+// trying again.
+func (h *host) reserve(now time.Time) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if now.Before(h.pausedUntil) {
+		return h.pausedUntil.Sub(now)
+	}
+	rate := h.limits.RequestsPerSecond
+	if rate <= 0 {
+		return 0
+	}
+	elapsed := now.Sub(h.refilledAt).Seconds()
+	h.tokens = min(float64(h.limits.Burst), h.tokens+elapsed*rate)
+	h.refilledAt = now
+	if h.tokens >= 1 {
+		h.tokens--
+		return 0
+	}
+	return time.Duration((1 - h.tokens) / rate * float64(time.Second))
+}
+
+func (h *host) pause(until time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if until.After(h.pausedUntil) {
+		h.pausedUntil = until
+	}
+	h.stats.RetryAfterPauses++
+}
+
+func (h *host) record(statusCode int, waited time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats.Requests++
+	switch {
+	case statusCode == 0:
+		h.stats.Errors++
+	case statusCode == http.StatusTooManyRequests:
+		h.stats.RateLimited++
+	}
+	if waited > 0 {
+		h.stats.Throttled++
+		h.stats.WaitTime += waited
+	}
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
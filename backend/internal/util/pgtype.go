@@ -1,6 +1,9 @@
 package util
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 func ToInt4(i *int) pgtype.Int4 {
 	if i == nil {
@@ -40,3 +43,14 @@ func ToStringPtr(s string) *string {
 	}
 	return &s
 }
+
+func ToUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+func FromUUID(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return uuid.UUID(id.Bytes).String()
+}
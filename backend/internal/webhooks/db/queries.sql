@@ -0,0 +1,118 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO public.webhook_subscriptions (
+    webhook_subscriptions_url,
+    webhook_subscriptions_secret,
+    webhook_subscriptions_event_types,
+    webhook_subscriptions_description,
+    webhook_subscriptions_enabled
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM public.webhook_subscriptions
+WHERE webhook_subscriptions_id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM public.webhook_subscriptions
+ORDER BY webhook_subscriptions_created_at DESC, webhook_subscriptions_id
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: UpdateWebhookSubscription :one
+UPDATE public.webhook_subscriptions
+SET webhook_subscriptions_url = $2,
+    webhook_subscriptions_event_types = $3,
+    webhook_subscriptions_description = $4,
+    webhook_subscriptions_enabled = $5,
+    webhook_subscriptions_updated_at = now()
+WHERE webhook_subscriptions_id = $1
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM public.webhook_subscriptions
+WHERE webhook_subscriptions_id = $1;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+SELECT * FROM public.webhook_subscriptions
+WHERE webhook_subscriptions_enabled
+    AND (
+        cardinality(webhook_subscriptions_event_types) = 0
+        OR sqlc.arg('event_type')::text = ANY(webhook_subscriptions_event_types)
+    )
+ORDER BY webhook_subscriptions_created_at, webhook_subscriptions_id;
+
+-- name: CreateWebhookDelivery :one
+SELECT public.fnc__create_webhook_delivery(
+    sqlc.arg('subscription_id')::uuid,
+    sqlc.arg('event_id')::uuid,
+    sqlc.arg('event_type')::text,
+    sqlc.arg('payload')::jsonb,
+    sqlc.arg('max_attempts')::int
+)::uuid AS webhook_deliveries_id;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM public.webhook_deliveries
+WHERE webhook_deliveries_id = $1;
+
+-- name: GetWebhookDeliveryTarget :one
+SELECT
+    d.webhook_deliveries_id,
+    d.webhook_deliveries_event_id,
+    d.webhook_deliveries_event_type,
+    d.webhook_deliveries_payload,
+    d.webhook_deliveries_status,
+    d.webhook_deliveries_attempts,
+    s.webhook_subscriptions_url,
+    s.webhook_subscriptions_secret,
+    s.webhook_subscriptions_enabled
+FROM public.webhook_deliveries d
+JOIN public.webhook_subscriptions s ON s.webhook_subscriptions_id = d.webhook_deliveries_subscription_id
+WHERE d.webhook_deliveries_id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM public.webhook_deliveries
+WHERE webhook_deliveries_subscription_id = sqlc.arg('subscription_id')
+    AND (sqlc.narg('status')::text IS NULL OR webhook_deliveries_status = sqlc.narg('status')::text)
+    AND (sqlc.narg('event_type')::text IS NULL OR webhook_deliveries_event_type = sqlc.narg('event_type')::text)
+ORDER BY webhook_deliveries_created_at DESC, webhook_deliveries_id
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE public.webhook_deliveries
+SET webhook_deliveries_status = 'delivered',
+    webhook_deliveries_attempts = webhook_deliveries_attempts + 1,
+    webhook_deliveries_response_status = sqlc.arg('response_status'),
+    webhook_deliveries_last_error = NULL,
+    webhook_deliveries_delivered_at = now()
+WHERE webhook_deliveries_id = sqlc.arg('id');
+
+-- name: MarkWebhookDeliveryAttemptFailed :exec
+UPDATE public.webhook_deliveries
+SET webhook_deliveries_attempts = webhook_deliveries_attempts + 1,
+    webhook_deliveries_response_status = sqlc.narg('response_status'),
+    webhook_deliveries_last_error = sqlc.arg('last_error')::text
+WHERE webhook_deliveries_id = sqlc.arg('id');
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE public.webhook_deliveries
+SET webhook_deliveries_status = 'failed',
+    webhook_deliveries_last_error = sqlc.arg('last_error')::text
+WHERE webhook_deliveries_id = sqlc.arg('id')
+    AND webhook_deliveries_status <> 'delivered';
+
+-- name: InsertWebhookDeliveryAttempt :exec
+INSERT INTO public.webhook_delivery_attempts (
+    webhook_delivery_attempts_delivery_id,
+    webhook_delivery_attempts_attempt,
+    webhook_delivery_attempts_response_status,
+    webhook_delivery_attempts_error,
+    webhook_delivery_attempts_duration_ms
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM public.webhook_delivery_attempts
+WHERE webhook_delivery_attempts_delivery_id = $1
+ORDER BY webhook_delivery_attempts_attempt, webhook_delivery_attempts_id;
@@ -0,0 +1,45 @@
+CREATE TABLE public.webhook_subscriptions (
+    webhook_subscriptions_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    webhook_subscriptions_url text NOT NULL,
+    webhook_subscriptions_secret text NOT NULL,
+    webhook_subscriptions_event_types text[] NOT NULL DEFAULT '{}',
+    webhook_subscriptions_description text,
+    webhook_subscriptions_enabled bool NOT NULL DEFAULT true,
+    webhook_subscriptions_created_at timestamptz NOT NULL DEFAULT now(),
+    webhook_subscriptions_updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (webhook_subscriptions_id)
+);
+
+CREATE TABLE public.webhook_deliveries (
+    webhook_deliveries_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    webhook_deliveries_subscription_id uuid NOT NULL,
+    webhook_deliveries_event_id uuid NOT NULL,
+    webhook_deliveries_event_type text NOT NULL,
+    webhook_deliveries_payload jsonb NOT NULL,
+    webhook_deliveries_status text NOT NULL DEFAULT 'pending',
+    webhook_deliveries_attempts int4 NOT NULL DEFAULT 0,
+    webhook_deliveries_response_status int4,
+    webhook_deliveries_last_error text,
+    webhook_deliveries_created_at timestamptz NOT NULL DEFAULT now(),
+    webhook_deliveries_delivered_at timestamptz,
+    PRIMARY KEY (webhook_deliveries_id)
+);
+
+CREATE TABLE public.webhook_delivery_attempts (
+    webhook_delivery_attempts_id bigserial NOT NULL,
+    webhook_delivery_attempts_delivery_id uuid NOT NULL,
+    webhook_delivery_attempts_attempt int4 NOT NULL,
+    webhook_delivery_attempts_response_status int4,
+    webhook_delivery_attempts_error text,
+    webhook_delivery_attempts_duration_ms int4 NOT NULL,
+    webhook_delivery_attempts_attempted_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (webhook_delivery_attempts_id)
+);
+
+CREATE OR REPLACE FUNCTION public.fnc__create_webhook_delivery(
+    p_subscription_id uuid,
+    p_event_id uuid,
+    p_event_type text,
+    p_payload jsonb,
+    p_max_attempts int
+) RETURNS uuid AS $$ BEGIN RETURN NULL; END; $$ LANGUAGE plpgsql;
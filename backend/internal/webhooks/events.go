@@ -0,0 +1,59 @@
+package webhooks
+
+import "time"
+
+// Event types subscriptions can filter on.
+const (
+	EventAdCreated        = "ad.created"
+	EventAdPriceChanged   = "ad.price_changed"
+	EventBuildingSynced   = "building.synced"
+	EventTaskDeadLettered = "task.dead_lettered"
+)
+
+// EventSavedSearchMatched is only sent to the subscription a saved search
+// delivers its alerts to, see Service.PublishTo.
+const EventSavedSearchMatched = "saved_search.matched"
+
+// EventTypes lists every event type that is published.
+var EventTypes = []string{
+	EventAdCreated,
+	EventAdPriceChanged,
+	EventBuildingSynced,
+	EventTaskDeadLettered,
+}
+
+// Event is the JSON body posted to subscribers. ID is shared by the
+// deliveries of the same event to different subscriptions.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// AdEventData is the data of ad.created and ad.price_changed events.
+type AdEventData struct {
+	ListingID             string   `json:"listing_id"`
+	Source                string   `json:"source"`
+	ExternalID            string   `json:"external_id"`
+	Price                 *float64 `json:"price,omitempty"`
+	DebtFreePrice         *float64 `json:"debt_free_price,omitempty"`
+	PreviousPrice         *float64 `json:"previous_price,omitempty"`
+	PreviousDebtFreePrice *float64 `json:"previous_debt_free_price,omitempty"`
+	Status                *string  `json:"status,omitempty"`
+}
+
+// BuildingEventData is the data of building.synced events.
+type BuildingEventData struct {
+	Source     string `json:"source"`
+	BuildingID string `json:"building_id"`
+}
+
+// TaskEventData is the data of task.dead_lettered events.
+type TaskEventData struct {
+	TaskID   int64  `json:"task_id"`
+	TaskType string `json:"task_type"`
+	EntityID string `json:"entity_id"`
+	Attempts int64  `json:"attempts"`
+	Error    string `json:"error"`
+}
@@ -0,0 +1,227 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"koditon-go/internal/transport"
+	"koditon-go/internal/util"
+	"koditon-go/internal/webhooks/db"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+
+	// DeliveryEntityType is the task queue entity type of deliveries.
+	DeliveryEntityType = "webhook_delivery"
+	// DeliveryMaxAttempts is how many times the task queue tries a delivery
+	// before dead lettering it.
+	DeliveryMaxAttempts = 8
+
+	deliveryTimeout = 10 * time.Second
+	// maxErrorBody caps how much of a failed response ends up in the
+	// delivery log.
+	maxErrorBody = 512
+	userAgent    = "koditon-webhooks/1.0"
+)
+
+// ErrDeliveryNotFound is returned for deliveries that no longer exist, e.g.
+// because their subscription was deleted.
+var ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+// HTTPStatusError is a non-2xx response from a subscriber.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is the delay the response asked for, zero when it had no
+	// Retry-After.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("webhook: HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+type Service struct {
+	queries    *db.Queries
+	httpClient *http.Client
+}
+
+func NewService(dbtx db.DBTX) *Service {
+	return &Service{
+		queries:    db.New(dbtx),
+		httpClient: newHTTPClient(),
+	}
+}
+
+// Publish records a delivery of the event for each enabled subscription to
+// its type and queues them. Each delivery is recorded together with its task.
+// It returns the number of deliveries queued.
+func (s *Service) Publish(ctx context.Context, eventType string, data any) (int, error) {
+	subscriptions, err := s.queries.ListWebhookSubscriptionsForEvent(ctx, eventType)
+	if err != nil {
+		return 0, fmt.Errorf("list subscriptions for %s: %w", eventType, err)
+	}
+	if len(subscriptions) == 0 {
+		return 0, nil
+	}
+	eventID, payload, err := newEventPayload(eventType, data)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	queued := 0
+	for _, subscription := range subscriptions {
+		if _, err := s.queries.CreateWebhookDelivery(ctx, &db.CreateWebhookDeliveryParams{
+			SubscriptionID: subscription.WebhookSubscriptionsID,
+			EventID:        util.ToUUID(eventID),
+			EventType:      eventType,
+			Payload:        payload,
+			MaxAttempts:    DeliveryMaxAttempts,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("create delivery (subscription_id=%s): %w", util.FromUUID(subscription.WebhookSubscriptionsID), err))
+			continue
+		}
+		queued++
+	}
+	return queued, errors.Join(errs...)
+}
+
+// PublishTo records and queues a delivery of the event for one subscription,
+// whatever event types it subscribes to. It is for events that belong to a
+// single subscriber, such as the matches of a saved search.
+func (s *Service) PublishTo(ctx context.Context, subscriptionID uuid.UUID, eventType string, data any) error {
+	eventID, payload, err := newEventPayload(eventType, data)
+	if err != nil {
+		return err
+	}
+	if _, err := s.queries.CreateWebhookDelivery(ctx, &db.CreateWebhookDeliveryParams{
+		SubscriptionID: util.ToUUID(subscriptionID),
+		EventID:        util.ToUUID(eventID),
+		EventType:      eventType,
+		Payload:        payload,
+		MaxAttempts:    DeliveryMaxAttempts,
+	}); err != nil {
+		return fmt.Errorf("create delivery (subscription_id=%s): %w", subscriptionID, err)
+	}
+	return nil
+}
+
+func newEventPayload(eventType string, data any) (uuid.UUID, []byte, error) {
+	eventID := uuid.New()
+	payload, err := json.Marshal(Event{
+		ID:        eventID.String(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+	return eventID, payload, nil
+}
+
+// Deliver posts a delivery to its subscriber and logs the attempt. Delivered
+// deliveries are skipped and deliveries of disabled subscriptions are marked
+// failed without sending. The returned error is an *HTTPStatusError for
+// non-2xx responses.
+func (s *Service) Deliver(ctx context.Context, deliveryID uuid.UUID) error {
+	id := util.ToUUID(deliveryID)
+	target, err := s.queries.GetWebhookDeliveryTarget(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDeliveryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get delivery (delivery_id=%s): %w", deliveryID, err)
+	}
+	switch {
+	case target.WebhookDeliveriesStatus == DeliveryStatusDelivered:
+		return nil
+	case !target.WebhookSubscriptionsEnabled:
+		return s.MarkDeliveryFailed(ctx, deliveryID, "subscription disabled")
+	}
+	start := time.Now()
+	statusCode, sendErr := s.send(ctx, target)
+	attempt := &db.InsertWebhookDeliveryAttemptParams{
+		WebhookDeliveryAttemptsDeliveryID:     id,
+		WebhookDeliveryAttemptsAttempt:        target.WebhookDeliveriesAttempts + 1,
+		WebhookDeliveryAttemptsResponseStatus: statusCode,
+		WebhookDeliveryAttemptsDurationMs:     int32(time.Since(start).Milliseconds()),
+	}
+	if sendErr != nil {
+		attempt.WebhookDeliveryAttemptsError = util.ToStringPtr(sendErr.Error())
+	}
+	if err := s.queries.InsertWebhookDeliveryAttempt(ctx, attempt); err != nil {
+		return fmt.Errorf("log delivery attempt (delivery_id=%s): %w", deliveryID, err)
+	}
+	if sendErr != nil {
+		if err := s.queries.MarkWebhookDeliveryAttemptFailed(ctx, &db.MarkWebhookDeliveryAttemptFailedParams{
+			ResponseStatus: statusCode,
+			LastError:      sendErr.Error(),
+			ID:             id,
+		}); err != nil {
+			return fmt.Errorf("mark delivery attempt failed (delivery_id=%s): %w", deliveryID, err)
+		}
+		return sendErr
+	}
+	if err := s.queries.MarkWebhookDeliveryDelivered(ctx, &db.MarkWebhookDeliveryDeliveredParams{
+		ResponseStatus: statusCode,
+		ID:             id,
+	}); err != nil {
+		return fmt.Errorf("mark delivery delivered (delivery_id=%s): %w", deliveryID, err)
+	}
+	return nil
+}
+
+// MarkDeliveryFailed gives up on a delivery that is not delivered yet.
+func (s *Service) MarkDeliveryFailed(ctx context.Context, deliveryID uuid.UUID, reason string) error {
+	if err := s.queries.MarkWebhookDeliveryFailed(ctx, &db.MarkWebhookDeliveryFailedParams{
+		LastError: reason,
+		ID:        util.ToUUID(deliveryID),
+	}); err != nil {
+		return fmt.Errorf("mark delivery failed (delivery_id=%s): %w", deliveryID, err)
+	}
+	return nil
+}
+
+// send posts the payload and returns the response status, nil when no
+// response was received.
+func (s *Service) send(ctx context.Context, target db.GetWebhookDeliveryTargetRow) (*int32, error) {
+	body := []byte(target.WebhookDeliveriesPayload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.WebhookSubscriptionsUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, target.WebhookDeliveriesEventType)
+	req.Header.Set(DeliveryHeader, util.FromUUID(target.WebhookDeliveriesID))
+	req.Header.Set(SignatureHeader, Sign(target.WebhookSubscriptionsSecret, time.Now(), body))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	statusCode := int32(resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &statusCode, &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(snippet)),
+			RetryAfter: transport.RetryAfter(resp),
+		}
+	}
+	return &statusCode, nil
+}
@@ -0,0 +1,43 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex signature>". The
+	// signature is the HMAC-SHA256 of "<t>.<body>" keyed with the secret of
+	// the subscription, receivers should recompute it and compare in constant
+	// time.
+	SignatureHeader = "X-Koditon-Signature"
+	EventHeader     = "X-Koditon-Event"
+	DeliveryHeader  = "X-Koditon-Delivery"
+
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+)
+
+// Sign returns the SignatureHeader value for a body sent at the given time.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// GenerateSecret returns a random signing secret for a new subscription.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
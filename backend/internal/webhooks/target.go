@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrForbiddenTarget is returned when a webhook URL leads to an address that
+// is not public, such as loopback, private networks or cloud metadata.
+var ErrForbiddenTarget = errors.New("webhook target is not a public address")
+
+// cgnatPrefix is the shared address space of carrier-grade NAT, which
+// netip.Addr.IsPrivate does not cover.
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// ValidateURL checks that a subscription URL is an http or https URL whose
+// host only resolves to public addresses.
+func ValidateURL(ctx context.Context, raw string) error {
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return errors.New("url must be an http or https URL")
+	}
+	if target.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", target.Hostname())
+	if err != nil {
+		return fmt.Errorf("url host %s cannot be resolved", target.Hostname())
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("url host %s resolves to %s: %w", target.Hostname(), addr, ErrForbiddenTarget)
+		}
+	}
+	return nil
+}
+
+// newHTTPClient returns the delivery client. The address is checked again
+// when connecting, so that a host that resolves differently after
+// ValidateURL, or a redirect, cannot reach an internal address.
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("connect to %s: %w", address, ErrForbiddenTarget)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: deliveryTimeout, Transport: transport}
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnatPrefix.Contains(addr)
+}
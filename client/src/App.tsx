@@ -159,7 +159,8 @@ function App() {
               {neighborhoods.length === 0 && <option>No neighborhoods</option>}
               {neighborhoods.map((neighborhood) => (
                 <option key={neighborhood.id} value={neighborhood.id}>
-                  {neighborhood.name} ({neighborhood.postal_code})
+                  {neighborhood.name}
+                  {neighborhood.postal_code && ` (${neighborhood.postal_code.code})`}
                 </option>
               ))}
             </select>